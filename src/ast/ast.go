@@ -3,6 +3,7 @@ package ast
 import (
 	"bytes"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"../token"
@@ -48,13 +49,73 @@ type LetStatement struct {
 	Token token.Token
 	Name  *Identifier
 	Value Expression
+	// Exported bindings can be imported by other modules
+	Exported bool
 }
 
 // ConstStatement :
 type ConstStatement struct {
+	Token    token.Token
+	Name     *Identifier
+	Value    Expression
+	Exported bool
+}
+
+// ImportStatement : `import { a, b } from "./utils.tr"`
+type ImportStatement struct {
 	Token token.Token
-	Name  *Identifier
-	Value Expression
+	Names []*Identifier
+	Path  string
+}
+
+// Type : a type annotation, `numeric`, or `numeric[]` for a vector of them; Parameters are the types given to a
+// generic type, as `double` in `Option[double]`
+type Type struct {
+	Token      token.Token
+	Name       string
+	Parameters []*Type
+	Vector     bool
+}
+
+// TypeParameter : `T`, or `T: numeric` when any type given to it has to be one of the bound
+type TypeParameter struct {
+	Token token.Token
+	Name  string
+	Bound *Type
+}
+
+// DeclareStatement : `declare function mean(x: numeric[], na.rm: logical = FALSE): double` tells the signature of a
+// function written elsewhere, in R; Types and Result are nil where the declaration leaves them out, and generic
+// functions name their TypeParameters, as in `declare function head<T>(xs: T[]): T`
+type DeclareStatement struct {
+	Token          token.Token
+	Name           *Identifier
+	TypeParameters []*TypeParameter
+	Parameters     []*Identifier
+	Types          []*Type
+	Defaults       []Expression
+	Result         *Type
+}
+
+// TypeStatement : `type Shape = Circle(r: double) | Rect(w: double, h: double)`, a type made of alternatives, each
+// one built by the constructor named after it, or `type Numbers = double[]`, an Alias standing for another type
+type TypeStatement struct {
+	Token          token.Token
+	Name           *Identifier
+	TypeParameters []*TypeParameter
+	Constructors   []*Constructor
+	Alias          *Type
+	// Exported types can have their constructors imported by other modules
+	Exported bool
+}
+
+// Constructor : `Rect(w: double, h: double)`, an alternative of a type along with its fields, whose types are nil
+// where they are left out
+type Constructor struct {
+	Token  token.Token
+	Name   *Identifier
+	Fields []*Identifier
+	Types  []*Type
 }
 
 // ReturnStatement :
@@ -76,6 +137,12 @@ type IntegerLiteral struct {
 	Value int64
 }
 
+// DoubleLiteral :
+type DoubleLiteral struct {
+	Token token.Token
+	Value float64
+}
+
 // PrefixExpression :
 type PrefixExpression struct {
 	Token    token.Token
@@ -97,6 +164,16 @@ type Boolean struct {
 	Value bool
 }
 
+// NotAvailable : R's NA
+type NotAvailable struct {
+	Token token.Token
+}
+
+// Null : R's NULL, which only R source has
+type Null struct {
+	Token token.Token
+}
+
 // BlockStatement :
 type BlockStatement struct {
 	Token      token.Token
@@ -121,10 +198,165 @@ type IdentifierTypes struct {
 type FunctionLiteral struct {
 	Token token.Token
 	// IdentifierTypes contains the parameters and return values types
-	Contract   []*IdentifierTypes
-	Parameters []*Identifier
-	Body       *BlockStatement
-	Name       string
+	Contract       []*IdentifierTypes
+	TypeParameters []*TypeParameter
+	Parameters     []*Identifier
+	// Defaults holds, for every parameter, the expression giving its default value, nil when it has none
+	Defaults []Expression
+	// Types and Result are the annotations, as in `function<T>(xs: T[]): T`, nil where they are left out
+	Types  []*Type
+	Result *Type
+	Body   *BlockStatement
+	Name   string
+}
+
+// Annotated : whether the function tells the type of anything, so it can be checked as a declared one
+func (fl *FunctionLiteral) Annotated() bool {
+	if 0 != len(fl.TypeParameters) || nil != fl.Result {
+		return true
+	}
+
+	for _, annotation := range fl.Types {
+		if nil != annotation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Type : the type annotation of the parameter at index, nil when it has none
+func (fl *FunctionLiteral) Type(index int) *Type {
+	if index >= len(fl.Types) {
+		return nil
+	}
+
+	return fl.Types[index]
+}
+
+// Default : the default value of the parameter at index, nil when it has none
+func (fl *FunctionLiteral) Default(index int) Expression {
+	if index >= len(fl.Defaults) {
+		return nil
+	}
+
+	return fl.Defaults[index]
+}
+
+// DefaultOrder : the parameters having a default value, each one after those its default value names, so the values
+// can be worked out one after the other just as R does lazily; for those naming each other, the first one named is
+// worked out last
+func DefaultOrder(parameters []*Identifier, defaults []Expression) []int {
+	order := []int{}
+	visited := make([]bool, len(parameters))
+
+	var visit func(index int)
+
+	visit = func(index int) {
+		if visited[index] {
+			return
+		}
+
+		visited[index] = true
+		named := map[string]bool{}
+
+		Inspect(defaults[index], func(node Node) bool {
+			if identifier, ok := node.(*Identifier); ok {
+				named[identifier.Value] = true
+			}
+
+			return true
+		})
+
+		for other, parameter := range parameters {
+			if other != index && other < len(defaults) && nil != defaults[other] && named[parameter.Value] {
+				visit(other)
+			}
+		}
+
+		order = append(order, index)
+	}
+
+	for index := range parameters {
+		if index < len(defaults) && nil != defaults[index] {
+			visit(index)
+		}
+	}
+
+	return order
+}
+
+// Inspect : calls visit on the node and then on every node inside of it, skipping the insides of those visit returns
+// false for
+func Inspect(node Node, visit func(Node) bool) {
+	if nil == node || reflect.ValueOf(node).IsNil() || !visit(node) {
+		return
+	}
+
+	children := []Node{}
+
+	switch node := node.(type) {
+	case *Program:
+		for _, statement := range node.Statements {
+			children = append(children, statement)
+		}
+	case *BlockStatement:
+		for _, statement := range node.Statements {
+			children = append(children, statement)
+		}
+	case *LetStatement:
+		children = append(children, node.Name, node.Value)
+	case *ConstStatement:
+		children = append(children, node.Name, node.Value)
+	case *ReturnStatement:
+		children = append(children, node.ReturnValue)
+	case *ExpressionStatement:
+		children = append(children, node.Expression)
+	case *PrefixExpression:
+		children = append(children, node.Right)
+	case *InfixExpression:
+		children = append(children, node.Left, node.Right)
+	case *ConditionalExpression:
+		children = append(children, node.Condition, node.Consequence, node.Alternative)
+	case *FunctionLiteral:
+		for _, value := range node.Defaults {
+			children = append(children, value)
+		}
+
+		children = append(children, node.Body)
+	case *CallExpression:
+		children = append(children, node.Function)
+
+		for _, parameter := range node.Parameters {
+			children = append(children, parameter)
+		}
+	case *NamedArgument:
+		children = append(children, node.Value)
+	case *ArrayLiteral:
+		for _, element := range node.Elements {
+			children = append(children, element)
+		}
+	case *IndexExpression:
+		children = append(children, node.Left, node.Index, node.Column)
+	case *MatchExpression:
+		children = append(children, node.Subject)
+
+		for _, arm := range node.Arms {
+			children = append(children, arm.Body)
+		}
+	case *PointFreeExpression:
+		for _, identifier := range node.ToCompose {
+			children = append(children, identifier)
+		}
+
+		for _, parameter := range node.Parameters {
+			children = append(children, parameter)
+		}
+	}
+
+	for _, child := range children {
+		Inspect(child, visit)
+	}
 }
 
 // CallExpression :
@@ -134,6 +366,13 @@ type CallExpression struct {
 	Parameters []Expression
 }
 
+// NamedArgument : `name = value`, as given to a call
+type NamedArgument struct {
+	Token token.Token
+	Name  string
+	Value Expression
+}
+
 // StringLiteral :
 type StringLiteral struct {
 	Token token.Token
@@ -146,11 +385,36 @@ type ArrayLiteral struct {
 	Elements []Expression
 }
 
-// IndexExpression :
+// IndexExpression : Matrix flags the `x[rows, columns]` form, where both Index and Column may be left empty
 type IndexExpression struct {
-	Token token.Token
-	Left  Expression
-	Index Expression
+	Token  token.Token
+	Left   Expression
+	Index  Expression
+	Column Expression
+	Matrix bool
+}
+
+// MatchExpression : `match (s) { Circle(r) -> r * r, _ -> 0 }`, the body of the first arm whose pattern fits the
+// value being evaluated
+type MatchExpression struct {
+	Token   token.Token
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+// MatchArm : the pattern is a constructor along with the names its fields are bound to, `_` leaving one out; Fields
+// is nil when the pattern is written without parentheses, so it fits whatever fields the value has, and a pattern
+// named `_` fits any value
+type MatchArm struct {
+	Token       token.Token
+	Constructor *Identifier
+	Fields      []*Identifier
+	Body        *BlockStatement
+}
+
+// IsWildcard : whether the arm fits any value
+func (ma *MatchArm) IsWildcard() bool {
+	return "_" == ma.Constructor.Value
 }
 
 // PointFreeExpression :
@@ -200,6 +464,10 @@ func (p *Program) TokenLiteral() string {
 func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
+	if ls.Exported {
+		out.WriteString("export ")
+	}
+
 	out.WriteString(ls.TokenLiteral() + " ")
 	out.WriteString(ls.Name.String())
 	out.WriteString(" <- ")
@@ -226,6 +494,10 @@ func (ls *LetStatement) TokenLiteral() string {
 func (cs *ConstStatement) String() string {
 	var out bytes.Buffer
 
+	if cs.Exported {
+		out.WriteString("export ")
+	}
+
 	out.WriteString(cs.TokenLiteral() + " ")
 	out.WriteString(cs.Name.String())
 	out.WriteString(" <- ")
@@ -245,6 +517,163 @@ func (cs *ConstStatement) TokenLiteral() string {
 	return cs.Token.Literal
 }
 
+// String :
+func (is *ImportStatement) String() string {
+	names := []string{}
+
+	for _, name := range is.Names {
+		names = append(names, name.String())
+	}
+
+	return fmt.Sprintf("import { %s } from %q", strings.Join(names, ", "), is.Path)
+}
+
+// statementNode :
+func (is *ImportStatement) statementNode() {}
+
+// TokenLiteral :
+func (is *ImportStatement) TokenLiteral() string {
+	return is.Token.Literal
+}
+
+// String :
+func (t *Type) String() string {
+	name := t.Name
+
+	if 0 != len(t.Parameters) {
+		parameters := make([]string, len(t.Parameters))
+
+		for index, parameter := range t.Parameters {
+			parameters[index] = parameter.String()
+		}
+
+		name += "[" + strings.Join(parameters, ", ") + "]"
+	}
+
+	if t.Vector {
+		return name + "[]"
+	}
+
+	return name
+}
+
+// TokenLiteral :
+func (t *Type) TokenLiteral() string {
+	return t.Token.Literal
+}
+
+// String :
+func (tp *TypeParameter) String() string {
+	if nil == tp.Bound {
+		return tp.Name
+	}
+
+	return tp.Name + ": " + tp.Bound.String()
+}
+
+// TypeParameters : `<T, U: numeric>`, nothing when there are none
+func TypeParameters(parameters []*TypeParameter) string {
+	if 0 == len(parameters) {
+		return ""
+	}
+
+	names := make([]string, len(parameters))
+
+	for index, parameter := range parameters {
+		names[index] = parameter.String()
+	}
+
+	return "<" + strings.Join(names, ", ") + ">"
+}
+
+// String :
+func (ds *DeclareStatement) String() string {
+	parameters := []string{}
+
+	for index, parameter := range ds.Parameters {
+		declared := parameter.String()
+
+		if nil != ds.Types[index] {
+			declared += ": " + ds.Types[index].String()
+		}
+
+		if nil != ds.Defaults[index] {
+			declared += " = " + ds.Defaults[index].String()
+		}
+
+		parameters = append(parameters, declared)
+	}
+
+	declared := fmt.Sprintf("declare function %s%s(%s)", ds.Name, TypeParameters(ds.TypeParameters), strings.Join(parameters, ", "))
+
+	if nil != ds.Result {
+		declared += ": " + ds.Result.String()
+	}
+
+	return declared
+}
+
+// statementNode :
+func (ds *DeclareStatement) statementNode() {}
+
+// TokenLiteral :
+func (ds *DeclareStatement) TokenLiteral() string {
+	return ds.Token.Literal
+}
+
+// String :
+func (ts *TypeStatement) String() string {
+	constructors := make([]string, len(ts.Constructors))
+
+	for index, constructor := range ts.Constructors {
+		constructors[index] = constructor.String()
+	}
+
+	declared := fmt.Sprintf("type %s%s = %s", ts.Name, TypeParameters(ts.TypeParameters), strings.Join(constructors, " | "))
+
+	if nil != ts.Alias {
+		declared = fmt.Sprintf("type %s%s = %s", ts.Name, TypeParameters(ts.TypeParameters), ts.Alias)
+	}
+
+	if ts.Exported {
+		return "export " + declared
+	}
+
+	return declared
+}
+
+// statementNode :
+func (ts *TypeStatement) statementNode() {}
+
+// TokenLiteral :
+func (ts *TypeStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+
+// String :
+func (c *Constructor) String() string {
+	if nil == c.Fields {
+		return c.Name.String()
+	}
+
+	fields := make([]string, len(c.Fields))
+
+	for index, field := range c.Fields {
+		fields[index] = field.String()
+
+		if nil != c.Types[index] {
+			fields[index] += ": " + c.Types[index].String()
+		}
+	}
+
+	return fmt.Sprintf("%s(%s)", c.Name, strings.Join(fields, ", "))
+}
+
+// TokenLiteral :
+func (c *Constructor) TokenLiteral() string {
+	return c.Token.Literal
+}
+
 // String :
 func (rs *ReturnStatement) String() string {
 	var out bytes.Buffer
@@ -296,6 +725,19 @@ func (il *IntegerLiteral) String() string {
 	return il.Token.Literal
 }
 
+// expressionNode :
+func (dl *DoubleLiteral) expressionNode() {}
+
+// TokenLiteral :
+func (dl *DoubleLiteral) TokenLiteral() string {
+	return dl.Token.Literal
+}
+
+// String :
+func (dl *DoubleLiteral) String() string {
+	return dl.Token.Literal
+}
+
 // expressionNode :
 func (pe *PrefixExpression) expressionNode() {}
 
@@ -350,6 +792,32 @@ func (b *Boolean) String() string {
 	return b.Token.Literal
 }
 
+// expressionNode :
+func (na *NotAvailable) expressionNode() {}
+
+// TokenLiteral :
+func (na *NotAvailable) TokenLiteral() string {
+	return na.Token.Literal
+}
+
+// String :
+func (na *NotAvailable) String() string {
+	return na.Token.Literal
+}
+
+// expressionNode :
+func (n *Null) expressionNode() {}
+
+// TokenLiteral :
+func (n *Null) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+// String :
+func (n *Null) String() string {
+	return n.Token.Literal
+}
+
 // expressionNode :
 func (bs *BlockStatement) expressionNode() {}
 
@@ -408,8 +876,18 @@ func (fl *FunctionLiteral) String() string {
 
 	parameters := []string{}
 
-	for _, p := range fl.Parameters {
-		parameters = append(parameters, p.String())
+	for index, p := range fl.Parameters {
+		parameter := p.String()
+
+		if annotation := fl.Type(index); nil != annotation {
+			parameter += ": " + annotation.String()
+		}
+
+		if value := fl.Default(index); nil != value {
+			parameter += " = " + value.String()
+		}
+
+		parameters = append(parameters, parameter)
 	}
 
 	out.WriteString(fl.TokenLiteral())
@@ -418,9 +896,16 @@ func (fl *FunctionLiteral) String() string {
 		out.WriteString(fmt.Sprintf("<%s>", fl.Name))
 	}
 
+	out.WriteString(TypeParameters(fl.TypeParameters))
 	out.WriteString("(")
 	out.WriteString(strings.Join(parameters, ", "))
-	out.WriteString(") ")
+	out.WriteString(")")
+
+	if nil != fl.Result {
+		out.WriteString(": " + fl.Result.String())
+	}
+
+	out.WriteString(" ")
 	out.WriteString(fl.Body.String())
 
 	return out.String()
@@ -452,6 +937,19 @@ func (ce *CallExpression) String() string {
 	return out.String()
 }
 
+// expressionNode :
+func (na *NamedArgument) expressionNode() {}
+
+// TokenLiteral :
+func (na *NamedArgument) TokenLiteral() string {
+	return na.Token.Literal
+}
+
+// String :
+func (na *NamedArgument) String() string {
+	return na.Name + " = " + na.Value.String()
+}
+
 // expressionNode :
 func (sl *StringLiteral) expressionNode() {}
 
@@ -505,12 +1003,65 @@ func (ie *IndexExpression) String() string {
 	out.WriteString("(")
 	out.WriteString(ie.Left.String())
 	out.WriteString("[")
-	out.WriteString(ie.Index.String())
+
+	if nil != ie.Index {
+		out.WriteString(ie.Index.String())
+	}
+
+	if ie.Matrix {
+		out.WriteString(", ")
+
+		if nil != ie.Column {
+			out.WriteString(ie.Column.String())
+		}
+	}
+
 	out.WriteString("])")
 
 	return out.String()
 }
 
+// expressionNode :
+func (me *MatchExpression) expressionNode() {}
+
+// TokenLiteral :
+func (me *MatchExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+
+// String :
+func (me *MatchExpression) String() string {
+	arms := make([]string, len(me.Arms))
+
+	for index, arm := range me.Arms {
+		arms[index] = arm.String()
+	}
+
+	return fmt.Sprintf("match (%s) { %s }", me.Subject, strings.Join(arms, ", "))
+}
+
+// String :
+func (ma *MatchArm) String() string {
+	pattern := ma.Constructor.String()
+
+	if nil != ma.Fields {
+		fields := make([]string, len(ma.Fields))
+
+		for index, field := range ma.Fields {
+			fields[index] = field.String()
+		}
+
+		pattern += "(" + strings.Join(fields, ", ") + ")"
+	}
+
+	return pattern + " -> " + ma.Body.String()
+}
+
+// TokenLiteral :
+func (ma *MatchArm) TokenLiteral() string {
+	return ma.Token.Literal
+}
+
 // expressionNode :
 func (pf *PointFreeExpression) expressionNode() {}
 
@@ -0,0 +1,1210 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"../ast"
+	"../module"
+	"../object"
+	"../token"
+)
+
+// Shape : the dimensions of a matrix known before the program runs
+type Shape struct {
+	Rows    int
+	Columns int
+}
+
+// String : as R reports dimensions in its errors
+func (s Shape) String() string {
+	return fmt.Sprintf("%dx%d", s.Rows, s.Columns)
+}
+
+// shaping : the builtins whose result has a shape that can be worked out from their parameters
+var shaping = map[string]bool{
+	"matrix": true,
+	"t":      true,
+	"diag":   true,
+	"solve":  true,
+}
+
+// optional : the builtins making and handling Option and Result, whose types are followed unless a binding hides them
+var optional = map[string]bool{
+	"Some":      true,
+	"None":      true,
+	"Ok":        true,
+	"Err":       true,
+	"map":       true,
+	"and_then":  true,
+	"unwrap_or": true,
+}
+
+// missing : what Option and Result hold instead of a value, which has to be handled before the value is used
+var missing = map[string]string{
+	"Option": "None",
+	"Result": "Err",
+}
+
+// families : Option and Result, just as if they were declared with `type`
+var families = []*ast.TypeStatement{}
+
+func init() {
+	for _, statement := range parse("type Option<T> = Some(value: T) | None; type Result<T, E> = Ok(value: T) | Err(error: E)") {
+		families = append(families, statement.(*ast.TypeStatement))
+	}
+}
+
+// Checker : follows the matrices whose dimensions are known statically, so mismatches are caught before running the
+// program, checks the calls to the functions declared to be written in R, that Options and Results are handled
+// before the values they may hold are used, and that matches handle every alternative of their type; anything it
+// can not be sure about is left for the runtime to report
+type Checker struct {
+	// Path is the file being checked, the declaration files it imports being found from it
+	Path         string
+	shapes       map[string]Shape
+	types        map[string]*ast.Type
+	declarations map[string]*ast.DeclareStatement
+	redefined    map[string]bool
+	constructors map[string]*ast.TypeStatement
+	aliases      map[string]*ast.TypeStatement
+	// the names imported from modules, which may be constructors of the types they define
+	imported map[string]bool
+	errors   []string
+	warnings []string
+}
+
+// InitializeChecker :
+func InitializeChecker() *Checker {
+	return &Checker{
+		shapes:       map[string]Shape{},
+		types:        map[string]*ast.Type{},
+		declarations: copyDeclarations(builtins),
+		redefined:    map[string]bool{},
+		constructors: map[string]*ast.TypeStatement{},
+		aliases:      map[string]*ast.TypeStatement{},
+		imported:     map[string]bool{},
+	}
+}
+
+// Check : the problems found in the program, telling where they are; what is known about the names bound by a
+// program without problems is kept for the next one, as the REPL checks a line at a time
+func (c *Checker) Check(program *ast.Program) []string {
+	shapes, types, declarations, redefined := copyShapes(c.shapes), copyTypes(c.types), copyDeclarations(c.declarations), copyNames(c.redefined)
+	constructors, aliases, imported := copyConstructors(c.constructors), copyConstructors(c.aliases), copyNames(c.imported)
+	c.errors, c.warnings = []string{}, []string{}
+
+	for _, statement := range program.Statements {
+		c.statement(statement)
+	}
+
+	if 0 != len(c.errors) {
+		c.shapes, c.types, c.declarations, c.redefined = shapes, types, declarations, redefined
+		c.constructors, c.aliases, c.imported = constructors, aliases, imported
+	}
+
+	return c.errors
+}
+
+// Warnings : what the last program checked may get wrong without being an error, such as a match missing some
+// alternatives of its type
+func (c *Checker) Warnings() []string {
+	return c.warnings
+}
+
+// copyShapes :
+func copyShapes(shapes map[string]Shape) map[string]Shape {
+	copied := make(map[string]Shape, len(shapes))
+
+	for name, shape := range shapes {
+		copied[name] = shape
+	}
+
+	return copied
+}
+
+// copyTypes :
+func copyTypes(types map[string]*ast.Type) map[string]*ast.Type {
+	copied := make(map[string]*ast.Type, len(types))
+
+	for name, annotation := range types {
+		copied[name] = annotation
+	}
+
+	return copied
+}
+
+// copyDeclarations :
+func copyDeclarations(declarations map[string]*ast.DeclareStatement) map[string]*ast.DeclareStatement {
+	copied := make(map[string]*ast.DeclareStatement, len(declarations))
+
+	for name, declaration := range declarations {
+		copied[name] = declaration
+	}
+
+	return copied
+}
+
+// copyConstructors : the types by name, or by the name of their constructors
+func copyConstructors(constructors map[string]*ast.TypeStatement) map[string]*ast.TypeStatement {
+	copied := make(map[string]*ast.TypeStatement, len(constructors))
+
+	for name, statement := range constructors {
+		copied[name] = statement
+	}
+
+	return copied
+}
+
+// copyNames :
+func copyNames(names map[string]bool) map[string]bool {
+	copied := make(map[string]bool, len(names))
+
+	for name, value := range names {
+		copied[name] = value
+	}
+
+	return copied
+}
+
+// error :
+func (c *Checker) error(tok token.Token, format string, a ...interface{}) {
+	c.errors = append(c.errors, fmt.Sprintf("%s at line %d, column %d", fmt.Sprintf(format, a...), tok.Line, tok.Column))
+}
+
+// warn :
+func (c *Checker) warn(tok token.Token, format string, a ...interface{}) {
+	c.warnings = append(c.warnings, fmt.Sprintf("%s at line %d, column %d", fmt.Sprintf(format, a...), tok.Line, tok.Column))
+}
+
+// bind : a name bound to anything else than a known matrix is forgotten, just as one whose type is not known, and a
+// builtin or a declared function bound to something else no longer tells the shape of its result
+func (c *Checker) bind(name string, shape Shape, known bool, annotation *ast.Type) {
+	if known {
+		c.shapes[name] = shape
+	} else {
+		delete(c.shapes, name)
+	}
+
+	if nil != annotation {
+		c.types[name] = annotation
+	} else {
+		delete(c.types, name)
+	}
+
+	if shaping[name] || optional[name] {
+		c.redefined[name] = true
+	}
+
+	delete(c.declarations, name)
+	delete(c.constructors, name)
+}
+
+// declare : the declaration is trusted, the function doing just what it tells
+func (c *Checker) declare(declaration *ast.DeclareStatement) {
+	name := declaration.Name.Value
+
+	delete(c.shapes, name)
+	delete(c.types, name)
+
+	c.declarations[name] = c.expanded(declaration)
+}
+
+// define : the constructors of the type are checked just as declared functions giving a value of the type, their
+// fields being their parameters and the type parameters of the type theirs; an alias is only known to the checker
+func (c *Checker) define(statement *ast.TypeStatement) {
+	if c.duplicate(statement) {
+		return
+	}
+
+	if nil != statement.Alias {
+		c.knownSignature(statement.TypeParameters, nil, statement.Alias)
+		c.alias(statement)
+
+		return
+	}
+
+	delete(c.aliases, statement.Name.Value)
+
+	result := &ast.Type{Token: statement.Token, Name: statement.Name.Value}
+
+	for _, parameter := range statement.TypeParameters {
+		result.Parameters = append(result.Parameters, &ast.Type{Token: parameter.Token, Name: parameter.Name})
+	}
+
+	expanded := *statement
+	expanded.Constructors = []*ast.Constructor{}
+
+	for _, constructor := range statement.Constructors {
+		name := constructor.Name.Value
+
+		c.bind(name, Shape{}, false, nil)
+		c.declare(&ast.DeclareStatement{
+			Token:          constructor.Token,
+			Name:           constructor.Name,
+			TypeParameters: statement.TypeParameters,
+			Parameters:     constructor.Fields,
+			Types:          constructor.Types,
+			Defaults:       make([]ast.Expression, len(constructor.Fields)),
+			Result:         result,
+		})
+
+		expanded.Constructors = append(expanded.Constructors, &ast.Constructor{
+			Token:  constructor.Token,
+			Name:   constructor.Name,
+			Fields: constructor.Fields,
+			Types:  c.declarations[name].Types,
+		})
+	}
+
+	for _, constructor := range expanded.Constructors {
+		c.constructors[constructor.Name.Value] = &expanded
+	}
+
+	// the fields may be of the type being defined
+	fields := []*ast.Type{}
+
+	for _, constructor := range statement.Constructors {
+		fields = append(fields, constructor.Types...)
+	}
+
+	c.knownSignature(statement.TypeParameters, fields, nil)
+}
+
+// duplicate : reports a type named as one already defined, alias or not, or a constructor named as one of another
+// alternative, as a match could not tell them apart; Option and Result are defined by the checker itself
+func (c *Checker) duplicate(statement *ast.TypeStatement) bool {
+	errors := len(c.errors)
+
+	defined := c.aliases[statement.Name.Value]
+
+	if nil == defined {
+		defined = c.named(statement.Name.Value)
+	}
+
+	if nil != defined {
+		c.error(statement.Name.Token, "duplicate type %s (first defined %s)", statement.Name.Value, where(defined, defined.Name.Token))
+	}
+
+	seen := map[string]*ast.Constructor{}
+
+	for _, constructor := range statement.Constructors {
+		name := constructor.Name.Value
+		family, defined := c.family(name)
+
+		if earlier, ok := seen[name]; ok {
+			family, defined = statement, earlier
+		}
+
+		if nil != defined {
+			c.error(constructor.Token, "duplicate constructor %s (first defined %s)", name, where(family, defined.Token))
+		}
+
+		seen[name] = constructor
+	}
+
+	return errors != len(c.errors)
+}
+
+// where : the position of the token, given as the checker tells it, Option and Result being built in
+func where(statement *ast.TypeStatement, tok token.Token) string {
+	for _, family := range families {
+		if family == statement {
+			return "by " + family.Name.Value
+		}
+	}
+
+	return fmt.Sprintf("at line %d, column %d", tok.Line, tok.Column)
+}
+
+// load : the names imported from a declaration file are bound to their declarations, while anything imported from
+// a module is not known; a file that can not be loaded is for the compiler to report
+func (c *Checker) load(statement *ast.ImportStatement) {
+	declarations := map[string]*ast.DeclareStatement{}
+
+	if path, err := module.Resolve(c.Path, statement.Path); nil == err && module.IsDeclarationFile(path) {
+		if program, err := module.Load(path); nil == err {
+			for _, declared := range program.Statements {
+				declaration := declared.(*ast.DeclareStatement)
+				declarations[declaration.Name.Value] = declaration
+			}
+		}
+	}
+
+	for _, name := range statement.Names {
+		c.bind(name.Value, Shape{}, false, nil)
+		c.imported[name.Value] = true
+
+		if declaration, ok := declarations[name.Value]; ok {
+			c.declare(declaration)
+		}
+	}
+}
+
+// statement :
+func (c *Checker) statement(statement ast.Statement) {
+	switch statement := statement.(type) {
+	case *ast.LetStatement:
+		shape, known := c.expression(statement.Value)
+		c.bind(statement.Name.Value, shape, known, c.typeOf(statement.Value))
+		c.annotated(statement.Name, statement.Value)
+	case *ast.ConstStatement:
+		shape, known := c.expression(statement.Value)
+		c.bind(statement.Name.Value, shape, known, c.typeOf(statement.Value))
+		c.annotated(statement.Name, statement.Value)
+	case *ast.ReturnStatement:
+		c.expression(statement.ReturnValue)
+	case *ast.ExpressionStatement:
+		c.expression(statement.Expression)
+	case *ast.ImportStatement:
+		c.load(statement)
+	case *ast.TypeStatement:
+		c.define(statement)
+	case *ast.DeclareStatement:
+		for _, value := range statement.Defaults {
+			c.expression(value)
+		}
+
+		c.knownSignature(statement.TypeParameters, statement.Types, statement.Result)
+		c.declare(statement)
+	}
+}
+
+// annotated : a function telling the types of its parameters or of its result is checked as if it was declared,
+// keeping the type of function
+func (c *Checker) annotated(name *ast.Identifier, value ast.Expression) {
+	if function, ok := value.(*ast.FunctionLiteral); ok && function.Annotated() {
+		c.declare(signature(name, function))
+		c.types[name.Value] = c.typeOf(function)
+	}
+}
+
+// block :
+func (c *Checker) block(block *ast.BlockStatement) {
+	if nil == block {
+		return
+	}
+
+	for _, statement := range block.Statements {
+		c.statement(statement)
+	}
+}
+
+// branch : a block that may not run, so any shape or type it changes is no longer known after it, just as any
+// declaration it hides; the names, of the given types, are bound before it runs
+func (c *Checker) branch(block *ast.BlockStatement, names []*ast.Identifier, types []*ast.Type) {
+	outer, outerTypes, outerDeclarations := c.shapes, c.types, c.declarations
+	c.shapes, c.types, c.declarations = copyShapes(outer), copyTypes(outerTypes), copyDeclarations(outerDeclarations)
+
+	for index, name := range names {
+		if "_" != name.Value {
+			c.bind(name.Value, Shape{}, false, types[index])
+		}
+	}
+
+	c.block(block)
+
+	for name, shape := range outer {
+		if changed, ok := c.shapes[name]; !ok || changed != shape {
+			delete(outer, name)
+		}
+	}
+
+	for name, annotation := range outerTypes {
+		if changed, ok := c.types[name]; !ok || changed != annotation {
+			delete(outerTypes, name)
+		}
+	}
+
+	for name, declaration := range outerDeclarations {
+		if changed, ok := c.declarations[name]; !ok || changed != declaration {
+			delete(outerDeclarations, name)
+		}
+	}
+
+	c.shapes, c.types, c.declarations = outer, outerTypes, outerDeclarations
+}
+
+// function : the body is checked with the parameters hiding whatever they are named after, nothing it binds
+// being seen outside
+func (c *Checker) function(function *ast.FunctionLiteral) {
+	shapes, types, declarations, redefined := c.shapes, c.types, c.declarations, c.redefined
+	c.shapes, c.types, c.declarations, c.redefined = copyShapes(shapes), copyTypes(types), copyDeclarations(declarations), copyNames(redefined)
+
+	c.knownSignature(function.TypeParameters, function.Types, function.Result)
+
+	generic := generics(function.TypeParameters)
+
+	for index, parameter := range function.Parameters {
+		annotation := c.expand(function.Type(index), generic)
+
+		if nil != annotation && !concrete(annotation, generic) {
+			annotation = nil
+		}
+
+		c.bind(parameter.Value, Shape{}, false, annotation)
+	}
+
+	c.block(function.Body)
+
+	c.shapes, c.types, c.declarations, c.redefined = shapes, types, declarations, redefined
+}
+
+// expressions :
+func (c *Checker) expressions(expressions []ast.Expression) ([]Shape, []bool) {
+	shapes := make([]Shape, len(expressions))
+	known := make([]bool, len(expressions))
+
+	for index, expression := range expressions {
+		shapes[index], known[index] = c.expression(expression)
+	}
+
+	return shapes, known
+}
+
+// expression : the shape of the matrix the expression results in, when it is known
+func (c *Checker) expression(expression ast.Expression) (Shape, bool) {
+	switch expression := expression.(type) {
+	case *ast.Identifier:
+		shape, known := c.shapes[expression.Value]
+
+		return shape, known
+	case *ast.PrefixExpression:
+		shape, known := c.expression(expression.Right)
+		c.handled(expression.Token, expression.Operator, expression.Right)
+
+		return shape, known && "-" == expression.Operator
+	case *ast.InfixExpression:
+		return c.infix(expression)
+	case *ast.CallExpression:
+		return c.call(expression)
+	case *ast.ConditionalExpression:
+		c.expression(expression.Condition)
+		c.branch(expression.Consequence, nil, nil)
+		c.branch(expression.Alternative, nil, nil)
+	case *ast.MatchExpression:
+		c.expression(expression.Subject)
+		c.match(expression)
+	case *ast.FunctionLiteral:
+		c.function(expression)
+	case *ast.ArrayLiteral:
+		c.expressions(expression.Elements)
+	case *ast.IndexExpression:
+		c.expression(expression.Left)
+		c.expression(expression.Index)
+		c.expression(expression.Column)
+	case *ast.PointFreeExpression:
+		c.expressions(expression.Parameters)
+	case *ast.NamedArgument:
+		return c.expression(expression.Value)
+	}
+
+	return Shape{}, false
+}
+
+// infix : `%*%` needs as many columns on the left as rows on the right, while element-wise operations need the
+// very same dimensions on both sides
+func (c *Checker) infix(infix *ast.InfixExpression) (Shape, bool) {
+	left, leftKnown := c.expression(infix.Left)
+	right, rightKnown := c.expression(infix.Right)
+
+	c.handled(infix.Token, infix.Operator, infix.Left, infix.Right)
+
+	switch infix.Operator {
+	case "%*%":
+		if !leftKnown || !rightKnown {
+			return Shape{}, false
+		}
+
+		if left.Columns != right.Rows {
+			c.error(infix.Token, "non-conformable arguments: %s %%*%% %s", left, right)
+
+			return Shape{}, false
+		}
+
+		return Shape{
+			Rows:    left.Rows,
+			Columns: right.Columns,
+		}, true
+	case "+", "-", "*", "/":
+		if leftKnown && rightKnown && left != right {
+			c.error(infix.Token, "non-conformable arrays: %s %s %s", left, infix.Operator, right)
+
+			return Shape{}, false
+		}
+
+		if leftKnown {
+			return left, true
+		}
+
+		return right, rightKnown
+	}
+
+	return Shape{}, false
+}
+
+// length : how many elements a literal given as the data of a matrix has
+func length(expression ast.Expression) (int, bool) {
+	switch expression := expression.(type) {
+	case *ast.ArrayLiteral:
+		return len(expression.Elements), true
+	case *ast.IntegerLiteral, *ast.DoubleLiteral, *ast.Boolean, *ast.NotAvailable:
+		return 1, true
+	}
+
+	return 0, false
+}
+
+// dimension : a number of rows or columns given as a literal
+func dimension(expression ast.Expression) (int, bool) {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteral:
+		return int(expression.Value), 0 <= expression.Value
+	case *ast.DoubleLiteral:
+		return int(expression.Value), 0 <= expression.Value && float64(int(expression.Value)) == expression.Value
+	}
+
+	return 0, false
+}
+
+// split : the names and values of the arguments of the call, telling whether any is named
+func split(call *ast.CallExpression) ([]string, []ast.Expression, bool) {
+	names := make([]string, len(call.Parameters))
+	values := make([]ast.Expression, len(call.Parameters))
+	named := false
+
+	for index, parameter := range call.Parameters {
+		values[index] = parameter
+
+		if argument, ok := parameter.(*ast.NamedArgument); ok {
+			names[index], values[index], named = argument.Name, argument.Value, true
+		}
+	}
+
+	return names, values, named
+}
+
+// arguments : the arguments of a call to a builtin in the order it takes them, nil for those left out before the
+// last one given, or false when they do not match its parameters, which is for the runtime to report
+func arguments(function string, call *ast.CallExpression, shapes []Shape, known []bool) ([]ast.Expression, []Shape, []bool, bool) {
+	names, values, named := split(call)
+
+	if !named {
+		return values, shapes, known, true
+	}
+
+	bound, _, err := object.MatchArguments(object.GetBuiltinByName(function).Parameters, names, make([]object.Object, len(values)))
+
+	if nil != err {
+		return nil, nil, nil, false
+	}
+
+	last := len(bound) - 1
+
+	for 0 <= last && -1 == bound[last] {
+		last--
+	}
+
+	ordered := make([]ast.Expression, last+1)
+	orderedShapes := make([]Shape, last+1)
+	orderedKnown := make([]bool, last+1)
+
+	for parameter := range ordered {
+		if index := bound[parameter]; -1 != index {
+			ordered[parameter], orderedShapes[parameter], orderedKnown[parameter] = values[index], shapes[index], known[index]
+		}
+	}
+
+	return ordered, orderedShapes, orderedKnown, true
+}
+
+// call :
+func (c *Checker) call(call *ast.CallExpression) (Shape, bool) {
+	c.expression(call.Function)
+	shapes, known := c.expressions(call.Parameters)
+	function, ok := call.Function.(*ast.Identifier)
+
+	if !ok {
+		return Shape{}, false
+	}
+
+	if declaration, ok := c.declarations[function.Value]; ok {
+		c.declared(call, declaration)
+	}
+
+	if optional[function.Value] && !c.redefined[function.Value] {
+		c.option(call, function.Value)
+	}
+
+	if !shaping[function.Value] || c.redefined[function.Value] {
+		return Shape{}, false
+	}
+
+	parameters, shapes, known, ok := arguments(function.Value, call, shapes, known)
+
+	if !ok || 0 == len(parameters) || nil == parameters[0] {
+		return Shape{}, false
+	}
+
+	switch function.Value {
+	case "matrix":
+		return c.matrix(call, parameters)
+	case "t":
+		return Shape{
+			Rows:    shapes[0].Columns,
+			Columns: shapes[0].Rows,
+		}, known[0]
+	case "diag":
+		if size, ok := dimension(parameters[0]); ok {
+			return Shape{
+				Rows:    size,
+				Columns: size,
+			}, true
+		}
+
+		if array, ok := parameters[0].(*ast.ArrayLiteral); ok {
+			return Shape{
+				Rows:    len(array.Elements),
+				Columns: len(array.Elements),
+			}, true
+		}
+	case "solve":
+		return c.solve(call, parameters, shapes, known)
+	}
+
+	return Shape{}, false
+}
+
+// matrix : `matrix(data, nrow, ncol)`, when data and the dimensions are literals
+func (c *Checker) matrix(call *ast.CallExpression, parameters []ast.Expression) (Shape, bool) {
+	size, known := length(parameters[0])
+
+	if 1 == len(parameters) {
+		return Shape{
+			Rows:    size,
+			Columns: 1,
+		}, known
+	}
+
+	rows, ok := dimension(parameters[1])
+
+	if !ok {
+		return Shape{}, false
+	}
+
+	if 2 == len(parameters) {
+		if !known || 0 == rows {
+			return Shape{}, false
+		}
+
+		return Shape{
+			Rows:    rows,
+			Columns: (size + rows - 1) / rows,
+		}, true
+	}
+
+	columns, ok := dimension(parameters[2])
+
+	if !ok {
+		return Shape{}, false
+	}
+
+	if known && 0 != size && (size > rows*columns || 0 != (rows*columns)%size) {
+		c.error(call.Token, "data length %d differs from the size of the matrix, %dx%d", size, rows, columns)
+
+		return Shape{}, false
+	}
+
+	return Shape{
+		Rows:    rows,
+		Columns: columns,
+	}, true
+}
+
+// solve : `solve(a, b)` needs a square matrix, with as many rows as b
+func (c *Checker) solve(call *ast.CallExpression, parameters []ast.Expression, shapes []Shape, known []bool) (Shape, bool) {
+	a := shapes[0]
+
+	if !known[0] {
+		return Shape{}, false
+	}
+
+	if a.Rows != a.Columns {
+		c.error(call.Token, "a to `solve` must be square, got %s", a)
+
+		return Shape{}, false
+	}
+
+	if 1 == len(parameters) {
+		return a, true
+	}
+
+	if 2 != len(parameters) || !known[1] {
+		return Shape{}, false
+	}
+
+	b := shapes[1]
+
+	if b.Rows != a.Rows {
+		c.error(call.Token, "b to `solve` must have %d rows, got %s", a.Rows, b)
+
+		return Shape{}, false
+	}
+
+	return b, true
+}
+
+// source : an argument as written, for the errors about the arguments to show it
+type source struct {
+	expression ast.Expression
+}
+
+// Type :
+func (s source) Type() object.ObjectType {
+	return "SOURCE"
+}
+
+// Inspect :
+func (s source) Inspect() string {
+	return s.expression.String()
+}
+
+// matched : the argument bound to every parameter of the declaration, just as R would match them, nil for those
+// left out; false when they can not be told, as when `...` is passed along
+func matched(call *ast.CallExpression, declaration *ast.DeclareStatement) ([]ast.Expression, *object.Error, bool) {
+	names, values, _ := split(call)
+	arguments := make([]object.Object, len(values))
+
+	for index, value := range values {
+		if identifier, ok := value.(*ast.Identifier); ok && "..." == identifier.Value {
+			return nil, nil, false
+		}
+
+		arguments[index] = source{value}
+	}
+
+	parameters := make([]string, len(declaration.Parameters))
+
+	for index, parameter := range declaration.Parameters {
+		parameters[index] = parameter.Value
+	}
+
+	bound, _, err := object.MatchArguments(parameters, names, arguments)
+
+	if nil != err {
+		return nil, err, true
+	}
+
+	given := make([]ast.Expression, len(parameters))
+
+	for index := range parameters {
+		if -1 != bound[index] {
+			given[index] = values[bound[index]]
+		}
+	}
+
+	return given, nil, true
+}
+
+// declared : a function declared to be written in R has no body to check, so its declaration is trusted and the
+// arguments of the calls to it are checked against it instead, the type parameters of a generic one standing for
+// the types the arguments give them
+func (c *Checker) declared(call *ast.CallExpression, declaration *ast.DeclareStatement) {
+	values, err, ok := matched(call, declaration)
+
+	if !ok {
+		return
+	}
+
+	if nil != err {
+		c.error(call.Token, "%s", err.Message)
+
+		return
+	}
+
+	bindings, failed := c.instantiate(call, declaration, values, true)
+	generic := generics(declaration.TypeParameters)
+
+	for index, parameter := range declaration.Parameters {
+		if nil == values[index] {
+			if "..." != parameter.Value && nil == declaration.Defaults[index] {
+				c.error(call.Token, "%s", object.MissingArgument(parameter.Value).Message)
+			}
+
+			continue
+		}
+
+		expected := substitute(declaration.Types[index], bindings, generic)
+		actual := c.typeOf(values[index])
+
+		if nil != expected && nil != actual && !failed[index] && !assignable(expected, actual) {
+			c.error(call.Token, "%s to `%s` must be %s, got %s", parameter, declaration.Name, expected, actual)
+		}
+	}
+}
+
+// result : the type a call to the declared function results in, the type parameters of a generic one standing for
+// the types its arguments give them
+func (c *Checker) result(call *ast.CallExpression, declaration *ast.DeclareStatement) *ast.Type {
+	if 0 == len(declaration.TypeParameters) {
+		return declaration.Result
+	}
+
+	values, err, ok := matched(call, declaration)
+
+	if !ok || nil != err {
+		return nil
+	}
+
+	bindings, _ := c.instantiate(call, declaration, values, false)
+
+	return substitute(declaration.Result, bindings, generics(declaration.TypeParameters))
+}
+
+// numeric : the types of numbers, the integers taking part in any computation with doubles
+var numeric = map[string]int{
+	"integer": 1,
+	"double":  2,
+	"numeric": 3,
+}
+
+// assignable : whether a value of the actual type can be given where the expected one is declared; a single value
+// is a vector of length one, as in R, and an integer can stand for a double
+func assignable(expected *ast.Type, actual *ast.Type) bool {
+	if "any" == expected.Name || "any" == actual.Name {
+		return true
+	}
+
+	if actual.Vector && !expected.Vector {
+		return false
+	}
+
+	if expected.Name == actual.Name {
+		for index := range expected.Parameters {
+			if index < len(actual.Parameters) && !assignable(expected.Parameters[index], actual.Parameters[index]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return 0 != numeric[actual.Name] && numeric[actual.Name] < numeric[expected.Name]
+}
+
+// typeOf : the type of the value of the expression, when it can be told without running it, nil otherwise; the
+// declared functions being trusted, their calls result in the type they are declared to return
+func (c *Checker) typeOf(expression ast.Expression) *ast.Type {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteral:
+		return &ast.Type{Token: expression.Token, Name: "integer"}
+	case *ast.DoubleLiteral:
+		return &ast.Type{Token: expression.Token, Name: "double"}
+	case *ast.StringLiteral:
+		return &ast.Type{Token: expression.Token, Name: "character"}
+	case *ast.Boolean:
+		return &ast.Type{Token: expression.Token, Name: "logical"}
+	case *ast.FunctionLiteral:
+		return &ast.Type{Token: expression.Token, Name: "function"}
+	case *ast.Identifier:
+		return c.types[expression.Value]
+	case *ast.PrefixExpression:
+		right := c.typeOf(expression.Right)
+
+		switch {
+		case nil == right:
+			return nil
+		case "-" == expression.Operator && 0 != numeric[right.Name]:
+			return right
+		case "!" == expression.Operator && "logical" == right.Name:
+			return right
+		}
+	case *ast.ArrayLiteral:
+		return c.elements(expression)
+	case *ast.CallExpression:
+		function, ok := expression.Function.(*ast.Identifier)
+
+		if !ok {
+			return nil
+		}
+
+		if declaration, ok := c.declarations[function.Value]; ok {
+			return c.result(expression, declaration)
+		}
+
+		if optional[function.Value] && !c.redefined[function.Value] {
+			return c.optionType(expression, function.Value)
+		}
+	}
+
+	return nil
+}
+
+// anyType :
+func anyType(tok token.Token) *ast.Type {
+	return &ast.Type{
+		Token: tok,
+		Name:  "any",
+	}
+}
+
+// parameterType : the type given to the generic type at index, any when it is not told
+func parameterType(annotation *ast.Type, index int) *ast.Type {
+	if index >= len(annotation.Parameters) {
+		return anyType(annotation.Token)
+	}
+
+	return annotation.Parameters[index]
+}
+
+// isOptional : whether the value may be None or Err, a vector of them being something else
+func isOptional(annotation *ast.Type) bool {
+	return nil != annotation && "" != missing[annotation.Name] && !annotation.Vector
+}
+
+// positional : the arguments of a call given by position, false when any is named
+func positional(call *ast.CallExpression) ([]ast.Expression, bool) {
+	_, values, named := split(call)
+
+	return values, !named
+}
+
+// optionType : `Some(x)` is an Option of the type of x, just as `Ok(x)` and `Err(e)` are Results, the other type being
+// any; `unwrap_or` gives the type the Option or Result holds, while `map` and `and_then` give one of the same kind
+func (c *Checker) optionType(call *ast.CallExpression, function string) *ast.Type {
+	values, ok := positional(call)
+
+	if !ok {
+		return nil
+	}
+
+	held := func(index int) *ast.Type {
+		if index < len(values) {
+			if annotation := c.typeOf(values[index]); nil != annotation {
+				return annotation
+			}
+		}
+
+		return anyType(call.Token)
+	}
+
+	switch function {
+	case "Some":
+		return &ast.Type{Token: call.Token, Name: "Option", Parameters: []*ast.Type{held(0)}}
+	case "None":
+		return &ast.Type{Token: call.Token, Name: "Option", Parameters: []*ast.Type{anyType(call.Token)}}
+	case "Ok":
+		return &ast.Type{Token: call.Token, Name: "Result", Parameters: []*ast.Type{held(0), anyType(call.Token)}}
+	case "Err":
+		return &ast.Type{Token: call.Token, Name: "Result", Parameters: []*ast.Type{anyType(call.Token), held(0)}}
+	}
+
+	if 0 == len(values) {
+		return nil
+	}
+
+	option := c.typeOf(values[0])
+
+	if !isOptional(option) {
+		return nil
+	}
+
+	if "unwrap_or" == function {
+		if value := parameterType(option, 0); "any" != value.Name || 2 != len(values) {
+			return value
+		}
+
+		return held(1)
+	}
+
+	kind := &ast.Type{Token: call.Token, Name: option.Name, Parameters: []*ast.Type{anyType(call.Token)}}
+
+	if "Result" == option.Name {
+		kind.Parameters = append(kind.Parameters, parameterType(option, 1))
+	}
+
+	return kind
+}
+
+// option : the helpers only take an Option or a Result
+func (c *Checker) option(call *ast.CallExpression, function string) {
+	values, ok := positional(call)
+
+	if !ok || 0 == len(values) || "map" != function && "and_then" != function && "unwrap_or" != function {
+		return
+	}
+
+	if annotation := c.typeOf(values[0]); nil != annotation && "any" != annotation.Name && !isOptional(annotation) {
+		c.error(call.Token, "o to `%s` must be Option or Result, got %s", function, annotation)
+	}
+}
+
+// handled : an Option or a Result can not be used as the value it may hold, both cases having to be handled first
+func (c *Checker) handled(tok token.Token, operator string, operands ...ast.Expression) {
+	for _, operand := range operands {
+		if annotation := c.typeOf(operand); isOptional(annotation) {
+			c.error(tok, "%s may be %s, handle both cases with unwrap_or, map or and_then before `%s`", annotation, missing[annotation.Name], operator)
+
+			return
+		}
+	}
+}
+
+// elements : a vector of the type every element has, the integers among doubles being taken as doubles; NA fits in
+// with any of them
+func (c *Checker) elements(array *ast.ArrayLiteral) *ast.Type {
+	var element *ast.Type
+
+	for _, expression := range array.Elements {
+		if _, ok := expression.(*ast.NotAvailable); ok {
+			continue
+		}
+
+		annotation := c.typeOf(expression)
+
+		switch {
+		case nil == annotation || annotation.Vector:
+			return nil
+		case nil == element || assignable(annotation, element):
+			element = annotation
+		case !assignable(element, annotation):
+			return nil
+		}
+	}
+
+	if nil == element {
+		return nil
+	}
+
+	return &ast.Type{
+		Token:  array.Token,
+		Name:   element.Name,
+		Vector: true,
+	}
+}
+
+// family : the type the constructor makes a value of, along with the alternative it makes, nil when it is not known
+func (c *Checker) family(tag string) (*ast.TypeStatement, *ast.Constructor) {
+	statements := []*ast.TypeStatement{}
+
+	if statement, ok := c.constructors[tag]; ok {
+		statements = append(statements, statement)
+	} else if optional[tag] && !c.redefined[tag] {
+		statements = families
+	}
+
+	for _, statement := range statements {
+		for _, constructor := range statement.Constructors {
+			if tag == constructor.Name.Value {
+				return statement, constructor
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// fields : the types of the fields the pattern binds, the type parameters of its type standing for the types the
+// subject gives them, nil for those not known
+func fields(statement *ast.TypeStatement, constructor *ast.Constructor, subject *ast.Type, count int) []*ast.Type {
+	bindings, generic := map[string]*ast.Type{}, generics(statement.TypeParameters)
+
+	if nil != subject && subject.Name == statement.Name.Value {
+		for index, parameter := range statement.TypeParameters {
+			if index < len(subject.Parameters) && "any" != subject.Parameters[index].Name {
+				bindings[parameter.Name] = subject.Parameters[index]
+			}
+		}
+	}
+
+	types := make([]*ast.Type, count)
+
+	for index := range types {
+		if index < len(constructor.Types) {
+			types[index] = substitute(constructor.Types[index], bindings, generic)
+		}
+	}
+
+	return types
+}
+
+// match : the patterns must all be alternatives of the same type, naming as many fields as they have; a match
+// without `_` leaving out some alternatives is warned about, unless a pattern is not known to the checker
+func (c *Checker) match(match *ast.MatchExpression) {
+	var statement *ast.TypeStatement
+
+	subject := c.typeOf(match.Subject)
+
+	if nil != subject && !subject.Vector {
+		statement = c.named(subject.Name)
+	}
+
+	handled, exhaustive := map[string]bool{}, true
+
+	for _, arm := range match.Arms {
+		if arm.IsWildcard() {
+			c.branch(arm.Body, nil, nil)
+			handled["_"] = true
+
+			continue
+		}
+
+		tag := arm.Constructor.Value
+		family, constructor := c.family(tag)
+
+		switch {
+		case nil == family && !optional[tag] && !c.imported[tag]:
+			c.error(arm.Token, "pattern %s is not a constructor, only `_` matches anything", tag)
+			exhaustive = false
+		case nil == family:
+			exhaustive = false
+		case nil != statement && family != statement:
+			c.error(arm.Token, "pattern %s is not an alternative of %s", tag, statement.Name)
+		case nil != arm.Fields && len(arm.Fields) != len(constructor.Fields):
+			c.error(arm.Token, "pattern %s takes %d fields, got=%d", tag, len(constructor.Fields), len(arm.Fields))
+		default:
+			statement = family
+		}
+
+		handled[tag] = true
+
+		if nil == constructor {
+			c.branch(arm.Body, arm.Fields, make([]*ast.Type, len(arm.Fields)))
+		} else {
+			c.branch(arm.Body, arm.Fields, fields(family, constructor, subject, len(arm.Fields)))
+		}
+	}
+
+	if nil == statement || !exhaustive || handled["_"] {
+		return
+	}
+
+	left := []string{}
+
+	for _, constructor := range statement.Constructors {
+		if !handled[constructor.Name.Value] {
+			left = append(left, constructor.Name.Value)
+		}
+	}
+
+	if 0 != len(left) {
+		c.warn(match.Token, "match on %s is not exhaustive, %s not handled", statement.Name, strings.Join(left, ", "))
+	}
+}
+
+// named : the type declared with `type`, or Option and Result, named so
+func (c *Checker) named(name string) *ast.TypeStatement {
+	for _, statement := range c.constructors {
+		if name == statement.Name.Value {
+			return statement
+		}
+	}
+
+	for _, statement := range families {
+		if name == statement.Name.Value {
+			return statement
+		}
+	}
+
+	return nil
+}
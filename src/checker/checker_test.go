@@ -0,0 +1,505 @@
+package checker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"../lexer"
+	"../object"
+	"../parser"
+)
+
+// check :
+func check(t *testing.T, checker *Checker, input string) []string {
+	t.Helper()
+
+	p := parser.InitializeParser(lexer.InitializeLexer(input))
+	program := p.ParseProgram()
+
+	if 0 != len(p.Errors()) {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	return checker.Check(program)
+}
+
+func TestMatrixShapes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			`let a <- matrix([1, 2, 3, 4, 5, 6], 2, 3); a %*% a`,
+			[]string{
+				"non-conformable arguments: 2x3 %*% 2x3 at line 1, column 46",
+			},
+		},
+		{
+			`let a <- matrix([1, 2, 3, 4, 5, 6], 2, 3); let b <- a %*% t(a); b %*% a`,
+			[]string{},
+		},
+		{
+			`let a <- matrix([1, 2, 3, 4, 5, 6], 2); a + t(a)`,
+			[]string{
+				"non-conformable arrays: 2x3 + 3x2 at line 1, column 43",
+			},
+		},
+		{
+			`let a <- matrix(0, 2, 3) * 2; solve(a)`,
+			[]string{
+				"a to `solve` must be square, got 2x3 at line 1, column 36",
+			},
+		},
+		{
+			`solve(diag(3), matrix(1, 2, 1))`,
+			[]string{
+				"b to `solve` must have 3 rows, got 2x1 at line 1, column 6",
+			},
+		},
+		{
+			`matrix([1, 2, 3, 4, 5], 2, 3)`,
+			[]string{
+				"data length 5 differs from the size of the matrix, 2x3 at line 1, column 7",
+			},
+		},
+		{
+			`let a <- matrix(1, 2, 3); let f <- function(a) { a %*% a }; f(diag(2))`,
+			[]string{},
+		},
+		{
+			`let a <- matrix(1, 2, 3); if (TRUE) { let a <- diag(3) }; a %*% a`,
+			[]string{},
+		},
+		{
+			`let t <- function(x) { x }; let a <- matrix(1, 2, 3); t(a) + a`,
+			[]string{},
+		},
+		{
+			`let a <- matrix(1, n, 3); a %*% a`,
+			[]string{},
+		},
+		{
+			`matrix([1, 2, 3, 4, 5, 6], ncol = 3, nrow = 2) %*% diag(2)`,
+			[]string{
+				"non-conformable arguments: 2x3 %*% 2x2 at line 1, column 48",
+			},
+		},
+		{
+			`solve(b = matrix(1, 2, 1), a = diag(3))`,
+			[]string{
+				"b to `solve` must have 3 rows, got 2x1 at line 1, column 6",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		errors := check(t, InitializeChecker(), tt.input)
+
+		if len(errors) != len(tt.expected) {
+			t.Errorf("wrong number of errors for %q, got=%v", tt.input, errors)
+
+			continue
+		}
+
+		for index, expected := range tt.expected {
+			if errors[index] != expected {
+				t.Errorf("wrong error, expected=%q, got=%q", expected, errors[index])
+			}
+		}
+	}
+}
+
+func TestShapesKeptBetweenPrograms(t *testing.T) {
+	checker := InitializeChecker()
+
+	check(t, checker, `let a <- matrix(1, 2, 3)`)
+
+	if errors := check(t, checker, `let a <- diag(3); a %*% t(a) %*% matrix(1, 2, 2)`); 1 != len(errors) {
+		t.Fatalf("expected a single error, got=%v", errors)
+	}
+
+	if errors := check(t, checker, `a %*% t(a)`); 0 != len(errors) {
+		t.Fatalf("the program with errors changed the shapes, got=%v", errors)
+	}
+}
+
+func TestDeclarations(t *testing.T) {
+	declarations := "declare function mean(x: numeric[], na.rm: logical = FALSE): double; " +
+		"declare function weighted.mean(x: numeric[], w: numeric[], ...): double; "
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			declarations + `mean([1, 2.5, NA], na.rm = TRUE); weighted.mean(1, w = [1], trim = 0)`,
+			[]string{},
+		},
+		{
+			declarations + `mean("a")`,
+			[]string{
+				"x to `mean` must be numeric[], got character at line 1, column 147",
+			},
+		},
+		{
+			declarations + `mean([1, 2], trim = 0.1)`,
+			[]string{
+				"unused argument (trim = 0.1) at line 1, column 147",
+			},
+		},
+		{
+			declarations + `weighted.mean([1])`,
+			[]string{
+				"argument \"w\" is missing, with no default at line 1, column 156",
+			},
+		},
+		{
+			declarations + `let m <- mean([1]); mean([m, 2], na.rm = -m)`,
+			[]string{
+				"na.rm to `mean` must be logical, got double at line 1, column 167",
+			},
+		},
+		{
+			declarations + `let x <- "a"; let f <- function(x) { mean(x) }; if (TRUE) { x <- 1 }; mean(x)`,
+			[]string{},
+		},
+		{
+			declarations + `let mean <- function(x) { x }; mean("a")`,
+			[]string{},
+		},
+		{
+			declarations + `mean(["a", 1])`,
+			[]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		errors := check(t, InitializeChecker(), tt.input)
+
+		if len(errors) != len(tt.expected) {
+			t.Errorf("wrong number of errors for %q, got=%v", tt.input, errors)
+
+			continue
+		}
+
+		for index, expected := range tt.expected {
+			if errors[index] != expected {
+				t.Errorf("wrong error, expected=%q, got=%q", expected, errors[index])
+			}
+		}
+	}
+}
+
+func TestDeclarationFiles(t *testing.T) {
+	directory, err := ioutil.TempDir("", "typer")
+
+	if nil != err {
+		t.Fatalf("could not create temporary directory: %s", err)
+	}
+
+	defer os.RemoveAll(directory)
+
+	content := "declare function nchar(x: character[]): integer[]\ndeclare function sd(x: numeric[]): double"
+
+	if err := ioutil.WriteFile(filepath.Join(directory, "base.d.tr"), []byte(content), 0644); nil != err {
+		t.Fatalf("could not write fixture: %s", err)
+	}
+
+	checker := InitializeChecker()
+	checker.Path = filepath.Join(directory, "main.tr")
+
+	errors := check(t, checker, `import { nchar, sd } from "./base.d.tr"; sd(nchar(["a", "bc"])); nchar(sd([1]))`)
+
+	if 1 != len(errors) || "x to `nchar` must be character[], got double at line 1, column 71" != errors[0] {
+		t.Fatalf("wrong errors, got=%v", errors)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	declarations := "declare function parse_number(x: character): Result[double, character]; " +
+		"declare function mean(x: numeric[]): double; "
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			`let x <- Some(1); unwrap_or(x, 0) + 1`,
+			[]string{},
+		},
+		{
+			`let x <- Some(1); x + 1`,
+			[]string{
+				"Option[integer] may be None, handle both cases with unwrap_or, map or and_then before `+` at line 1, column 21",
+			},
+		},
+		{
+			declarations + `let n <- parse_number("1.5"); -n`,
+			[]string{
+				"Result[double, character] may be Err, handle both cases with unwrap_or, map or and_then before `-` at line 1, column 148",
+			},
+		},
+		{
+			declarations + `mean(parse_number("1")); mean(unwrap_or(parse_number("1"), 0))`,
+			[]string{
+				"x to `mean` must be numeric[], got Result[double, character] at line 1, column 122",
+			},
+		},
+		{
+			`let y <- map(Some(2), function(x) { x * 2 }); unwrap_or(y, 0) > unwrap_or(None(), 1); y == 4`,
+			[]string{
+				"Option[any] may be None, handle both cases with unwrap_or, map or and_then before `==` at line 1, column 89",
+			},
+		},
+		{
+			`unwrap_or(1, 0)`,
+			[]string{
+				"o to `unwrap_or` must be Option or Result, got integer at line 1, column 10",
+			},
+		},
+		{
+			`let Some <- function(x) { x }; Some(1) + 1`,
+			[]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		errors := check(t, InitializeChecker(), tt.input)
+
+		if len(errors) != len(tt.expected) {
+			t.Errorf("wrong number of errors for %q, got=%v", tt.input, errors)
+
+			continue
+		}
+
+		for index, expected := range tt.expected {
+			if errors[index] != expected {
+				t.Errorf("wrong error, expected=%q, got=%q", expected, errors[index])
+			}
+		}
+	}
+}
+
+func TestAlgebraicTypes(t *testing.T) {
+	shape := "type Shape = Circle(r: double) | Rect(w: double, h: double); "
+
+	tests := []struct {
+		input    string
+		errors   []string
+		warnings []string
+	}{
+		{
+			shape + `let s <- Circle(1); match (s) { Circle(r) -> r * r, Rect(w, h) -> w * h }`,
+			[]string{},
+			[]string{},
+		},
+		{
+			shape + `let s <- Rect(1, 2); match (s) { Circle(r) -> r * r }`,
+			[]string{},
+			[]string{
+				"match on Shape is not exhaustive, Rect not handled at line 1, column 83",
+			},
+		},
+		{
+			shape + `match (Circle(1)) { Circle(r) -> r, _ -> 0 }; match (Some(1)) { Some(x) -> x }`,
+			[]string{},
+			[]string{
+				"match on Option is not exhaustive, None not handled at line 1, column 108",
+			},
+		},
+		{
+			shape + `Circle("one"); match (Circle(1)) { Rect(w) -> w, Some(x) -> x }`,
+			[]string{
+				"r to `Circle` must be double, got character at line 1, column 68",
+				"pattern Rect takes 2 fields, got=1 at line 1, column 97",
+				"pattern Some is not an alternative of Shape at line 1, column 111",
+			},
+			[]string{
+				"match on Shape is not exhaustive, Circle not handled at line 1, column 77",
+			},
+		},
+		{
+			`let x <- Some(1); match (x) { Some(y) -> y + 1, None -> 0 }; match (x) { Some(y) -> y, Other(z) -> z }`,
+			[]string{
+				"pattern Other is not a constructor, only `_` matches anything at line 1, column 88",
+			},
+			[]string{},
+		},
+		{
+			`match (5) { x -> x + 1 }`,
+			[]string{
+				"pattern x is not a constructor, only `_` matches anything at line 1, column 13",
+			},
+			[]string{},
+		},
+		{
+			shape + `type Shape = Square(side: double); type Pair = Some(x: any) | Circle | Two | Two`,
+			[]string{
+				"duplicate type Shape (first defined at line 1, column 6) at line 1, column 67",
+				"duplicate constructor Some (first defined by Option) at line 1, column 109",
+				"duplicate constructor Circle (first defined at line 1, column 14) at line 1, column 124",
+				"duplicate constructor Two (first defined at line 1, column 133) at line 1, column 139",
+			},
+			[]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		checker := InitializeChecker()
+		errors := check(t, checker, tt.input)
+
+		if len(errors) != len(tt.errors) {
+			t.Errorf("wrong number of errors for %q, got=%v", tt.input, errors)
+
+			continue
+		}
+
+		for index, expected := range tt.errors {
+			if errors[index] != expected {
+				t.Errorf("wrong error, expected=%q, got=%q", expected, errors[index])
+			}
+		}
+
+		if len(checker.Warnings()) != len(tt.warnings) {
+			t.Errorf("wrong number of warnings for %q, got=%v", tt.input, checker.Warnings())
+
+			continue
+		}
+
+		for index, expected := range tt.warnings {
+			if checker.Warnings()[index] != expected {
+				t.Errorf("wrong warning, expected=%q, got=%q", expected, checker.Warnings()[index])
+			}
+		}
+	}
+}
+
+func TestGenerics(t *testing.T) {
+	declarations := "declare function f(x: character): any; " +
+		"declare function total<T: numeric>(xs: T[]): T; "
+
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			declarations + `let x <- head([1, 2]) + 1; f(head([1, 2])); f(tail(["a"]))`,
+			[]string{
+				"x to `f` must be character, got integer at line 1, column 116",
+				"x to `f` must be character, got character[] at line 1, column 133",
+			},
+		},
+		{
+			declarations + `push([1, 2], 2.5); push([1, 2], "a")`,
+			[]string{
+				"x to `push` must be integer, got character at line 1, column 111",
+			},
+		},
+		{
+			declarations + `total(["a", "b"]); f(total([1, 2.5]))`,
+			[]string{
+				"xs to `total` must be numeric[], got character[] at line 1, column 93",
+				"x to `f` must be character, got double at line 1, column 108",
+			},
+		},
+		{
+			declarations + `let first <- function<T>(xs: T[]): T { xs[0] }; f(first([1])); first(1, 2)`,
+			[]string{
+				"x to `f` must be character, got integer at line 1, column 137",
+				"unused argument (2) at line 1, column 156",
+			},
+		},
+		{
+			declarations + `let scale <- function<T: numeric>(x: T, by: double = 2): T { x * by }; scale("a"); scale(1, by = 3)`,
+			[]string{
+				"x to `scale` must be numeric, got character at line 1, column 164",
+			},
+		},
+		{
+			`type Numbers = double[]; declare function m(x: Numbers): double; m(["a"]); m([1, 2])`,
+			[]string{
+				"x to `m` must be double[], got character[] at line 1, column 67",
+			},
+		},
+		{
+			`type Named<T> = Result[T, character]; declare function p(x: character): Named[double]; p("1") + 1`,
+			[]string{
+				"Result[double, character] may be Err, handle both cases with unwrap_or, map or and_then before `+` at line 1, column 95",
+			},
+		},
+		{
+			declarations + `type Tree<T: numeric> = Leaf(value: T) | Node(left: Tree[T], right: Tree[T]); ` +
+				`Leaf("a"); match (Leaf(1)) { Leaf(v) -> f(v), Node(l, r) -> 0 }`,
+			[]string{
+				"value to `Leaf` must be numeric, got character at line 1, column 170",
+				"x to `f` must be character, got integer at line 1, column 207",
+			},
+		},
+		{
+			`let f <- function(x: bogus) { x }; let g <- function<T: bogus>(x: T) { x }; let h <- function<T>(x: U): T { x }`,
+			[]string{
+				"unknown type bogus at line 1, column 22",
+				"unknown type bogus at line 1, column 57",
+				"unknown type U at line 1, column 101",
+			},
+		},
+		{
+			`type Numbers = double[]; declare function m(x: Numbrs): double; type Tree = Leaf(value: Numbers) | Node(left: Tre)`,
+			[]string{
+				"unknown type Numbrs at line 1, column 48",
+				"unknown type Tre at line 1, column 111",
+			},
+		},
+		{
+			`type Numbers = double[]; type Numbers = character[]; type Tree = Leaf | Node; type Tree = double`,
+			[]string{
+				"duplicate type Numbers (first defined at line 1, column 6) at line 1, column 31",
+				"duplicate type Tree (first defined at line 1, column 59) at line 1, column 84",
+			},
+		},
+		{
+			declarations + `f(Find(function(x) { x > 1 }, [1, 2])); f(sample(["a", "b"], 1)); Reduce(function(a, b) { a + b }, [1], accumulate = 1)`,
+			[]string{
+				"x to `f` must be character, got integer at line 1, column 89",
+				"x to `f` must be character, got character[] at line 1, column 129",
+				"accumulate to `Reduce` must be logical, got integer at line 1, column 160",
+			},
+		},
+		{
+			`let len <- function(x) { 0 }; len(1, 2); head()`,
+			[]string{
+				"argument \"xs\" is missing, with no default at line 1, column 46",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		errors := check(t, InitializeChecker(), tt.input)
+
+		if len(errors) != len(tt.expected) {
+			t.Errorf("wrong number of errors for %q, got=%v", tt.input, errors)
+
+			continue
+		}
+
+		for index, expected := range tt.expected {
+			if errors[index] != expected {
+				t.Errorf("wrong error, expected=%q, got=%q", expected, errors[index])
+			}
+		}
+	}
+}
+
+func TestBuiltinSignatures(t *testing.T) {
+	for _, builtin := range object.Builtins {
+		if "" == builtin.Builtin.Signature {
+			continue
+		}
+
+		if _, ok := builtins[builtin.Name]; !ok {
+			t.Errorf("signature of %s does not parse, got=%q", builtin.Name, builtin.Builtin.Signature)
+		}
+	}
+}
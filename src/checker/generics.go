@@ -0,0 +1,362 @@
+package checker
+
+import (
+	"../ast"
+	"../lexer"
+	"../object"
+	"../parser"
+)
+
+// parse : the statements of source written for the checker itself, which are known to be right
+func parse(source string) []ast.Statement {
+	p := parser.InitializeParser(lexer.InitializeLexer(source))
+	program := p.ParseProgram()
+
+	if 0 != len(p.Errors()) {
+		return nil
+	}
+
+	return program.Statements
+}
+
+// signatures : the declarations of the builtins carrying a signature, checked just as those of declaration files
+func signatures() map[string]*ast.DeclareStatement {
+	declarations := map[string]*ast.DeclareStatement{}
+
+	for _, builtin := range object.Builtins {
+		if "" == builtin.Builtin.Signature {
+			continue
+		}
+
+		for _, statement := range parse("declare function " + builtin.Name + builtin.Builtin.Signature) {
+			declarations[builtin.Name] = statement.(*ast.DeclareStatement)
+		}
+	}
+
+	return declarations
+}
+
+// builtins : what the builtins are declared to be, every checker starting from them
+var builtins = signatures()
+
+// generics : the type parameters, by name
+func generics(parameters []*ast.TypeParameter) map[string]*ast.TypeParameter {
+	named := make(map[string]*ast.TypeParameter, len(parameters))
+
+	for _, parameter := range parameters {
+		named[parameter.Name] = parameter
+	}
+
+	return named
+}
+
+// vectorOf : a vector of the type, nil when it is a vector already, as vectors of vectors have no type
+func vectorOf(annotation *ast.Type) *ast.Type {
+	if annotation.Vector {
+		return nil
+	}
+
+	return &ast.Type{
+		Token:      annotation.Token,
+		Name:       annotation.Name,
+		Parameters: annotation.Parameters,
+		Vector:     true,
+	}
+}
+
+// element : the type of the elements of a vector of the type, a single value being a vector of length one
+func element(annotation *ast.Type) *ast.Type {
+	if !annotation.Vector {
+		return annotation
+	}
+
+	return &ast.Type{
+		Token:      annotation.Token,
+		Name:       annotation.Name,
+		Parameters: annotation.Parameters,
+	}
+}
+
+// substitute : the type with the type parameters replaced by the types bound to them; it is nil when it is a type
+// parameter that is not bound, any standing for those inside of it
+func substitute(annotation *ast.Type, bindings map[string]*ast.Type, generic map[string]*ast.TypeParameter) *ast.Type {
+	if nil == annotation {
+		return nil
+	}
+
+	if _, ok := generic[annotation.Name]; ok {
+		bound, ok := bindings[annotation.Name]
+
+		if !ok {
+			return nil
+		}
+
+		if annotation.Vector {
+			return vectorOf(bound)
+		}
+
+		return bound
+	}
+
+	if 0 == len(annotation.Parameters) {
+		return annotation
+	}
+
+	substituted := &ast.Type{
+		Token:  annotation.Token,
+		Name:   annotation.Name,
+		Vector: annotation.Vector,
+	}
+
+	for _, parameter := range annotation.Parameters {
+		if parameter = substitute(parameter, bindings, generic); nil == parameter {
+			parameter = anyType(annotation.Token)
+		}
+
+		substituted.Parameters = append(substituted.Parameters, parameter)
+	}
+
+	return substituted
+}
+
+// concrete : whether the type names none of the type parameters
+func concrete(annotation *ast.Type, generic map[string]*ast.TypeParameter) bool {
+	if _, ok := generic[annotation.Name]; ok {
+		return false
+	}
+
+	for _, parameter := range annotation.Parameters {
+		if !concrete(parameter, generic) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// primitives : the types of the values every program has
+var primitives = map[string]bool{
+	"any":       true,
+	"numeric":   true,
+	"integer":   true,
+	"double":    true,
+	"character": true,
+	"logical":   true,
+	"list":      true,
+	"function":  true,
+}
+
+// known : reports the names the type gives which are neither types, aliases nor the type parameters in scope
+func (c *Checker) known(annotation *ast.Type, generic map[string]*ast.TypeParameter) {
+	if nil == annotation {
+		return
+	}
+
+	if _, ok := generic[annotation.Name]; !ok && !primitives[annotation.Name] && nil == c.aliases[annotation.Name] && nil == c.named(annotation.Name) {
+		c.error(annotation.Token, "unknown type %s", annotation.Name)
+	}
+
+	for _, parameter := range annotation.Parameters {
+		c.known(parameter, generic)
+	}
+}
+
+// knownSignature : the bounds of the type parameters, the types of the parameters and the result must all be known
+func (c *Checker) knownSignature(typeParameters []*ast.TypeParameter, types []*ast.Type, result *ast.Type) {
+	generic := generics(typeParameters)
+
+	for _, parameter := range typeParameters {
+		c.known(parameter.Bound, generic)
+	}
+
+	for _, annotation := range append(types, result) {
+		c.known(annotation, generic)
+	}
+}
+
+// expand : the type with the aliases it names replaced by the types they stand for, but for the names the type
+// parameters hide; nil when it can not be told, as for a vector of an alias of a vector
+func (c *Checker) expand(annotation *ast.Type, hidden map[string]*ast.TypeParameter) *ast.Type {
+	if nil == annotation {
+		return nil
+	}
+
+	expanded := &ast.Type{
+		Token:  annotation.Token,
+		Name:   annotation.Name,
+		Vector: annotation.Vector,
+	}
+
+	for _, parameter := range annotation.Parameters {
+		if parameter = c.expand(parameter, hidden); nil == parameter {
+			parameter = anyType(annotation.Token)
+		}
+
+		expanded.Parameters = append(expanded.Parameters, parameter)
+	}
+
+	alias, ok := c.aliases[annotation.Name]
+
+	if _, hides := hidden[annotation.Name]; !ok || hides {
+		return expanded
+	}
+
+	bindings := map[string]*ast.Type{}
+
+	for index, parameter := range alias.TypeParameters {
+		if index < len(expanded.Parameters) {
+			bindings[parameter.Name] = expanded.Parameters[index]
+		}
+	}
+
+	target := substitute(alias.Alias, bindings, generics(alias.TypeParameters))
+
+	if nil == target {
+		return nil
+	}
+
+	if annotation.Vector {
+		return vectorOf(target)
+	}
+
+	return target
+}
+
+// expanded : the declaration with the aliases its types name replaced by the types they stand for
+func (c *Checker) expanded(declaration *ast.DeclareStatement) *ast.DeclareStatement {
+	hidden := generics(declaration.TypeParameters)
+	expanded := *declaration
+
+	expanded.TypeParameters = make([]*ast.TypeParameter, len(declaration.TypeParameters))
+
+	for index, parameter := range declaration.TypeParameters {
+		expanded.TypeParameters[index] = &ast.TypeParameter{
+			Token: parameter.Token,
+			Name:  parameter.Name,
+			Bound: c.expand(parameter.Bound, hidden),
+		}
+	}
+
+	expanded.Types = make([]*ast.Type, len(declaration.Types))
+
+	for index, annotation := range declaration.Types {
+		expanded.Types[index] = c.expand(annotation, hidden)
+	}
+
+	expanded.Result = c.expand(declaration.Result, hidden)
+
+	return &expanded
+}
+
+// alias : the type the alias stands for is expanded right away, so an alias can only name those defined before it
+func (c *Checker) alias(statement *ast.TypeStatement) {
+	expanded := *statement
+	expanded.Alias = c.expand(statement.Alias, generics(statement.TypeParameters))
+
+	if nil == expanded.Alias {
+		expanded.Alias = anyType(statement.Alias.Token)
+	}
+
+	c.aliases[statement.Name.Value] = &expanded
+}
+
+// signature : a function literal telling the type of anything is checked just as a declared function
+func signature(name *ast.Identifier, function *ast.FunctionLiteral) *ast.DeclareStatement {
+	declaration := &ast.DeclareStatement{
+		Token:          function.Token,
+		Name:           name,
+		TypeParameters: function.TypeParameters,
+		Parameters:     function.Parameters,
+		Types:          make([]*ast.Type, len(function.Parameters)),
+		Defaults:       make([]ast.Expression, len(function.Parameters)),
+		Result:         function.Result,
+	}
+
+	for index := range function.Parameters {
+		declaration.Types[index], declaration.Defaults[index] = function.Type(index), function.Default(index)
+	}
+
+	return declaration
+}
+
+// infer : binds the type parameters the expected type names to the types the actual one gives them; when a type
+// parameter is given two types, it takes the one the other can stand for, as double for integer, while a type not
+// meeting the bound of the type parameter is left out, the type parameter being given back
+func infer(expected *ast.Type, actual *ast.Type, generic map[string]*ast.TypeParameter, bindings map[string]*ast.Type) *ast.TypeParameter {
+	typeParameter, ok := generic[expected.Name]
+
+	if !ok {
+		if expected.Name != actual.Name {
+			return nil
+		}
+
+		for index, inner := range expected.Parameters {
+			if index >= len(actual.Parameters) {
+				break
+			}
+
+			if failed := infer(inner, actual.Parameters[index], generic, bindings); nil != failed {
+				return failed
+			}
+		}
+
+		return nil
+	}
+
+	given := actual
+
+	if expected.Vector {
+		given = element(actual)
+	}
+
+	if "any" == given.Name {
+		return nil
+	}
+
+	if nil != typeParameter.Bound && !assignable(typeParameter.Bound, element(given)) {
+		return typeParameter
+	}
+
+	if previous, ok := bindings[typeParameter.Name]; !ok || assignable(given, previous) {
+		bindings[typeParameter.Name] = given
+	}
+
+	return nil
+}
+
+// instantiate : the types bound to the type parameters of the declaration by the arguments given to them, values
+// being the arguments bound to each parameter, nil for those left out, along with the parameters whose arguments do
+// not meet the bounds, which are reported when report is set
+func (c *Checker) instantiate(call *ast.CallExpression, declaration *ast.DeclareStatement, values []ast.Expression, report bool) (map[string]*ast.Type, map[int]bool) {
+	bindings, failures := map[string]*ast.Type{}, map[int]bool{}
+	generic := generics(declaration.TypeParameters)
+
+	if 0 == len(generic) {
+		return bindings, failures
+	}
+
+	for index, parameter := range declaration.Parameters {
+		expected := declaration.Types[index]
+
+		if nil == values[index] || nil == expected {
+			continue
+		}
+
+		actual := c.typeOf(values[index])
+
+		if nil == actual {
+			continue
+		}
+
+		if failed := infer(expected, actual, generic, bindings); nil != failed {
+			failures[index] = true
+
+			if report {
+				bounded := substitute(expected, map[string]*ast.Type{failed.Name: failed.Bound}, generic)
+				c.error(call.Token, "%s to `%s` must be %s, got %s", parameter, declaration.Name, bounded, actual)
+			}
+		}
+	}
+
+	return bindings, failures
+}
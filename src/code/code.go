@@ -42,6 +42,18 @@ const (
 	OpClosure
 	OpGetFreeVariable
 	OpCurrentClosure
+	OpMatrixIndex
+	OpMatrixMultiply
+	OpCallNamed
+	OpJumpBound
+	OpGetDot
+	OpPushHandler
+	OpPushCallingHandler
+	OpPopHandler
+	OpSignal
+	OpTestTag
+	OpDestructure
+	OpNoMatch
 )
 
 // Definition :
@@ -191,6 +203,69 @@ var definitions = map[Opcode]*Definition{
 		"OpCurrentClosure",
 		[]int{},
 	},
+	OpMatrixIndex: {
+		"OpMatrixIndex",
+		[]int{},
+	},
+	OpMatrixMultiply: {
+		"OpMatrixMultiply",
+		[]int{},
+	},
+	OpCallNamed: {
+		"OpCallNamed",
+		[]int{
+			1,
+			2,
+		},
+	},
+	OpJumpBound: {
+		"OpJumpBound",
+		[]int{
+			1,
+			2,
+		},
+	},
+	OpGetDot: {
+		"OpGetDot",
+		[]int{
+			2,
+		},
+	},
+	OpPushHandler: {
+		"OpPushHandler",
+		[]int{
+			2,
+			2,
+		},
+	},
+	OpPushCallingHandler: {
+		"OpPushCallingHandler",
+		[]int{},
+	},
+	OpPopHandler: {
+		"OpPopHandler",
+		[]int{},
+	},
+	OpSignal: {
+		"OpSignal",
+		[]int{},
+	},
+	OpTestTag: {
+		"OpTestTag",
+		[]int{
+			2,
+		},
+	},
+	OpDestructure: {
+		"OpDestructure",
+		[]int{
+			1,
+		},
+	},
+	OpNoMatch: {
+		"OpNoMatch",
+		[]int{},
+	},
 }
 
 // fmtInstruction :
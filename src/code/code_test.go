@@ -49,6 +49,30 @@ func TestMake(t *testing.T) {
 				255,
 			},
 		},
+		{
+			OpPushHandler,
+			[]int{
+				300,
+				0,
+			},
+			[]byte{
+				byte(OpPushHandler),
+				1,
+				44,
+				0,
+				0,
+			},
+		},
+		{
+			OpDestructure,
+			[]int{
+				2,
+			},
+			[]byte{
+				byte(OpDestructure),
+				2,
+			},
+		},
 	}
 
 	for _, tt := range tests {
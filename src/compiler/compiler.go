@@ -2,9 +2,11 @@ package compiler
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"../ast"
 	"../code"
+	"../module"
 	"../object"
 )
 
@@ -13,6 +15,7 @@ type CompilationsScope struct {
 	instructions        code.Instructions
 	lastInstruction     EmittedInstruction
 	previousInstruction EmittedInstruction
+	positions           map[int]object.Position
 }
 
 // Compiler :
@@ -30,6 +33,9 @@ type Compiler struct {
 type Bytecode struct {
 	Instructions code.Instructions
 	Constants    []object.Object
+	Positions    map[int]object.Position
+	// the slots of the globals by their names, so do.call finds a function by its name
+	Globals map[string]int
 }
 
 // EmittedInstruction :
@@ -44,6 +50,7 @@ func (c *Compiler) enterScope() {
 		instructions:        code.Instructions{},
 		lastInstruction:     EmittedInstruction{},
 		previousInstruction: EmittedInstruction{},
+		positions:           map[int]object.Position{},
 	}
 
 	c.scopes = append(c.scopes, scope)
@@ -53,15 +60,16 @@ func (c *Compiler) enterScope() {
 }
 
 // leaveScope :
-func (c *Compiler) leaveScope() code.Instructions {
+func (c *Compiler) leaveScope() (code.Instructions, map[int]object.Position) {
 	instructions := c.currentInstructions()
+	positions := c.scopes[c.scopeIndex].positions
 
 	c.scopes = c.scopes[:len(c.scopes)-1]
 	c.scopeIndex--
 
 	c.symbolTable = c.symbolTable.Outer
 
-	return instructions
+	return instructions, positions
 }
 
 // currentInstructions :
@@ -179,6 +187,31 @@ func (c *Compiler) defineAssignmentScope(symbol Symbol) {
 	}
 }
 
+// compileDot :
+func (c *Compiler) compileDot(name string, position int) error {
+	symbol, ok := c.symbolTable.Resolve("...")
+
+	if !ok {
+		return fmt.Errorf("%s used in an incorrect context, no ... to look in", name)
+	}
+
+	c.loadSymbol(symbol)
+	c.emit(code.OpGetDot, position)
+
+	return nil
+}
+
+// compileOptional : empty positions, as in `frame[, 1]`, are compiled to NULL
+func (c *Compiler) compileOptional(node ast.Expression) error {
+	if nil == node {
+		c.emit(code.OpNull)
+
+		return nil
+	}
+
+	return c.Compile(node)
+}
+
 // Compile :
 func (c *Compiler) Compile(node ast.Node) error {
 	switch node := node.(type) {
@@ -191,6 +224,12 @@ func (c *Compiler) Compile(node ast.Node) error {
 			}
 		}
 
+	case *ast.ImportStatement:
+		return c.compileImport(node)
+
+	case *ast.DeclareStatement:
+		return c.compileDeclare(node)
+
 	case *ast.ExpressionStatement:
 		err := c.Compile(node.Expression)
 
@@ -240,6 +279,8 @@ func (c *Compiler) Compile(node ast.Node) error {
 			c.emit(code.OpMultiply)
 		case "/":
 			c.emit(code.OpDivide)
+		case "%*%":
+			c.emit(code.OpMatrixMultiply)
 		case ">":
 			c.emit(code.OpGreaterThan)
 		case "==":
@@ -257,6 +298,13 @@ func (c *Compiler) Compile(node ast.Node) error {
 
 		c.emit(code.OpConstant, c.addConstant(integer))
 
+	case *ast.DoubleLiteral:
+		double := &object.Double{
+			Value: node.Value,
+		}
+
+		c.emit(code.OpConstant, c.addConstant(double))
+
 	case *ast.Boolean:
 		if node.Value {
 			c.emit(code.OpTrue)
@@ -264,6 +312,12 @@ func (c *Compiler) Compile(node ast.Node) error {
 			c.emit(code.OpFalse)
 		}
 
+	case *ast.NotAvailable:
+		c.emit(code.OpConstant, c.addConstant(object.NA))
+
+	case *ast.Null:
+		c.emit(code.OpNull)
+
 	case *ast.PrefixExpression:
 		err := c.Compile(node.Right)
 
@@ -322,6 +376,12 @@ func (c *Compiler) Compile(node ast.Node) error {
 		afterAlternativePosition := len(c.currentInstructions())
 		c.changeOperand(jumpPosition, afterAlternativePosition)
 
+	case *ast.MatchExpression:
+		return c.compileMatch(node)
+
+	case *ast.TypeStatement:
+		return c.compileType(node)
+
 	case *ast.BlockStatement:
 		for _, statement := range node.Statements {
 			err := c.Compile(statement)
@@ -332,6 +392,10 @@ func (c *Compiler) Compile(node ast.Node) error {
 		}
 
 	case *ast.ConstStatement:
+		if err := c.export(node.Name.Value, node.Exported); nil != err {
+			return err
+		}
+
 		value, ok := c.symbolTable.Resolve(node.Name.Value)
 
 		if !ok {
@@ -357,16 +421,39 @@ func (c *Compiler) Compile(node ast.Node) error {
 		c.defineAssignmentScope(value)
 
 	case *ast.LetStatement:
-		symbol := c.symbolTable.Define(node.Name.Value, false)
-		err := c.Compile(node.Value)
+		if err := c.export(node.Name.Value, node.Exported); nil != err {
+			return err
+		}
 
-		if nil != err {
+		// functions are bound first, so they can call themselves; any other value is computed before, as in
+		// `let s <- s + 1`, from what the name was bound to
+		if _, ok := node.Value.(*ast.FunctionLiteral); ok {
+			symbol := c.symbolTable.Define(node.Name.Value, false)
+
+			if err := c.Compile(node.Value); nil != err {
+				return err
+			}
+
+			c.defineAssignmentScope(symbol)
+
+			break
+		}
+
+		if err := c.Compile(node.Value); nil != err {
 			return err
 		}
 
-		c.defineAssignmentScope(symbol)
+		c.defineAssignmentScope(c.symbolTable.Define(node.Name.Value, false))
 
 	case *ast.Identifier:
+		if "..." == node.Value {
+			return fmt.Errorf("'...' used in an incorrect context")
+		}
+
+		if position, ok := object.DotPosition(node.Value); ok {
+			return c.compileDot(node.Value, position)
+		}
+
 		symbol, ok := c.symbolTable.Resolve(node.Value)
 
 		if !ok {
@@ -400,13 +487,25 @@ func (c *Compiler) Compile(node ast.Node) error {
 			return err
 		}
 
-		err = c.Compile(node.Index)
+		err = c.compileOptional(node.Index)
 
 		if nil != err {
 			return err
 		}
 
-		c.emit(code.OpIndex)
+		if !node.Matrix {
+			c.emit(code.OpIndex)
+
+			return nil
+		}
+
+		err = c.compileOptional(node.Column)
+
+		if nil != err {
+			return err
+		}
+
+		c.emit(code.OpMatrixIndex)
 
 	case *ast.FunctionLiteral:
 		c.enterScope()
@@ -415,8 +514,32 @@ func (c *Compiler) Compile(node ast.Node) error {
 			c.symbolTable.DefineFunctionName(node.Name)
 		}
 
-		for _, parameter := range node.Parameters {
+		parameters := make([]string, len(node.Parameters))
+		defaults := make([]bool, len(node.Parameters))
+
+		variadic := false
+
+		for index, parameter := range node.Parameters {
 			c.symbolTable.Define(parameter.Value, true)
+			parameters[index] = parameter.Value
+			variadic = variadic || "..." == parameter.Value
+		}
+
+		// the default values are worked out on entry, for the parameters the call left unbound, each one after those
+		// it names
+		for _, index := range ast.DefaultOrder(node.Parameters, node.Defaults) {
+			value := node.Default(index)
+			defaults[index] = true
+			jumpBoundPosition := c.emit(code.OpJumpBound, index, 9999)
+
+			err := c.Compile(value)
+
+			if nil != err {
+				return err
+			}
+
+			c.emit(code.OpSetLocal, index)
+			c.replaceInstruction(jumpBoundPosition, code.Make(code.OpJumpBound, index, len(c.currentInstructions())))
 		}
 
 		err := c.Compile(node.Body)
@@ -435,7 +558,8 @@ func (c *Compiler) Compile(node ast.Node) error {
 
 		freeVariableSymbols := c.symbolTable.FreeVariableSymbol
 		numberOfLocals := c.symbolTable.numberDefinitions
-		instructions := c.leaveScope()
+		locals, freeVariables := c.symbolTable.indices(LocalScope), c.symbolTable.indices(FreeVariableScope)
+		instructions, positions := c.leaveScope()
 
 		for _, symbol := range freeVariableSymbols {
 			c.loadSymbol(symbol)
@@ -445,6 +569,12 @@ func (c *Compiler) Compile(node ast.Node) error {
 			Instructions:       instructions,
 			NumberOfLocals:     numberOfLocals,
 			NumberOfParameters: len(node.Parameters),
+			Positions:          positions,
+			Parameters:         parameters,
+			Defaults:           defaults,
+			Variadic:           variadic,
+			Locals:             locals,
+			FreeVariables:      freeVariables,
 		}
 
 		functionIndex := c.addConstant(compiledFunction)
@@ -460,21 +590,66 @@ func (c *Compiler) Compile(node ast.Node) error {
 		c.emit(code.OpReturnValue)
 
 	case *ast.CallExpression:
+		if identifier, ok := node.Function.(*ast.Identifier); ok && object.IsHandling(identifier.Value) {
+			if _, defined := c.symbolTable.Resolve(identifier.Value); !defined {
+				return c.compileHandling(identifier.Value, node)
+			}
+		}
+
+		node = c.resolveFunctionName(node)
 		err := c.Compile(node.Function)
 
 		if nil != err {
 			return err
 		}
 
+		names := []object.Object{}
+		named := false
+
 		for _, parameter := range node.Parameters {
+			name := ""
+
+			if argument, ok := parameter.(*ast.NamedArgument); ok {
+				name, parameter, named = argument.Name, argument.Value, true
+			}
+
+			// `...` is spread into the arguments it holds when the call is made
+			if identifier, ok := parameter.(*ast.Identifier); ok && "..." == identifier.Value && "" == name {
+				symbol, ok := c.symbolTable.Resolve("...")
+
+				if !ok {
+					return fmt.Errorf("'...' used in an incorrect context")
+				}
+
+				c.loadSymbol(symbol)
+				names = append(names, &object.String{Value: "..."})
+				named = true
+
+				continue
+			}
+
 			err := c.Compile(parameter)
 
 			if nil != err {
 				return err
 			}
+
+			names = append(names, &object.String{Value: name})
 		}
 
-		c.emit(code.OpCall, len(node.Parameters))
+		var position int
+
+		if named {
+			position = c.emit(code.OpCallNamed, len(node.Parameters), c.addConstant(&object.Array{Elements: names}))
+		} else {
+			position = c.emit(code.OpCall, len(node.Parameters))
+		}
+
+		// builtins can tell where they were called from
+		c.scopes[c.scopeIndex].positions[position] = object.Position{
+			Line:   node.Token.Line,
+			Column: node.Token.Column,
+		}
 
 	case *ast.PointFreeExpression:
 		for _, function := range node.ToCompose {
@@ -503,11 +678,119 @@ func (c *Compiler) Compile(node ast.Node) error {
 	return nil
 }
 
+// export : only the bindings of a module, not those of its functions, can be exported
+func (c *Compiler) export(name string, exported bool) error {
+	if !exported {
+		return nil
+	}
+
+	if 0 != c.scopeIndex {
+		return fmt.Errorf("export is only allowed at the top level")
+	}
+
+	c.symbolTable.exports[name] = true
+
+	return nil
+}
+
+// compileDeclare : a declared function that is not already there, as the builtins are, is bound to one telling it
+// can not be found when called; declarations are always exported, so a declaration file can be imported
+func (c *Compiler) compileDeclare(node *ast.DeclareStatement) error {
+	if 0 != c.scopeIndex {
+		return fmt.Errorf("declare is only allowed at the top level")
+	}
+
+	name := node.Name.Value
+
+	if _, ok := c.symbolTable.Resolve(name); !ok {
+		parameters := make([]string, len(node.Parameters))
+
+		for index, parameter := range node.Parameters {
+			parameters[index] = parameter.Value
+		}
+
+		symbol := c.symbolTable.Define(name, true)
+		c.emit(code.OpConstant, c.addConstant(object.Declared(name, parameters)))
+		c.defineAssignmentScope(symbol)
+	}
+
+	return c.export(name, true)
+}
+
+// compileImport : a module is compiled the first time it is imported, right where it is, in a global namespace of
+// its own; the names imported are then bound to the very globals the module defined
+func (c *Compiler) compileImport(node *ast.ImportStatement) error {
+	if 0 != c.scopeIndex {
+		return fmt.Errorf("import is only allowed at the top level")
+	}
+
+	path, err := module.Resolve(c.symbolTable.Path, node.Path)
+
+	if nil != err {
+		return err
+	}
+
+	namespace, ok := c.symbolTable.session.modules[path]
+
+	if !ok {
+		if namespace, err = c.compileModule(path); nil != err {
+			return err
+		}
+	}
+
+	for _, name := range node.Names {
+		symbol, ok := namespace.store[name.Value]
+
+		if !ok || !namespace.exports[name.Value] {
+			return fmt.Errorf("%s is not exported by %s", name.Value, node.Path)
+		}
+
+		symbol.Constant = true
+		c.symbolTable.store[name.Value] = symbol
+	}
+
+	return nil
+}
+
+// compileModule : modules are kept once compiled, so the next imports only bind names
+func (c *Compiler) compileModule(path string) (*SymbolTable, error) {
+	importers := c.symbolTable.importers()
+
+	for _, importer := range importers {
+		if importer == path {
+			return nil, module.Cycle(path, importers)
+		}
+	}
+
+	program, err := module.Load(path)
+
+	if nil != err {
+		return nil, err
+	}
+
+	importer := c.symbolTable
+	c.symbolTable = importer.namespace(path)
+
+	err = c.Compile(program)
+	namespace := c.symbolTable
+	c.symbolTable = importer
+
+	if nil != err {
+		return nil, fmt.Errorf("%s, in %s", err, filepath.Base(path))
+	}
+
+	importer.session.modules[path] = namespace
+
+	return namespace, nil
+}
+
 // Bytecode :
 func (c *Compiler) Bytecode() *Bytecode {
 	return &Bytecode{
 		Instructions: c.currentInstructions(),
 		Constants:    c.constants,
+		Positions:    c.scopes[c.scopeIndex].positions,
+		Globals:      c.symbolTable.indices(GlobalScope),
 	}
 }
 
@@ -517,6 +800,7 @@ func InitializeCompiler() *Compiler {
 		instructions:        code.Instructions{},
 		lastInstruction:     EmittedInstruction{},
 		previousInstruction: EmittedInstruction{},
+		positions:           map[int]object.Position{},
 	}
 
 	symbolTable := InitializeSymbolTable()
@@ -542,3 +826,239 @@ func InitializeWithState(s *SymbolTable, constants []object.Object) *Compiler {
 
 	return compiler
 }
+
+// resolveFunctionName : the name given as what to the `do.call` builtin is looked up here, just like an identifier
+// would be, when it is a literal; the virtual machine looks up the others
+func (c *Compiler) resolveFunctionName(node *ast.CallExpression) *ast.CallExpression {
+	identifier, ok := node.Function.(*ast.Identifier)
+
+	if !ok || "do.call" != identifier.Value || 0 == len(node.Parameters) {
+		return node
+	}
+
+	if symbol, ok := c.symbolTable.Resolve(identifier.Value); !ok || BuiltinScope != symbol.Scope {
+		return node
+	}
+
+	index, what := 0, node.Parameters[0]
+
+	for position, parameter := range node.Parameters {
+		if argument, ok := parameter.(*ast.NamedArgument); ok && "what" == argument.Name {
+			index, what = position, argument.Value
+		}
+	}
+
+	if _, named := what.(*ast.NamedArgument); named {
+		return node
+	}
+
+	name, ok := what.(*ast.StringLiteral)
+
+	if !ok {
+		return node
+	}
+
+	if _, ok := c.symbolTable.Resolve(name.Value); !ok {
+		return node
+	}
+
+	parameters := append([]ast.Expression{}, node.Parameters...)
+	function := &ast.Identifier{
+		Token: name.Token,
+		Value: name.Value,
+	}
+
+	if argument, ok := parameters[index].(*ast.NamedArgument); ok {
+		parameters[index] = &ast.NamedArgument{
+			Token: argument.Token,
+			Name:  argument.Name,
+			Value: function,
+		}
+	} else {
+		parameters[index] = function
+	}
+
+	resolved := *node
+	resolved.Parameters = parameters
+
+	return &resolved
+}
+
+// compileHandling : `tryCatch(expr, error = function(e) ..., finally = ...)` and `withCallingHandlers(expr, ...)`;
+// the handlers of the classes the virtual machine takes them for, NULL standing for those not given, are pushed
+// along with where to go once the evaluation unwound to the tryCatch -- catch, where the handler called is given the
+// condition, and the copy of finally after which the condition is signalled again, 0 when there is none
+func (c *Compiler) compileHandling(function string, node *ast.CallExpression) error {
+	expression, handlers, finally, err := object.HandlerArguments(function, node.Parameters)
+
+	if nil != err {
+		return err
+	}
+
+	for _, class := range []string{"error", "warning", "message", "condition"} {
+		handler, ok := handlers[class]
+
+		if !ok {
+			c.emit(code.OpNull)
+
+			continue
+		}
+
+		if err := c.Compile(handler); nil != err {
+			return err
+		}
+	}
+
+	var push int
+
+	if "withCallingHandlers" == function {
+		c.emit(code.OpPushCallingHandler)
+	} else {
+		push = c.emit(code.OpPushHandler, 9999, 9999)
+	}
+
+	if nil == expression {
+		c.emit(code.OpNull)
+	} else if err := c.Compile(expression); nil != err {
+		return err
+	}
+
+	c.emit(code.OpPopHandler)
+
+	if "withCallingHandlers" == function {
+		return nil
+	}
+
+	catch, rethrow := len(c.currentInstructions()), 0
+
+	if nil != finally {
+		if err := c.Compile(finally); nil != err {
+			return err
+		}
+
+		c.emit(code.OpPop)
+		jump := c.emit(code.OpJump, 9999)
+		rethrow = len(c.currentInstructions())
+
+		if err := c.Compile(finally); nil != err {
+			return err
+		}
+
+		c.emit(code.OpPop)
+		c.emit(code.OpSignal)
+		c.changeOperand(jump, len(c.currentInstructions()))
+	}
+
+	c.replaceInstruction(push, code.Make(code.OpPushHandler, catch, rethrow))
+
+	return nil
+}
+
+// compileType : every alternative of the type is bound to the constant builtin making it
+func (c *Compiler) compileType(node *ast.TypeStatement) error {
+	for _, constructor := range node.Constructors {
+		name := constructor.Name.Value
+
+		if err := c.export(name, node.Exported); nil != err {
+			return err
+		}
+
+		if symbol, ok := c.symbolTable.Resolve(name); ok && symbol.Constant {
+			return fmt.Errorf("overwrite previously defined value '%s' is not allowed", name)
+		}
+
+		fields := []string{}
+
+		for _, field := range constructor.Fields {
+			fields = append(fields, field.Value)
+		}
+
+		symbol := c.symbolTable.Define(name, true)
+		c.emit(code.OpConstant, c.addConstant(object.NewConstructor(node.Name.Value, name, fields)))
+		c.defineAssignmentScope(symbol)
+	}
+
+	return nil
+}
+
+// compileMatch : the subject stays on the stack while OpTestTag checks it against each pattern in turn; the arm it
+// fits destructures it, binding its fields, then jumps past the others with the value of its body
+func (c *Compiler) compileMatch(node *ast.MatchExpression) error {
+	if err := c.Compile(node.Subject); nil != err {
+		return err
+	}
+
+	ends := []int{}
+	exhaustive := false
+
+	for _, arm := range node.Arms {
+		next := -1
+
+		if arm.IsWildcard() {
+			c.emit(code.OpPop)
+		} else {
+			tag := c.addConstant(&object.String{
+				Value: arm.Constructor.Value,
+			})
+
+			c.emit(code.OpTestTag, tag)
+			next = c.emit(code.OpJumpNotTruthy, 9999)
+
+			if nil == arm.Fields {
+				c.emit(code.OpPop)
+			} else {
+				c.emit(code.OpDestructure, len(arm.Fields))
+			}
+
+			// the fields are pushed in order, so the last one is bound first
+			for index := len(arm.Fields) - 1; index >= 0; index-- {
+				name := arm.Fields[index].Value
+
+				if "_" == name {
+					c.emit(code.OpPop)
+
+					continue
+				}
+
+				// the fields are bound just as by let, which constants can not be
+				if symbol, ok := c.symbolTable.Resolve(name); ok && symbol.Constant {
+					return fmt.Errorf("overwrite previously defined value '%s' is not allowed", name)
+				}
+
+				c.defineAssignmentScope(c.symbolTable.Define(name, false))
+			}
+		}
+
+		body := len(c.currentInstructions())
+
+		if err := c.Compile(arm.Body); nil != err {
+			return err
+		}
+
+		if body < len(c.currentInstructions()) && c.lastInstructionIs(code.OpPop) {
+			c.removeLastPop()
+		} else {
+			c.emit(code.OpNull)
+		}
+
+		ends = append(ends, c.emit(code.OpJump, 9999))
+
+		if -1 == next {
+			exhaustive = true
+
+			break
+		}
+
+		c.changeOperand(next, len(c.currentInstructions()))
+	}
+
+	if !exhaustive {
+		c.emit(code.OpNoMatch)
+	}
+
+	for _, end := range ends {
+		c.changeOperand(end, len(c.currentInstructions()))
+	}
+
+	return nil
+}
@@ -42,7 +42,7 @@ func testInstructions(expected []code.Instructions, actual code.Instructions) er
 	concatted := concatInstructions(expected)
 
 	if len(actual) != len(concatted) {
-		return fmt.Errorf("wrong instructions length, want=%q, got%q", len(actual), len(concatted))
+		return fmt.Errorf("wrong instructions length, want=%d, got=%d", len(concatted), len(actual))
 	}
 
 	for index, instruction := range concatted {
@@ -98,6 +98,12 @@ func testConstants(t *testing.T, expected []interface{}, actual []object.Object)
 			if nil != err {
 				return fmt.Errorf("constant %d - testInteger object failed: %s", index, err)
 			}
+		case float64:
+			result, ok := actual[index].(*object.Double)
+
+			if !ok || result.Value != constant {
+				return fmt.Errorf("constant %d - not Double %f, got=%T (%+v)", index, constant, actual[index], actual[index])
+			}
 		case string:
 			err := testStringObject(constant, actual[index])
 
@@ -674,6 +680,21 @@ func TestIndexExpressions(t *testing.T) {
 				code.Make(code.OpPop),
 			},
 		},
+		{
+			input: "[1.5][, 0]",
+			expectedConstants: []interface{}{
+				1.5,
+				0,
+			},
+			expectedInstructions: []code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpArray, 1),
+				code.Make(code.OpNull),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpMatrixIndex),
+				code.Make(code.OpPop),
+			},
+		},
 	}
 
 	runCompilerTests(t, tests)
@@ -1517,3 +1538,41 @@ func TestHighOrderCall(t *testing.T) {
 
 	runCompilerTests(t, tests)
 }
+
+// TestCallPositions :
+func TestCallPositions(t *testing.T) {
+	program := parse("len([1]);\nlet f <- function() {\n  len([2]);\n};")
+	compiler := InitializeCompiler()
+
+	if err := compiler.Compile(program); nil != err {
+		t.Fatalf("compiler error: %s", err)
+	}
+
+	bytecode := compiler.Bytecode()
+	expected := object.Position{
+		Line:   1,
+		Column: 4,
+	}
+
+	// OpGetBuiltin, OpConstant and OpArray come before the call
+	if position := bytecode.Positions[8]; position != expected {
+		t.Errorf("wrong call position, expected=%s, got=%s", expected, position)
+	}
+
+	for _, constant := range bytecode.Constants {
+		function, ok := constant.(*object.CompiledFunction)
+
+		if !ok {
+			continue
+		}
+
+		expected = object.Position{
+			Line:   3,
+			Column: 6,
+		}
+
+		if position := function.Positions[8]; position != expected {
+			t.Errorf("wrong call position inside function, expected=%s, got=%s", expected, position)
+		}
+	}
+}
@@ -27,6 +27,19 @@ type SymbolTable struct {
 	numberDefinitions int
 
 	FreeVariableSymbol []Symbol
+
+	// every module has a global namespace of its own, Path being the file it was loaded from -- "" for the main
+	// program -- and importer the namespace that imported it first; all of them share the session
+	Path     string
+	importer *SymbolTable
+	exports  map[string]bool
+	session  *session
+}
+
+// session : what the global namespaces of a run share, the slots of the globals being handed out to all of them
+type session struct {
+	globals int
+	modules map[string]*SymbolTable
 }
 
 // defineFreeVariable :
@@ -67,6 +80,8 @@ func (s *SymbolTable) Define(name string, constant bool) Symbol {
 
 	if nil == s.Outer {
 		symbol.Scope = GlobalScope
+		symbol.Index = s.session.globals
+		s.session.globals++
 	} else {
 		symbol.Scope = LocalScope
 	}
@@ -90,6 +105,19 @@ func (s *SymbolTable) DefineBuiltin(index int, name string) Symbol {
 	return symbol
 }
 
+// indices : the indices of the symbols of the scope defined in the table, by their names
+func (s *SymbolTable) indices(scope SymbolScope) map[string]int {
+	indices := map[string]int{}
+
+	for name, symbol := range s.store {
+		if scope == symbol.Scope {
+			indices[name] = symbol.Index
+		}
+	}
+
+	return indices
+}
+
 // Resolve :
 func (s *SymbolTable) Resolve(name string) (Symbol, bool) {
 	obj, ok := s.store[name]
@@ -121,7 +149,38 @@ func InitializeSymbolTable() *SymbolTable {
 	return &SymbolTable{
 		store:              store,
 		FreeVariableSymbol: freeVariable,
+		exports:            map[string]bool{},
+		session: &session{
+			modules: map[string]*SymbolTable{},
+		},
+	}
+}
+
+// namespace : the global namespace of the module at path, imported from s, which only sees the builtins
+func (s *SymbolTable) namespace(path string) *SymbolTable {
+	namespace := InitializeSymbolTable()
+	namespace.Path = path
+	namespace.importer = s
+	namespace.session = s.session
+
+	for name, symbol := range s.store {
+		if BuiltinScope == symbol.Scope {
+			namespace.store[name] = symbol
+		}
+	}
+
+	return namespace
+}
+
+// importers : the paths of the modules being loaded, from the one s belongs to back to the main program
+func (s *SymbolTable) importers() []string {
+	paths := []string{}
+
+	for namespace := s; nil != namespace && "" != namespace.Path; namespace = namespace.importer {
+		paths = append(paths, namespace.Path)
 	}
+
+	return paths
 }
 
 // InitializeEnclosedSymbolTable :
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"../rpackage"
+)
+
+var output = flag.String("output", "", "writes the declarations to the given file instead of the standard output")
+
+func main() {
+	flag.Parse()
+
+	if 1 != flag.NArg() {
+		fmt.Fprintln(os.Stderr, "usage: declare [-output file.d.tr] <directory of the R package sources>")
+		os.Exit(2)
+	}
+
+	pkg, err := rpackage.Read(flag.Arg(0))
+
+	if nil != err {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if "" == *output {
+		fmt.Print(pkg.Declarations())
+
+		return
+	}
+
+	if err := ioutil.WriteFile(*output, []byte(pkg.Declarations()), 0644); nil != err {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,872 @@
+package emitter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"../ast"
+	"../module"
+)
+
+// INDENTATION :
+const INDENTATION = "  "
+
+// syntactic : the names R takes without backquotes
+var syntactic = regexp.MustCompile(`^((\pL|\.[._\pL])[._\pL\pN]*|\.|\.\.\.|\.\.[0-9]+)$`)
+
+// reserved : the words R keeps for itself, which can only be names when backquoted
+var reserved = map[string]bool{
+	"if": true, "else": true, "repeat": true, "while": true, "function": true, "for": true, "next": true,
+	"break": true, "TRUE": true, "FALSE": true, "NULL": true, "Inf": true, "NaN": true, "NA": true, "in": true,
+}
+
+// translated : the builtins whose R counterpart has another name or is written some other way, given the arguments
+// as R source; a call that can not be translated, as one with named arguments, is written as it is
+var translated = map[string]func(arguments []string) (string, bool){
+	"puts":    rename("print", 1),
+	"len":     rename("length", 1),
+	"push":    rename("append", 2),
+	"keys":    rename("names", 1),
+	"values":  rename("unname", 1),
+	"head":    template("%s[[1L]]", 1),
+	"tail":    template("%s[-1L]", 1),
+	"last":    template("%[1]s[[length(%[1]s)]]", 1),
+	"has_key": template("(%[2]s %%in%% names(%[1]s))", 2),
+	"assoc":   template("replace(%s, %s, list(%s))", 3),
+	"hash":    hash,
+	// Option and Result are lists tagged with the alternative they are
+	"Some":      template(`list(tag = "Some", value = %s)`, 1),
+	"None":      template(`list(tag = "None")`, 0),
+	"Ok":        template(`list(tag = "Ok", value = %s)`, 1),
+	"Err":       template(`list(tag = "Err", error = %s)`, 1),
+	"map":       template(`(function(o, f) if (o$tag %%in%% c("Some", "Ok")) list(tag = o$tag, value = f(o$value)) else o)(%s, %s)`, 2),
+	"and_then":  template(`(function(o, f) if (o$tag %%in%% c("Some", "Ok")) f(o$value) else o)(%s, %s)`, 2),
+	"unwrap_or": template(`(function(o, default) if (o$tag %%in%% c("Some", "Ok")) o$value else default)(%s, %s)`, 2),
+	// positions are counted from zero, but from one in R
+	"substr":     template("substr(%s, %s + 1L, %s + 1L)", 3),
+	"Position":   position,
+	"grep":       template("(grep(%s, %s) - 1L)", 2),
+	"regexpr":    template(`(function(m) data.frame(start = ifelse(m > 0L, as.vector(m) - 1L, -1L), length = attr(m, "match.length")))(regexpr(%s, %s))`, 2),
+	"regmatches": template(`(function(x, m) substring(x, m$start + 1L, m$start + m$length)[m$start >= 0L])(%s, %s)`, 2),
+	// data frames are made, and worked on, with base R only, the columns being named after the strings given
+	"data.frame": dataFrame,
+	"filter":     template("%s[%s, , drop = FALSE]", 2),
+	"select":     selectColumns,
+	"mutate":     mutate,
+	"arrange":    arrange,
+	"summarise":  summarise,
+}
+
+// zeroBased : the builtins counting positions from zero, which can not be written as they are, along with the calls
+// of them that are translated
+var zeroBased = map[string]string{
+	"substr":     "`substr(x, start, stop)`",
+	"grep":       "`grep(pattern, x)`",
+	"regexpr":    "`regexpr(pattern, text)`",
+	"regmatches": "`regmatches(x, m)`",
+	"Position":   "`Position(f, x)` and `Position(f, x, right)`",
+}
+
+// framed : the builtins working on data frames, which base R has not, or has for something else, as `stats::filter`,
+// along with the calls of them that are translated
+var framed = map[string]string{
+	"data.frame": "`data.frame(\"x\", x, ...)`",
+	"filter":     "`filter(frame, mask)`",
+	"select":     "`select(frame, \"x\", ...)`",
+	"mutate":     "`mutate(frame, \"x\", x, ...)`",
+	"arrange":    "`arrange(frame, \"x\", ...)` and `arrange(frame, \"x\", ..., decreasing)`",
+	"summarise":  "`summarise(frame, \"x\", x, ...)`",
+}
+
+// rename :
+func rename(name string, count int) func([]string) (string, bool) {
+	return func(arguments []string) (string, bool) {
+		return name + "(" + strings.Join(arguments, ", ") + ")", count == len(arguments)
+	}
+}
+
+// template :
+func template(format string, count int) func([]string) (string, bool) {
+	return func(arguments []string) (string, bool) {
+		if count != len(arguments) {
+			return "", false
+		}
+
+		values := make([]interface{}, count)
+
+		for index, argument := range arguments {
+			values[index] = argument
+		}
+
+		return fmt.Sprintf(format, values...), true
+	}
+}
+
+// hash : `hash(k1, v1, k2, v2)` is a list named after the keys
+func hash(arguments []string) (string, bool) {
+	if 0 != len(arguments)%2 {
+		return "", false
+	}
+
+	keys, values := []string{}, []string{}
+
+	for index := 0; index < len(arguments); index += 2 {
+		keys = append(keys, arguments[index])
+		values = append(values, arguments[index+1])
+	}
+
+	return fmt.Sprintf("setNames(list(%s), c(%s))", strings.Join(values, ", "), strings.Join(keys, ", ")), true
+}
+
+// position : `Position(f, x)` is NA when nothing is found, just as in R
+func position(arguments []string) (string, bool) {
+	if 2 != len(arguments) && 3 != len(arguments) {
+		return "", false
+	}
+
+	return "(Position(" + strings.Join(arguments, ", ") + ") - 1L)", true
+}
+
+// column : the name the string literal given as R source stands for, as it is written for a column
+func column(argument string) (string, bool) {
+	name, err := strconv.Unquote(argument)
+
+	if nil != err || !strings.HasPrefix(argument, `"`) {
+		return "", false
+	}
+
+	return Name(name), true
+}
+
+// columns : `"x", x, "y", y` as `x = x, y = y`, the names being string literals; whether any of them has to be
+// backquoted tells R should not check the names
+func columns(arguments []string) ([]string, bool, bool) {
+	if 0 != len(arguments)%2 {
+		return nil, false, false
+	}
+
+	emitted, quoted := []string{}, false
+
+	for index := 0; index < len(arguments); index += 2 {
+		name, ok := column(arguments[index])
+
+		if !ok {
+			return nil, false, false
+		}
+
+		quoted = quoted || strings.HasPrefix(name, "`")
+		emitted = append(emitted, name+" = "+arguments[index+1])
+	}
+
+	return emitted, quoted, true
+}
+
+// dataFrame : `data.frame("x", [1, 2])` is `data.frame(x = c(1L, 2L))`
+func dataFrame(arguments []string) (string, bool) {
+	emitted, quoted, ok := columns(arguments)
+
+	if quoted {
+		emitted = append(emitted, "check.names = FALSE")
+	}
+
+	return "data.frame(" + strings.Join(emitted, ", ") + ")", ok
+}
+
+// summarise : the frame is left out, the summaries being computed from it already
+func summarise(arguments []string) (string, bool) {
+	if 0 == len(arguments) {
+		return "", false
+	}
+
+	return dataFrame(arguments[1:])
+}
+
+// mutate : `transform` adds or replaces the columns, the names having to be syntactic
+func mutate(arguments []string) (string, bool) {
+	if 0 == len(arguments) {
+		return "", false
+	}
+
+	emitted, quoted, ok := columns(arguments[1:])
+
+	return "transform(" + strings.Join(append(arguments[:1:1], emitted...), ", ") + ")", ok && !quoted
+}
+
+// selectColumns : `select(frame, "x", "y")` is `frame[, c("x", "y"), drop = FALSE]`
+func selectColumns(arguments []string) (string, bool) {
+	if 0 == len(arguments) {
+		return "", false
+	}
+
+	for _, argument := range arguments[1:] {
+		if _, ok := column(argument); !ok {
+			return "", false
+		}
+	}
+
+	return arguments[0] + "[, c(" + strings.Join(arguments[1:], ", ") + "), drop = FALSE]", true
+}
+
+// arrange : the rows in the order of the columns, a trailing TRUE sorting them decreasingly; missing values are
+// placed last either way, as `order` does
+func arrange(arguments []string) (string, bool) {
+	if 0 == len(arguments) {
+		return "", false
+	}
+
+	keys, decreasing := arguments[1:], ""
+
+	if last := len(keys) - 1; 0 <= last && ("TRUE" == keys[last] || "FALSE" == keys[last]) {
+		keys, decreasing = keys[:last], ", decreasing = "+keys[last]
+	}
+
+	if 0 == len(keys) {
+		return arguments[0], true
+	}
+
+	orders := []string{}
+
+	for _, key := range keys {
+		if _, ok := column(key); !ok {
+			return "", false
+		}
+
+		orders = append(orders, "f[["+key+"]]")
+	}
+
+	return "(function(f) f[order(" + strings.Join(orders, ", ") + decreasing + "), , drop = FALSE])(" + arguments[0] + ")", true
+}
+
+// Emitter : writes a program as R source; the functions declared to be written in R are called just as they are
+// written, while the builtins are translated to what R has, unless a binding of the program hides them
+type Emitter struct {
+	declared map[string]bool
+	scopes   []map[string]string
+}
+
+// Emit : the R source of the program
+func Emit(program *ast.Program) (string, error) {
+	e := &Emitter{
+		declared: map[string]bool{},
+		scopes:   []map[string]string{{}},
+	}
+
+	lines, err := e.statements(program.Statements, 0)
+
+	if nil != err {
+		return "", err
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// Name : as R takes the name, backquoted when it is not syntactic
+func Name(name string) string {
+	if syntactic.MatchString(name) && !reserved[name] {
+		return name
+	}
+
+	return "`" + strings.Replace(name, "`", "\\`", -1) + "`"
+}
+
+// bind : the name hides any builtin, and any declaration, named alike in the current scope
+func (e *Emitter) bind(name string) {
+	e.scopes[len(e.scopes)-1][name] = ""
+
+	if 1 == len(e.scopes) {
+		delete(e.declared, name)
+	}
+}
+
+// bound :
+func (e *Emitter) bound(name string) bool {
+	_, ok := e.binding(name)
+
+	return ok
+}
+
+// binding : the kind of the value the name is bound to, as far as it is told, in the innermost scope binding it
+func (e *Emitter) binding(name string) (string, bool) {
+	for index := len(e.scopes) - 1; 0 <= index; index-- {
+		if kind, ok := e.scopes[index][name]; ok {
+			return kind, true
+		}
+	}
+
+	return "", false
+}
+
+// statements : the lines of R source the statements are written as, indented by depth
+func (e *Emitter) statements(statements []ast.Statement, depth int) ([]string, error) {
+	lines := []string{}
+
+	for _, statement := range statements {
+		line, err := e.statement(statement, depth)
+
+		if nil != err {
+			return nil, err
+		}
+
+		if "" != line {
+			lines = append(lines, strings.Repeat(INDENTATION, depth)+line)
+		}
+	}
+
+	return lines, nil
+}
+
+// statement : declarations and imports of declaration files have nothing to be written as, the functions they tell
+// about being already there in R
+func (e *Emitter) statement(statement ast.Statement, depth int) (string, error) {
+	switch statement := statement.(type) {
+	case *ast.LetStatement:
+		return e.assignment(statement.Name.Value, statement.Value, depth)
+	case *ast.ConstStatement:
+		return e.assignment(statement.Name.Value, statement.Value, depth)
+	case *ast.DeclareStatement:
+		e.declared[statement.Name.Value] = true
+
+		return "", nil
+	case *ast.ImportStatement:
+		for _, name := range statement.Names {
+			if module.IsDeclarationFile(statement.Path) {
+				e.declared[name.Value] = true
+			} else {
+				e.bind(name.Value)
+			}
+		}
+
+		if module.IsDeclarationFile(statement.Path) {
+			return "", nil
+		}
+
+		path := statement.Path
+
+		if !module.IsRFile(path) {
+			path = strings.TrimSuffix(path, ".tr") + ".R"
+		}
+
+		return fmt.Sprintf("source(%s)", strconv.Quote(path)), nil
+	case *ast.TypeStatement:
+		return e.constructors(statement, depth), nil
+	case *ast.ReturnStatement:
+		value, err := e.bare(statement.ReturnValue, depth)
+
+		return "return(" + value + ")", err
+	case *ast.ExpressionStatement:
+		return e.bare(statement.Expression, depth)
+	}
+
+	return "", fmt.Errorf("%T can not be emitted to R", statement)
+}
+
+// assignment : the names bound to hashes are kept, so their elements are got by key
+func (e *Emitter) assignment(name string, value ast.Expression, depth int) (string, error) {
+	kind := e.kind(value)
+	e.bind(name)
+
+	if "hash" == kind {
+		e.scopes[len(e.scopes)-1][name] = kind
+	}
+
+	emitted, err := e.bare(value, depth)
+
+	return Name(name) + " <- " + emitted, err
+}
+
+// bare : an operation standing on its own, with nothing around it to tell apart from, needs no parentheses
+func (e *Emitter) bare(expression ast.Expression, depth int) (string, error) {
+	emitted, err := e.expression(expression, depth)
+
+	if _, ok := expression.(*ast.InfixExpression); ok && nil == err {
+		emitted = emitted[1 : len(emitted)-1]
+	}
+
+	return emitted, err
+}
+
+// expressions :
+func (e *Emitter) expressions(expressions []ast.Expression, depth int) ([]string, error) {
+	emitted := make([]string, len(expressions))
+
+	for index, expression := range expressions {
+		var err error
+
+		if emitted[index], err = e.expression(expression, depth); nil != err {
+			return nil, err
+		}
+	}
+
+	return emitted, nil
+}
+
+// expression : operations are enclosed in parentheses, so they are read by R just as they were parsed
+func (e *Emitter) expression(expression ast.Expression, depth int) (string, error) {
+	switch expression := expression.(type) {
+	case *ast.Identifier:
+		return Name(expression.Value), nil
+	case *ast.IntegerLiteral:
+		return fmt.Sprintf("%dL", expression.Value), nil
+	case *ast.DoubleLiteral:
+		return strconv.FormatFloat(expression.Value, 'g', -1, 64), nil
+	case *ast.StringLiteral:
+		return strconv.Quote(expression.Value), nil
+	case *ast.Boolean:
+		return strings.ToUpper(strconv.FormatBool(expression.Value)), nil
+	case *ast.NotAvailable:
+		return "NA", nil
+	case *ast.Null:
+		return "NULL", nil
+	case *ast.PrefixExpression:
+		right, err := e.expression(expression.Right, depth)
+
+		return expression.Operator + right, err
+	case *ast.InfixExpression:
+		operands, err := e.expressions([]ast.Expression{expression.Left, expression.Right}, depth)
+
+		if nil != err {
+			return "", err
+		}
+
+		if "/" == expression.Operator {
+			return e.division(expression, operands), nil
+		}
+
+		if "+" == expression.Operator {
+			return e.addition(expression, operands), nil
+		}
+
+		return "(" + operands[0] + " " + expression.Operator + " " + operands[1] + ")", nil
+	case *ast.ConditionalExpression:
+		return e.conditional(expression, depth)
+	case *ast.MatchExpression:
+		return e.match(expression, depth)
+	case *ast.FunctionLiteral:
+		return e.function(expression, depth)
+	case *ast.CallExpression:
+		return e.call(expression, depth)
+	case *ast.NamedArgument:
+		value, err := e.expression(expression.Value, depth)
+
+		return Name(expression.Name) + " = " + value, err
+	case *ast.ArrayLiteral:
+		elements, err := e.expressions(expression.Elements, depth)
+
+		return "c(" + strings.Join(elements, ", ") + ")", err
+	case *ast.IndexExpression:
+		return e.index(expression, depth)
+	}
+
+	return "", fmt.Errorf("%T can not be emitted to R", expression)
+}
+
+// block : the statements between braces, each on a line of its own
+func (e *Emitter) block(block *ast.BlockStatement, depth int) (string, error) {
+	if nil == block || 0 == len(block.Statements) {
+		return "{}", nil
+	}
+
+	lines, err := e.statements(block.Statements, depth+1)
+
+	if nil != err {
+		return "", err
+	}
+
+	return "{\n" + strings.Join(lines, "\n") + "\n" + strings.Repeat(INDENTATION, depth) + "}", nil
+}
+
+// conditional :
+func (e *Emitter) conditional(conditional *ast.ConditionalExpression, depth int) (string, error) {
+	condition, err := e.bare(conditional.Condition, depth)
+
+	if nil != err {
+		return "", err
+	}
+
+	consequence, err := e.block(conditional.Consequence, depth)
+
+	if nil != err || nil == conditional.Alternative {
+		return "if (" + condition + ") " + consequence, err
+	}
+
+	alternative, err := e.block(conditional.Alternative, depth)
+
+	return "if (" + condition + ") " + consequence + " else " + alternative, err
+}
+
+// constructors : the alternatives of a type are lists tagged with the alternative they are, just as Option and
+// Result, the fields following the tag
+func (e *Emitter) constructors(statement *ast.TypeStatement, depth int) string {
+	lines := []string{}
+
+	for _, constructor := range statement.Constructors {
+		e.bind(constructor.Name.Value)
+
+		parameters, fields := []string{}, []string{"tag = " + strconv.Quote(constructor.Name.Value)}
+
+		for _, field := range constructor.Fields {
+			parameters = append(parameters, Name(field.Value))
+			fields = append(fields, Name(field.Value)+" = "+Name(field.Value))
+		}
+
+		lines = append(lines, fmt.Sprintf("%s <- function(%s) list(%s)", Name(constructor.Name.Value), strings.Join(parameters, ", "), strings.Join(fields, ", ")))
+	}
+
+	return strings.Join(lines, "\n"+strings.Repeat(INDENTATION, depth))
+}
+
+// match : a switch on the tag of the subject, kept in `.match` so it is computed once, each arm binding the fields
+// it names before its body
+func (e *Emitter) match(match *ast.MatchExpression, depth int) (string, error) {
+	subject, err := e.bare(match.Subject, depth+1)
+
+	if nil != err {
+		return "", err
+	}
+
+	inner := strings.Repeat(INDENTATION, depth+1)
+	arms := []string{}
+	exhaustive := false
+
+	for _, arm := range match.Arms {
+		lines := []string{}
+
+		for index, field := range arm.Fields {
+			if "_" != field.Value {
+				e.bind(field.Value)
+				lines = append(lines, fmt.Sprintf("%s <- .match[[%dL]]", Name(field.Value), index+2))
+			}
+		}
+
+		body, err := e.statements(arm.Body.Statements, depth+3)
+
+		if nil != err {
+			return "", err
+		}
+
+		for index := range lines {
+			lines[index] = strings.Repeat(INDENTATION, depth+3) + lines[index]
+		}
+
+		emitted := "{}"
+
+		if lines = append(lines, body...); 0 != len(lines) {
+			emitted = "{\n" + strings.Join(lines, "\n") + "\n" + inner + INDENTATION + "}"
+		}
+
+		if arm.IsWildcard() {
+			arms, exhaustive = append(arms, emitted), true
+
+			break
+		}
+
+		arms = append(arms, Name(arm.Constructor.Value)+" = "+emitted)
+	}
+
+	if !exhaustive {
+		arms = append(arms, `stop("no pattern matches")`)
+	}
+
+	return "{\n" + inner + ".match <- " + subject + "\n" + inner + "switch(.match$tag,\n" + inner + INDENTATION +
+		strings.Join(arms, ",\n"+inner+INDENTATION) + "\n" + inner + ")\n" + strings.Repeat(INDENTATION, depth) + "}", nil
+}
+
+// function : the parameters hide whatever they are named after, within the body only
+func (e *Emitter) function(function *ast.FunctionLiteral, depth int) (string, error) {
+	e.scopes = append(e.scopes, map[string]string{})
+	defer func() { e.scopes = e.scopes[:len(e.scopes)-1] }()
+
+	parameters := []string{}
+
+	for index, parameter := range function.Parameters {
+		e.bind(parameter.Value)
+		emitted := Name(parameter.Value)
+
+		if value := function.Default(index); nil != value {
+			value, err := e.expression(value, depth)
+
+			if nil != err {
+				return "", err
+			}
+
+			emitted += " = " + value
+		}
+
+		parameters = append(parameters, emitted)
+	}
+
+	body, err := e.block(function.Body, depth)
+
+	return "function(" + strings.Join(parameters, ", ") + ") " + body, err
+}
+
+// call : `x %op% y` is written back as an operation; the declared functions, and those the program binds, are called
+// just as they are written
+func (e *Emitter) call(call *ast.CallExpression, depth int) (string, error) {
+	arguments, err := e.expressions(call.Parameters, depth)
+
+	if nil != err {
+		return "", err
+	}
+
+	function, ok := call.Function.(*ast.Identifier)
+
+	if ok && strings.HasPrefix(function.Value, "%") && 2 == len(arguments) {
+		return "(" + arguments[0] + " " + function.Value + " " + arguments[1] + ")", nil
+	}
+
+	if ok && !e.declared[function.Value] && !e.bound(function.Value) {
+		if translate, ok := translated[function.Value]; ok {
+			if _, named := namedArgument(call); !named {
+				if emitted, ok := translate(arguments); ok {
+					return emitted, nil
+				}
+			}
+		}
+	}
+
+	if ok && !e.declared[function.Value] && !e.bound(function.Value) {
+		if calls, ok := zeroBased[function.Value]; ok {
+			return "", fmt.Errorf("`%s` counts from zero, only %s can be emitted to R", function.Value, calls)
+		}
+
+		if calls, ok := framed[function.Value]; ok {
+			return "", fmt.Errorf("`%s` is not base R, only %s can be emitted to R", function.Value, calls)
+		}
+	}
+
+	callee, err := e.expression(call.Function, depth)
+
+	if nil != err {
+		return "", err
+	}
+
+	if _, ok := call.Function.(*ast.FunctionLiteral); ok {
+		callee = "(" + callee + ")"
+	}
+
+	return callee + "(" + strings.Join(arguments, ", ") + ")", nil
+}
+
+// namedArgument : the first argument of the call given by name, if any
+func namedArgument(call *ast.CallExpression) (*ast.NamedArgument, bool) {
+	for _, parameter := range call.Parameters {
+		if named, ok := parameter.(*ast.NamedArgument); ok {
+			return named, true
+		}
+	}
+
+	return nil, false
+}
+
+// index : elements are counted from zero, but from one in R; `x$name`, just like `x["name"]`, gets the element by
+// its name, while a logical mask gets those it picks out
+func (e *Emitter) index(index *ast.IndexExpression, depth int) (string, error) {
+	left, err := e.expression(index.Left, depth)
+
+	if nil != err {
+		return "", err
+	}
+
+	if _, ok := index.Left.(*ast.Identifier); !ok {
+		left = "(" + left + ")"
+	}
+
+	if !index.Matrix && "hash" == e.kind(index.Left) {
+		key, err := e.key(index.Index, depth)
+
+		return left + "[[" + key + "]]", err
+	}
+
+	if !index.Matrix {
+		position, err := e.position(index.Index, depth)
+
+		// a logical mask picks out many elements
+		if "logical" == e.kind(index.Index) {
+			return left + "[" + position + "]", err
+		}
+
+		return left + "[[" + position + "]]", err
+	}
+
+	row, err := e.position(index.Index, depth)
+
+	if nil != err {
+		return "", err
+	}
+
+	column, err := e.position(index.Column, depth)
+
+	return left + "[" + row + ", " + column + "]", err
+}
+
+// position : the position counted from one, nothing standing for every row or column; names and logical masks are
+// left as they are, and so are positions whose type can not be told but in R
+func (e *Emitter) position(position ast.Expression, depth int) (string, error) {
+	if nil == position {
+		return "", nil
+	}
+
+	if literal, ok := position.(*ast.IntegerLiteral); ok {
+		return fmt.Sprintf("%dL", literal.Value+1), nil
+	}
+
+	switch e.kind(position) {
+	case "integer", "double", "numeric":
+		emitted, err := e.expression(position, depth)
+
+		return emitted + " + 1L", err
+	case "character", "logical":
+		return e.bare(position, depth)
+	}
+
+	emitted, err := e.bare(position, depth)
+
+	return "(function(i) if (is.numeric(i)) i + 1L else i)(" + emitted + ")", err
+}
+
+// key : the name of the element of the hash, the keys being the names of the list it is written as
+func (e *Emitter) key(key ast.Expression, depth int) (string, error) {
+	if literal, ok := key.(*ast.IntegerLiteral); ok {
+		return strconv.Quote(strconv.FormatInt(literal.Value, 10)), nil
+	}
+
+	emitted, err := e.bare(key, depth)
+
+	if "character" == e.kind(key) {
+		return emitted, err
+	}
+
+	return "as.character(" + emitted + ")", err
+}
+
+// division : integers are divided leaving out the remainder, while R divides them just as doubles
+func (e *Emitter) division(division *ast.InfixExpression, operands []string) string {
+	left, right := e.kind(division.Left), e.kind(division.Right)
+
+	switch {
+	case "integer" == left && "integer" == right:
+		return "(as.integer(" + operands[0] + " / " + operands[1] + "))"
+	case "double" == left || "double" == right:
+		return "(" + operands[0] + " / " + operands[1] + ")"
+	}
+
+	return "((function(x, y) if (is.integer(x) && is.integer(y)) as.integer(x / y) else x / y)(" + operands[0] +
+		", " + operands[1] + "))"
+}
+
+// addition : strings are pasted together, while R adds numbers only
+func (e *Emitter) addition(addition *ast.InfixExpression, operands []string) string {
+	left, right := e.kind(addition.Left), e.kind(addition.Right)
+
+	switch {
+	case "character" == left || "character" == right:
+		return "(paste0(" + operands[0] + ", " + operands[1] + "))"
+	case "" != left || "" != right:
+		return "(" + operands[0] + " + " + operands[1] + ")"
+	}
+
+	return "((function(x, y) if (is.character(x)) paste0(x, y) else x + y)(" + operands[0] + ", " + operands[1] + "))"
+}
+
+// kind : what R makes of the expression, as far as it can be told from the expression alone; "numeric" stands for
+// either an integer or a double, "hash" for a list named after the keys, and "" for what can not be told
+func (e *Emitter) kind(expression ast.Expression) string {
+	switch expression := expression.(type) {
+	case *ast.IntegerLiteral:
+		return "integer"
+	case *ast.DoubleLiteral:
+		return "double"
+	case *ast.StringLiteral:
+		return "character"
+	case *ast.Boolean:
+		return "logical"
+	case *ast.PrefixExpression:
+		if "!" == expression.Operator {
+			return "logical"
+		}
+
+		if kind := e.kind(expression.Right); isNumber(kind) {
+			return kind
+		}
+
+		return ""
+	case *ast.InfixExpression:
+		left, right := e.kind(expression.Left), e.kind(expression.Right)
+
+		switch expression.Operator {
+		case "<", ">", "<=", ">=", "==", "!=", "&", "|", "&&", "||":
+			return "logical"
+		case "+":
+			// strings are pasted together
+			if "character" == left || "character" == right {
+				return "character"
+			}
+
+			if "" == numeric(left, right) {
+				return ""
+			}
+		case "-", "*", "/":
+		default:
+			return ""
+		}
+
+		if kind := numeric(left, right); "" != kind {
+			return kind
+		}
+
+		return "numeric"
+	case *ast.ArrayLiteral:
+		kind := ""
+
+		for index, element := range expression.Elements {
+			if 0 == index {
+				kind = e.kind(element)
+			} else if e.kind(element) != kind {
+				return ""
+			}
+		}
+
+		return kind
+	case *ast.Identifier:
+		kind, _ := e.binding(expression.Value)
+
+		return kind
+	case *ast.CallExpression:
+		function, ok := expression.Function.(*ast.Identifier)
+
+		if !ok || e.declared[function.Value] || e.bound(function.Value) {
+			return ""
+		}
+
+		switch function.Value {
+		case "len":
+			return "integer"
+		case "hash":
+			return "hash"
+		}
+	}
+
+	return ""
+}
+
+// numeric : the kind of the result of an operation on numbers of the given kinds, "" when either may not be a number
+func numeric(left string, right string) string {
+	switch {
+	case "integer" == left && "integer" == right:
+		return "integer"
+	case isNumber(left) && isNumber(right) && ("double" == left || "double" == right):
+		return "double"
+	case isNumber(left) && isNumber(right):
+		return "numeric"
+	}
+
+	return ""
+}
+
+// isNumber :
+func isNumber(kind string) bool {
+	return "integer" == kind || "double" == kind || "numeric" == kind
+}
@@ -0,0 +1,155 @@
+package emitter
+
+import (
+	"testing"
+
+	"../lexer"
+	"../parser"
+)
+
+// emit :
+func emit(t *testing.T, input string) (string, error) {
+	t.Helper()
+
+	p := parser.InitializeParser(lexer.InitializeLexer(input))
+	program := p.ParseProgram()
+
+	if 0 != len(p.Errors()) {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	return Emit(program)
+}
+
+func TestEmit(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`let x <- [1, 2.5, NA]; y <- "a\"b"; x[0] + 1`,
+			"x <- c(1L, 2.5, NA)\ny <- \"a\\\"b\"\nx[[1L]] + 1L\n",
+		},
+		{
+			`let f <- function(x, n = 2) { if (x > n) { return x * (n - 1) } else { -x } }`,
+			"f <- function(x, n = 2L) {\n  if (x > n) {\n    return(x * (n - 1L))\n  } else {\n    -x\n  }\n}\n",
+		},
+		{
+			`let m <- matrix([1, 2, 3, 4], 2); m[i, ]; m %*% t(m); people$age`,
+			"m <- matrix(c(1L, 2L, 3L, 4L), 2L)\nm[(function(i) if (is.numeric(i)) i + 1L else i)(i), ]\nm %*% t(m)\npeople[[\"age\"]]\n",
+		},
+		{
+			`puts(len(push(xs, 1))); head(xs); has_key(hash("a", 1), "a"); len(xs, 2)`,
+			"print(length(append(xs, 1L)))\nxs[[1L]]\n(\"a\" %in% names(setNames(list(1L), c(\"a\"))))\nlen(xs, 2L)\n",
+		},
+		{
+			`let len <- function(x) { 0 }; len(xs); let g <- function(puts) { puts(1) }; puts(2)`,
+			"len <- function(x) {\n  0L\n}\nlen(xs)\ng <- function(puts) {\n  puts(1L)\n}\nprint(2L)\n",
+		},
+		{
+			`declare function head(x: any, n: integer = 6): any; head(xs, n = 3); head(xs)`,
+			"head(xs, n = 3L)\nhead(xs)\n",
+		},
+		{
+			`import { head } from "./utils.d.tr"; import { double } from "./utils.tr"; head(double(xs))`,
+			"source(\"./utils.R\")\nhead(double(xs))\n",
+		},
+		{
+			`import { square } from "./legacy.R"; square(2)`,
+			"source(\"./legacy.R\")\nsquare(2L)\n",
+		},
+		{
+			"let `my var` <- (x, ...) { list(...) }; `my var`(1, b = 2)",
+			"`my var` <- function(x, ...) {\n  list(...)\n}\n`my var`(1L, b = 2L)\n",
+		},
+		{
+			`let half <- function(x) { if (x > 0) { Ok(x / 2) } else { Err("negative") } }; unwrap_or(map(half(4), f), None())`,
+			"half <- function(x) {\n  if (x > 0L) {\n    list(tag = \"Ok\", value = ((function(x, y) if (is.integer(x) && is.integer(y)) as.integer(x / y) else x / y)(x, 2L)))\n  } else {\n    list(tag = \"Err\", error = \"negative\")\n  }\n}\n" +
+				"(function(o, default) if (o$tag %in% c(\"Some\", \"Ok\")) o$value else default)((function(o, f) if (o$tag %in% c(\"Some\", \"Ok\")) list(tag = o$tag, value = f(o$value)) else o)(half(4L), f), list(tag = \"None\"))\n",
+		},
+		{
+			`type Shape = Circle(r: double) | Empty; match (s) { Circle(r) -> r * r, Empty -> 0 }`,
+			"Circle <- function(r) list(tag = \"Circle\", r = r)\nEmpty <- function() list(tag = \"Empty\")\n" +
+				"{\n  .match <- s\n  switch(.match$tag,\n    Circle = {\n      r <- .match[[2L]]\n      r * r\n    },\n    Empty = {\n      0L\n    },\n    stop(\"no pattern matches\")\n  )\n}\n",
+		},
+		{
+			`type Numbers = double[]; let first <- function<T>(xs: T[]): T { xs[0] }; first([1, 2])`,
+			"first <- function(xs) {\n  xs[[1L]]\n}\nfirst(c(1L, 2L))\n",
+		},
+		{
+			`h[k]; h["a"]; xs[len(xs) - 1]; xs[xs > 1]; frame[frame$age > 30, "age"]; m[[0, 1], 2 * j]`,
+			"h[[(function(i) if (is.numeric(i)) i + 1L else i)(k)]]\nh[[\"a\"]]\nxs[[(length(xs) - 1L) + 1L]]\nxs[xs > 1L]\n" +
+				"frame[frame[[\"age\"]] > 30L, \"age\"]\nm[c(0L, 1L) + 1L, (2L * j) + 1L]\n",
+		},
+		{
+			`3 / 2; 3.0 / 2; n / 2; (1 + 2) / 2`,
+			"as.integer(3L / 2L)\n3 / 2L\n(function(x, y) if (is.integer(x) && is.integer(y)) as.integer(x / y) else x / y)(n, 2L)\n" +
+				"as.integer((1L + 2L) / 2L)\n",
+		},
+		{
+			`substr("hello", 1, 3); grep("a", xs); regmatches(xs, regexpr("[0-9]+", xs))`,
+			"substr(\"hello\", 1L + 1L, 3L + 1L)\n(grep(\"a\", xs) - 1L)\n" +
+				"(function(x, m) substring(x, m$start + 1L, m$start + m$length)[m$start >= 0L])(xs, " +
+				"(function(m) data.frame(start = ifelse(m > 0L, as.vector(m) - 1L, -1L), length = attr(m, \"match.length\")))(regexpr(\"[0-9]+\", xs)))\n",
+		},
+		{
+			`data.frame("x", [1, 2]); data.frame("my x", 1, "y", "a")`,
+			"data.frame(x = c(1L, 2L))\ndata.frame(`my x` = 1L, y = \"a\", check.names = FALSE)\n",
+		},
+		{
+			`filter(people, people$age > 30); select(people, "name", "age"); mutate(people, "old", people$age > 60); arrange(people, "age", TRUE); summarise(people, "rows", nrow(people))`,
+			"people[(people[[\"age\"]] > 30L), , drop = FALSE]\npeople[, c(\"name\", \"age\"), drop = FALSE]\ntransform(people, old = (people[[\"age\"]] > 60L))\n" +
+				"(function(f) f[order(f[[\"age\"]], decreasing = TRUE), , drop = FALSE])(people)\ndata.frame(rows = nrow(people))\n",
+		},
+		{
+			`Position(function(x) { x > 1 }, xs)`,
+			"(Position(function(x) {\n  x > 1L\n}, xs) - 1L)\n",
+		},
+		{
+			`"a" + b; a + 1; a + b`,
+			"paste0(\"a\", b)\na + 1L\n(function(x, y) if (is.character(x)) paste0(x, y) else x + y)(a, b)\n",
+		},
+		{
+			`let h <- hash(1, "a", "b", 2); h[1]; h["b"]; h[k]`,
+			"h <- setNames(list(\"a\", 2L), c(1L, \"b\"))\nh[[\"1\"]]\nh[[\"b\"]]\nh[[as.character(k)]]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		emitted, err := emit(t, tt.input)
+
+		if nil != err {
+			t.Errorf("emitter error for %q: %s", tt.input, err)
+
+			continue
+		}
+
+		if emitted != tt.expected {
+			t.Errorf("wrong R source for %q, expected=%q, got=%q", tt.input, tt.expected, emitted)
+		}
+	}
+}
+
+func TestEmitErrors(t *testing.T) {
+	if _, err := emit(t, "second . first"); nil == err || "*ast.PointFreeExpression can not be emitted to R" != err.Error() {
+		t.Fatalf("wrong error, got=%v", err)
+	}
+
+	expected := "`grep` counts from zero, only `grep(pattern, x)` can be emitted to R"
+
+	if _, err := emit(t, `grep("a", xs, ignore.case = TRUE)`); nil == err || expected != err.Error() {
+		t.Fatalf("wrong error, got=%v", err)
+	}
+
+	expected = "`select` is not base R, only `select(frame, \"x\", ...)` can be emitted to R"
+
+	if _, err := emit(t, `select(people, columns)`); nil == err || expected != err.Error() {
+		t.Fatalf("wrong error, got=%v", err)
+	}
+
+	expected = "`Position` counts from zero, only `Position(f, x)` and `Position(f, x, right)` can be emitted to R"
+
+	if _, err := emit(t, `Position(f, xs, right = TRUE)`); nil == err || expected != err.Error() {
+		t.Fatalf("wrong error, got=%v", err)
+	}
+}
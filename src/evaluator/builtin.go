@@ -10,10 +10,98 @@ func wrongArgumentsError(size int) object.Object {
 }
 
 var builtins = map[string]*object.Builtin{
-	"puts": object.GetBuiltinByName("puts"),
-	"len":  object.GetBuiltinByName("len"),
-	"head": object.GetBuiltinByName("head"),
-	"tail": object.GetBuiltinByName("tail"),
-	"last": object.GetBuiltinByName("last"),
-	"push": object.GetBuiltinByName("push"),
+	"puts":    object.GetBuiltinByName("puts"),
+	"len":     object.GetBuiltinByName("len"),
+	"head":    object.GetBuiltinByName("head"),
+	"tail":    object.GetBuiltinByName("tail"),
+	"last":    object.GetBuiltinByName("last"),
+	"push":    object.GetBuiltinByName("push"),
+	"hash":    object.GetBuiltinByName("hash"),
+	"keys":    object.GetBuiltinByName("keys"),
+	"values":  object.GetBuiltinByName("values"),
+	"has_key": object.GetBuiltinByName("has_key"),
+	"assoc":   object.GetBuiltinByName("assoc"),
+
+	"data.frame":       object.GetBuiltinByName("data.frame"),
+	"nrow":             object.GetBuiltinByName("nrow"),
+	"ncol":             object.GetBuiltinByName("ncol"),
+	"names":            object.GetBuiltinByName("names"),
+	"filter":           object.GetBuiltinByName("filter"),
+	"select":           object.GetBuiltinByName("select"),
+	"mutate":           object.GetBuiltinByName("mutate"),
+	"arrange":          object.GetBuiltinByName("arrange"),
+	"summarise":        object.GetBuiltinByName("summarise"),
+	"read.csv":         object.GetBuiltinByName("read.csv"),
+	"write.csv":        object.GetBuiltinByName("write.csv"),
+	"fromJSON":         object.GetBuiltinByName("fromJSON"),
+	"toJSON":           object.GetBuiltinByName("toJSON"),
+	"lapply":           object.GetBuiltinByName("lapply"),
+	"sapply":           object.GetBuiltinByName("sapply"),
+	"vapply":           object.GetBuiltinByName("vapply"),
+	"Map":              object.GetBuiltinByName("Map"),
+	"Filter":           object.GetBuiltinByName("Filter"),
+	"Reduce":           object.GetBuiltinByName("Reduce"),
+	"Position":         object.GetBuiltinByName("Position"),
+	"Find":             object.GetBuiltinByName("Find"),
+	"paste":            object.GetBuiltinByName("paste"),
+	"paste0":           object.GetBuiltinByName("paste0"),
+	"sprintf":          object.GetBuiltinByName("sprintf"),
+	"format":           object.GetBuiltinByName("format"),
+	"nchar":            object.GetBuiltinByName("nchar"),
+	"substr":           object.GetBuiltinByName("substr"),
+	"strsplit":         object.GetBuiltinByName("strsplit"),
+	"toupper":          object.GetBuiltinByName("toupper"),
+	"tolower":          object.GetBuiltinByName("tolower"),
+	"trimws":           object.GetBuiltinByName("trimws"),
+	"startsWith":       object.GetBuiltinByName("startsWith"),
+	"endsWith":         object.GetBuiltinByName("endsWith"),
+	"grepl":            object.GetBuiltinByName("grepl"),
+	"grep":             object.GetBuiltinByName("grep"),
+	"sub":              object.GetBuiltinByName("sub"),
+	"gsub":             object.GetBuiltinByName("gsub"),
+	"regexpr":          object.GetBuiltinByName("regexpr"),
+	"regmatches":       object.GetBuiltinByName("regmatches"),
+	"abs":              object.GetBuiltinByName("abs"),
+	"sqrt":             object.GetBuiltinByName("sqrt"),
+	"exp":              object.GetBuiltinByName("exp"),
+	"log":              object.GetBuiltinByName("log"),
+	"round":            object.GetBuiltinByName("round"),
+	"floor":            object.GetBuiltinByName("floor"),
+	"ceiling":          object.GetBuiltinByName("ceiling"),
+	"min":              object.GetBuiltinByName("min"),
+	"max":              object.GetBuiltinByName("max"),
+	"sum":              object.GetBuiltinByName("sum"),
+	"prod":             object.GetBuiltinByName("prod"),
+	"mean":             object.GetBuiltinByName("mean"),
+	"median":           object.GetBuiltinByName("median"),
+	"var":              object.GetBuiltinByName("var"),
+	"sd":               object.GetBuiltinByName("sd"),
+	"quantile":         object.GetBuiltinByName("quantile"),
+	"cumsum":           object.GetBuiltinByName("cumsum"),
+	"cor":              object.GetBuiltinByName("cor"),
+	"range":            object.GetBuiltinByName("range"),
+	"set.seed":         object.GetBuiltinByName("set.seed"),
+	"runif":            object.GetBuiltinByName("runif"),
+	"rnorm":            object.GetBuiltinByName("rnorm"),
+	"sample":           object.GetBuiltinByName("sample"),
+	"rbinom":           object.GetBuiltinByName("rbinom"),
+	"matrix":           object.GetBuiltinByName("matrix"),
+	"dim":              object.GetBuiltinByName("dim"),
+	"t":                object.GetBuiltinByName("t"),
+	"diag":             object.GetBuiltinByName("diag"),
+	"solve":            object.GetBuiltinByName("solve"),
+	"apply":            object.GetBuiltinByName("apply"),
+	"list":             object.GetBuiltinByName("list"),
+	"do.call":          object.GetBuiltinByName("do.call"),
+	"stop":             object.GetBuiltinByName("stop"),
+	"warning":          object.GetBuiltinByName("warning"),
+	"message":          object.GetBuiltinByName("message"),
+	"conditionMessage": object.GetBuiltinByName("conditionMessage"),
+	"Some":             object.GetBuiltinByName("Some"),
+	"None":             object.GetBuiltinByName("None"),
+	"Ok":               object.GetBuiltinByName("Ok"),
+	"Err":              object.GetBuiltinByName("Err"),
+	"map":              object.GetBuiltinByName("map"),
+	"and_then":         object.GetBuiltinByName("and_then"),
+	"unwrap_or":        object.GetBuiltinByName("unwrap_or"),
 }
@@ -0,0 +1,61 @@
+package evaluator
+
+import (
+	"../object"
+)
+
+// callContext : what the builtins called by the evaluator receive
+type callContext struct {
+	environment *object.Environment
+	position    object.Position
+}
+
+// Call : functions called back by a builtin are reported at the position of the builtin
+func (c *callContext) Call(function object.Object, parameters ...object.Object) (object.Object, *object.Error) {
+	result := applyFunction(function, nil, parameters, c.environment, c.position)
+
+	if err, ok := result.(*object.Error); ok {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CallNamed :
+func (c *callContext) CallNamed(function object.Object, names []string, parameters []object.Object) (object.Object, *object.Error) {
+	result := applyFunction(function, names, parameters, c.environment, c.position)
+
+	if err, ok := result.(*object.Error); ok {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Position :
+func (c *callContext) Position() object.Position {
+	return c.position
+}
+
+// Function :
+func (c *callContext) Function(name string) object.Object {
+	if value, ok := c.environment.Get(name); ok {
+		return value.Value
+	}
+
+	if builtin, ok := builtins[name]; ok {
+		return builtin
+	}
+
+	return nil
+}
+
+// Random :
+func (c *callContext) Random() *object.Random {
+	return c.environment.Random()
+}
+
+// Signal :
+func (c *callContext) Signal(condition *object.Condition) *object.Error {
+	return signal(condition, c.environment, c.position)
+}
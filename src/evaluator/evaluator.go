@@ -2,9 +2,11 @@ package evaluator
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"../ast"
+	"../module"
 	"../object"
 	"../token"
 )
@@ -51,7 +53,10 @@ func evalProgram(statements []ast.Statement, environment *object.Environment) ob
 	var result object.Object
 
 	for _, statement := range statements {
-		result = Eval(statement, environment)
+		statement := statement
+		result = guarded(func() object.Object {
+			return Eval(statement, environment)
+		})
 
 		switch result := result.(type) {
 		case *object.ReturnValue:
@@ -64,6 +69,18 @@ func evalProgram(statements []ast.Statement, environment *object.Environment) ob
 	return result
 }
 
+// guarded : a fault of the evaluator itself, such as an index out of range, is an error rather than the end of the
+// program, so tryCatch can take it just as any other
+func guarded(evaluate func() object.Object) (result object.Object) {
+	defer func() {
+		if fault := recover(); nil != fault {
+			result = newError("%v", fault)
+		}
+	}()
+
+	return evaluate()
+}
+
 // nativeBoolToBooleanObject :
 func nativeBoolToBooleanObject(input bool) object.Object {
 	if input {
@@ -75,7 +92,13 @@ func nativeBoolToBooleanObject(input bool) object.Object {
 
 // evalBangOperatorExpression :
 func evalBangOperatorExpression(right object.Object) object.Object {
+	if array, ok := right.(*object.Array); ok {
+		return evalVectorizedPrefixExpression(array, evalBangOperatorExpression)
+	}
+
 	switch right {
+	case object.NA:
+		return object.NA
 	case TRUE:
 		return FALSE
 	case FALSE:
@@ -89,14 +112,40 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 
 // evalMinusPrefixOperatorExpression :
 func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
-	if right.Type() != object.INTEGER_OBJECT {
+	switch right := right.(type) {
+	case *object.Integer:
+		return &object.Integer{
+			Value: -right.Value,
+		}
+	case *object.Double:
+		return &object.Double{
+			Value: -right.Value,
+		}
+	case *object.Array:
+		return evalVectorizedPrefixExpression(right, evalMinusPrefixOperatorExpression)
+	case *object.Matrix:
+		return right.Negate()
+	case *object.NotAvailable:
+		return object.NA
+	default:
 		return newError("unknown operator: -%s", right.Type())
 	}
+}
 
-	value := right.(*object.Integer).Value
+// evalVectorizedPrefixExpression :
+func evalVectorizedPrefixExpression(array *object.Array, operation func(object.Object) object.Object) object.Object {
+	elements := make([]object.Object, len(array.Elements))
 
-	return &object.Integer{
-		Value: -value,
+	for index, element := range array.Elements {
+		elements[index] = operation(element)
+
+		if isError(elements[index]) {
+			return elements[index]
+		}
+	}
+
+	return &object.Array{
+		Elements: elements,
 	}
 }
 
@@ -131,7 +180,54 @@ func evalIntgerInfixExpression(operator string, left, right object.Object) objec
 			Value: leftValue * rightValue,
 		}
 	case "/":
-		return &object.Integer{
+		return object.DivideIntegers(leftValue, rightValue)
+	case "<":
+		return nativeBoolToBooleanObject(leftValue < rightValue)
+	case ">":
+		return nativeBoolToBooleanObject(leftValue > rightValue)
+	case "==":
+		return nativeBoolToBooleanObject(leftValue == rightValue)
+	case "!=":
+		return nativeBoolToBooleanObject(leftValue != rightValue)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// toDouble :
+func toDouble(obj object.Object) float64 {
+	if integer, ok := obj.(*object.Integer); ok {
+		return float64(integer.Value)
+	}
+
+	return obj.(*object.Double).Value
+}
+
+// isNumeric :
+func isNumeric(obj object.Object) bool {
+	return object.INTEGER_OBJECT == obj.Type() || object.DOUBLE_OBJECT == obj.Type()
+}
+
+// evalDoubleInfixExpression : whenever a double is involved, the integer is promoted to double
+func evalDoubleInfixExpression(operator string, left, right object.Object) object.Object {
+	leftValue := toDouble(left)
+	rightValue := toDouble(right)
+
+	switch operator {
+	case "+":
+		return &object.Double{
+			Value: leftValue + rightValue,
+		}
+	case "-":
+		return &object.Double{
+			Value: leftValue - rightValue,
+		}
+	case "*":
+		return &object.Double{
+			Value: leftValue * rightValue,
+		}
+	case "/":
+		return &object.Double{
 			Value: leftValue / rightValue,
 		}
 	case "<":
@@ -149,23 +245,99 @@ func evalIntgerInfixExpression(operator string, left, right object.Object) objec
 
 // evalStringInfixExpression :
 func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
-	if "+" != operator {
+	leftValue := left.(*object.String).Value
+	rightValue := right.(*object.String).Value
+
+	switch operator {
+	case "+":
+		return &object.String{
+			Value: leftValue + rightValue,
+		}
+	case "==":
+		return nativeBoolToBooleanObject(leftValue == rightValue)
+	case "!=":
+		return nativeBoolToBooleanObject(leftValue != rightValue)
+	default:
 		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
+}
 
-	leftValue := left.(*object.String).Value
-	rightValue := right.(*object.String).Value
+// vectorElement : scalars are recycled against every element of the other side
+func vectorElement(obj object.Object, index int) object.Object {
+	if array, ok := obj.(*object.Array); ok {
+		return array.Elements[index]
+	}
+
+	return obj
+}
 
-	return &object.String{
-		Value: leftValue + rightValue,
+// evalVectorizedInfixExpression : operations over arrays are applied element-wise, just like in R
+func evalVectorizedInfixExpression(operator string, left, right object.Object) object.Object {
+	length := -1
+
+	for _, side := range []object.Object{left, right} {
+		array, ok := side.(*object.Array)
+
+		if !ok {
+			continue
+		}
+
+		if -1 != length && len(array.Elements) != length {
+			return newError("vector lengths differ: %d and %d", length, len(array.Elements))
+		}
+
+		length = len(array.Elements)
+	}
+
+	elements := make([]object.Object, length)
+
+	for index := range elements {
+		elements[index] = evalInfixExpression(operator, vectorElement(left, index), vectorElement(right, index))
+
+		if isError(elements[index]) {
+			return elements[index]
+		}
+	}
+
+	return &object.Array{
+		Elements: elements,
 	}
 }
 
+// isArithmetic :
+func isArithmetic(operator string) bool {
+	switch operator {
+	case "+", "-", "*", "/":
+		return true
+	}
+
+	return false
+}
+
+// evalMatrixInfixExpression :
+func evalMatrixInfixExpression(operator string, left, right object.Object) object.Object {
+	result, err := object.MatrixOperation(operator, left, right)
+
+	if nil != err {
+		return err
+	}
+
+	return result
+}
+
 // evalInfixExpression :
 func evalInfixExpression(operator string, left, right object.Object) object.Object {
 	switch {
+	case "%*%" == operator || isArithmetic(operator) && (left.Type() == object.MATRIX_OBJECT || right.Type() == object.MATRIX_OBJECT):
+		return evalMatrixInfixExpression(operator, left, right)
 	case left.Type() == object.INTEGER_OBJECT && right.Type() == object.INTEGER_OBJECT:
 		return evalIntgerInfixExpression(operator, left, right)
+	case isNumeric(left) && isNumeric(right):
+		return evalDoubleInfixExpression(operator, left, right)
+	case left.Type() == object.ARRAY_OBJECT || right.Type() == object.ARRAY_OBJECT:
+		return evalVectorizedInfixExpression(operator, left, right)
+	case object.NA == left || object.NA == right:
+		return object.NA
 	case left.Type() == object.STRING_OBJECT && right.Type() == object.STRING_OBJECT:
 		return evalStringInfixExpression(operator, left, right)
 	case "==" == operator:
@@ -209,6 +381,79 @@ func evalConditionalExpression(ce *ast.ConditionalExpression, environment *objec
 	return NULL
 }
 
+// evalTypeStatement : every alternative of the type is bound to the constant builtin making it
+func evalTypeStatement(node *ast.TypeStatement, environment *object.Environment) object.Object {
+	for _, constructor := range node.Constructors {
+		name := constructor.Name.Value
+		fields := []string{}
+
+		for _, field := range constructor.Fields {
+			fields = append(fields, field.Value)
+		}
+
+		if field, ok := environment.Get(name); ok && field.Constant {
+			return newError("constant '%s' value cannot be overwritten", name)
+		}
+
+		environment.Set(name, true, object.NewConstructor(node.Name.Value, name, fields))
+
+		if err := evalExport(name, node.Exported, environment); nil != err {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// evalMatchExpression : the body of the first arm whose pattern the subject fits, the fields it names being bound
+// just as `let` would
+func evalMatchExpression(node *ast.MatchExpression, environment *object.Environment) object.Object {
+	subject := Eval(node.Subject, environment)
+
+	if isError(subject) {
+		return subject
+	}
+
+	for _, arm := range node.Arms {
+		if arm.IsWildcard() {
+			return Eval(arm.Body, environment)
+		}
+
+		count := -1
+
+		if nil != arm.Fields {
+			count = len(arm.Fields)
+		}
+
+		values, matched, err := object.Destructure(subject, arm.Constructor.Value, count)
+
+		if nil != err {
+			return err
+		}
+
+		if !matched {
+			continue
+		}
+
+		for index, field := range arm.Fields {
+			if "_" == field.Value {
+				continue
+			}
+
+			// the fields are bound just as by let, which constants can not be
+			if bound, ok := environment.Get(field.Value); ok && bound.Constant {
+				return newError("constant '%s' value cannot be overwritten", field.Value)
+			}
+
+			environment.Set(field.Value, false, values[index])
+		}
+
+		return Eval(arm.Body, environment)
+	}
+
+	return newError("no pattern matches %s", subject.Inspect())
+}
+
 // evalBlockStatement :
 func evalBlockStatement(block *ast.BlockStatement, environment *object.Environment) object.Object {
 	var result object.Object
@@ -241,18 +486,6 @@ func evalLet(let *ast.LetStatement, environment *object.Environment) object.Obje
 	return nil
 }
 
-// consToLet :
-func consToLet(cons *ast.ConstStatement) *ast.LetStatement {
-	return &ast.LetStatement{
-		Token: token.Token{
-			Type:    token.LET,
-			Literal: "LET",
-		},
-		Name:  cons.Name,
-		Value: cons.Value,
-	}
-}
-
 // evalConstant :
 func evalConstant(cons *ast.ConstStatement, environment *object.Environment) object.Object {
 	if field, ok := environment.Get(cons.Name.Value); ok && field.Constant {
@@ -263,11 +496,127 @@ func evalConstant(cons *ast.ConstStatement, environment *object.Environment) obj
 		return newError("builtin function '%s' cannot be overwritten", cons.Name.Value)
 	}
 
-	return evalLet(consToLet(cons), environment)
+	value := Eval(cons.Value, environment)
+
+	if isError(value) {
+		return value
+	}
+
+	environment.Set(cons.Name.Value, true, value)
+
+	return nil
+}
+
+// evalExport : only the bindings of a module, not those of its functions, can be exported
+func evalExport(name string, exported bool, environment *object.Environment) object.Object {
+	if !exported {
+		return nil
+	}
+
+	if !environment.IsOutermost() {
+		return newError("export is only allowed at the top level")
+	}
+
+	environment.Export(name)
+
+	return nil
+}
+
+// evalDeclareStatement : a declared function that is not already there, as the builtins are, is bound to one
+// telling it can not be found when called; declarations are always exported, so a declaration file can be imported
+func evalDeclareStatement(node *ast.DeclareStatement, environment *object.Environment) object.Object {
+	if !environment.IsOutermost() {
+		return newError("declare is only allowed at the top level")
+	}
+
+	name := node.Name.Value
+
+	if builtin, ok := builtins[name]; ok {
+		environment.Set(name, true, builtin)
+	} else if _, ok := environment.Get(name); !ok {
+		parameters := make([]string, len(node.Parameters))
+
+		for index, parameter := range node.Parameters {
+			parameters[index] = parameter.Value
+		}
+
+		environment.Set(name, true, object.Declared(name, parameters))
+	}
+
+	return evalExport(name, true, environment)
+}
+
+// evalImportStatement : a module is evaluated the first time it is imported, in an environment of its own; the names
+// imported are then bound to the values the module exports
+func evalImportStatement(node *ast.ImportStatement, environment *object.Environment) object.Object {
+	if !environment.IsOutermost() {
+		return newError("import is only allowed at the top level")
+	}
+
+	path, err := module.Resolve(environment.Path(), node.Path)
+
+	if nil != err {
+		return newError("%s", err)
+	}
+
+	imported, ok := environment.Loaded(path)
+
+	if !ok {
+		if imported, err = evalModule(path, environment); nil != err {
+			return newError("%s", err)
+		}
+	}
+
+	for _, name := range node.Names {
+		value, ok := imported.Exported(name.Value)
+
+		if !ok {
+			return newError("%s is not exported by %s", name.Value, node.Path)
+		}
+
+		environment.Set(name.Value, true, value)
+	}
+
+	return nil
+}
+
+// evalModule : modules are kept once evaluated, so the next imports only bind names
+func evalModule(path string, environment *object.Environment) (*object.Environment, error) {
+	importers := environment.Importers()
+
+	for _, importer := range importers {
+		if importer == path {
+			return nil, module.Cycle(path, importers)
+		}
+	}
+
+	program, err := module.Load(path)
+
+	if nil != err {
+		return nil, err
+	}
+
+	imported := environment.Module(path)
+
+	if result, ok := Eval(program, imported).(*object.Error); ok {
+		return nil, fmt.Errorf("%s, in %s", result.Message, filepath.Base(path))
+	}
+
+	environment.Keep(imported)
+
+	return imported, nil
 }
 
 // evalIdentifier :
 func evalIdentifier(node *ast.Identifier, environment *object.Environment) object.Object {
+	if "..." == node.Value {
+		return newError("'...' used in an incorrect context")
+	}
+
+	if position, ok := object.DotPosition(node.Value); ok {
+		return evalDot(node.Value, position, environment)
+	}
+
 	if value, ok := environment.Get(node.Value); ok {
 		return value.Value
 	}
@@ -276,7 +625,24 @@ func evalIdentifier(node *ast.Identifier, environment *object.Environment) objec
 		return builtin
 	}
 
-	return newError("identifier not found: " + node.Value)
+	return newError("identifier not found: %s", node.Value)
+}
+
+// evalDot :
+func evalDot(name string, position int, environment *object.Environment) object.Object {
+	dots, ok := environment.Get("...")
+
+	if !ok {
+		return newError("%s used in an incorrect context, no ... to look in", name)
+	}
+
+	dot, err := dots.Value.(*object.List).Dot(position)
+
+	if nil != err {
+		return err
+	}
+
+	return dot
 }
 
 // evalExpression :
@@ -309,16 +675,118 @@ func evalArrayIndexExpression(array, index object.Object) object.Object {
 	return arrayObject.Elements[position]
 }
 
+// evalHashIndexExpression :
+func evalHashIndexExpression(hash, index object.Object) object.Object {
+	key, ok := index.(object.Hashable)
+
+	if !ok {
+		return newError("unusable as hash key: %s", index.Type())
+	}
+
+	value, ok := hash.(*object.Hash).Get(key)
+
+	if !ok {
+		return NULL
+	}
+
+	return value
+}
+
 // evalIndexExpression :
 func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
 	case left.Type() == object.ARRAY_OBJECT && index.Type() == object.INTEGER_OBJECT:
 		return evalArrayIndexExpression(left, index)
+	case left.Type() == object.HASH_OBJECT:
+		return evalHashIndexExpression(left, index)
+	case left.Type() == object.DATA_FRAME_OBJECT:
+		if column := left.(*object.DataFrame).Index(index); nil != column {
+			return column
+		}
+
+		return NULL
+	case left.Type() == object.MATRIX_OBJECT:
+		if element := left.(*object.Matrix).Index(index); nil != element {
+			return element
+		}
+
+		return NULL
+	case left.Type() == object.LIST_OBJECT:
+		if element := left.(*object.List).Index(index); nil != element {
+			return element
+		}
+
+		return NULL
 	default:
 		return newError("index operator not supported: %s", left.Type())
 	}
 }
 
+// evalMatrixIndexExpression :
+func evalMatrixIndexExpression(left, rows, columns object.Object) object.Object {
+	switch left := left.(type) {
+	case *object.DataFrame:
+		return left.Slice(rows, columns)
+	case *object.Matrix:
+		return left.Slice(rows, columns)
+	default:
+		return newError("matrix index operator not supported: %s", left.Type())
+	}
+}
+
+// evalOptionalExpression : empty positions, as in `frame[, 1]`, evaluate to NULL
+func evalOptionalExpression(node ast.Expression, environment *object.Environment) object.Object {
+	if nil == node {
+		return NULL
+	}
+
+	return Eval(node, environment)
+}
+
+// evalArguments : the arguments of a call with the names given to them, nil when they are all given by position;
+// `...` is spread into the arguments it holds
+func evalArguments(arguments []ast.Expression, environment *object.Environment) ([]string, []object.Object) {
+	names := []string{}
+	values := []object.Object{}
+	named := false
+
+	for _, argument := range arguments {
+		name := ""
+
+		if namedArgument, ok := argument.(*ast.NamedArgument); ok {
+			name, argument, named = namedArgument.Name, namedArgument.Value, true
+		}
+
+		if identifier, ok := argument.(*ast.Identifier); ok && "..." == identifier.Value && "" == name {
+			dots, ok := environment.Get("...")
+
+			if !ok {
+				return nil, []object.Object{newError("'...' used in an incorrect context")}
+			}
+
+			names, values = dots.Value.(*object.List).Spread(names, values)
+			named = true
+
+			continue
+		}
+
+		value := Eval(argument, environment)
+
+		if isError(value) {
+			return nil, []object.Object{value}
+		}
+
+		names = append(names, name)
+		values = append(values, value)
+	}
+
+	if !named {
+		return nil, values
+	}
+
+	return names, values
+}
+
 // unwrapReturnValue :
 func unwrapReturnValue(obj object.Object) object.Object {
 	if returnValue, ok := obj.(*object.ReturnValue); ok {
@@ -328,37 +796,93 @@ func unwrapReturnValue(obj object.Object) object.Object {
 	return obj
 }
 
-// extendendFunctionEnvironment :
-func extendendFunctionEnvironment(fn *object.Function, arguments []object.Object) *object.Environment {
+// extendendFunctionEnvironment : the arguments are bound the way R does, by name and then by position, the default
+// values of the parameters left out being evaluated in the new environment, each one after those it names, so they
+// can use the others
+func extendendFunctionEnvironment(fn *object.Function, names []string, arguments []object.Object) (*object.Environment, object.Object) {
 	environment := object.InitializeEnclosedEnvironment(fn.Environment)
+	parameters := make([]string, len(fn.Parameters))
+
+	for index, parameter := range fn.Parameters {
+		parameters[index] = parameter.Value
+	}
+
+	bound, dots, err := object.MatchArguments(parameters, names, arguments)
+
+	if nil != err {
+		return nil, err
+	}
+
+	for index, parameter := range parameters {
+		switch {
+		case "..." == parameter:
+			environment.Set(parameter, true, object.PackDots(dots, names, arguments))
+		case -1 != bound[index]:
+			environment.Set(parameter, true, arguments[bound[index]])
+		}
+	}
+
+	for index, parameter := range parameters {
+		if -1 != bound[index] || "..." == parameter {
+			continue
+		}
 
-	for parameterIdx, parameter := range fn.Parameters {
-		environment.Set(parameter.Value, true, arguments[parameterIdx])
+		if index >= len(fn.Defaults) || nil == fn.Defaults[index] {
+			return nil, object.MissingArgument(parameter)
+		}
+
+		// until its default value is worked out, a parameter stands for the error of using it too early
+		environment.Set(parameter, true, object.RecursiveDefault())
 	}
 
-	return environment
+	for _, index := range ast.DefaultOrder(fn.Parameters, fn.Defaults) {
+		parameter := parameters[index]
+
+		if -1 != bound[index] {
+			continue
+		}
+
+		value := Eval(fn.Defaults[index], environment)
+
+		if isError(value) {
+			return nil, value
+		}
+
+		environment.Set(parameter, true, value)
+	}
+
+	return environment, nil
 }
 
 // memoizationKey :
-func memoizationKey(function *object.Function, parameters []object.Object) string {
-	names := []string{}
-
-	for _, parameter := range parameters {
-		names = append(names, parameter.Inspect())
+func memoizationKey(function *object.Function, names []string, parameters []object.Object) string {
+	values := []string{}
+
+	for index, parameter := range parameters {
+		if index < len(names) && "" != names[index] {
+			values = append(values, names[index]+" = "+parameter.Inspect())
+		} else {
+			values = append(values, parameter.Inspect())
+		}
 	}
 
-	return function.Name + "(" + strings.Join(names, ", ") + ")"
+	return function.Name + "(" + strings.Join(values, ", ") + ")"
 }
 
 // applyDefinedFunction :
-func applyDefinedFunction(function *object.Function, parameters []object.Object, environment *object.Environment) object.Object {
-	memoization := memoizationKey(function, parameters)
+func applyDefinedFunction(function *object.Function, names []string, parameters []object.Object, environment *object.Environment) object.Object {
+	memoization := memoizationKey(function, names, parameters)
 
 	if obj, ok := environment.GetMemoization(memoization); ok {
 		return obj
 	}
 
-	extendendEnvironment := extendendFunctionEnvironment(function, parameters)
+	extendendEnvironment, err := extendendFunctionEnvironment(function, names, parameters)
+
+	if nil != err {
+		return err
+	}
+
 	evaluated := Eval(function.Body, extendendEnvironment)
 	value := unwrapReturnValue(evaluated)
 
@@ -369,13 +893,40 @@ func applyDefinedFunction(function *object.Function, parameters []object.Object,
 	return value
 }
 
-// applyFunction :
-func applyFunction(fn object.Object, parameters []object.Object, environment *object.Environment) object.Object {
+// positionOf :
+func positionOf(tok token.Token) object.Position {
+	return object.Position{
+		Line:   tok.Line,
+		Column: tok.Column,
+	}
+}
+
+// applyFunction : position is where the call is, so builtins can report it; names are those given to the
+// arguments, nil when they are all given by position
+func applyFunction(fn object.Object, names []string, parameters []object.Object, environment *object.Environment, position object.Position) object.Object {
 	switch function := fn.(type) {
 	case *object.Function:
-		return applyDefinedFunction(function, parameters, environment)
+		return applyDefinedFunction(function, names, parameters, environment)
 	case *object.Builtin:
-		if result := function.Fn(parameters...); nil != result {
+		context := &callContext{
+			environment: environment,
+			position:    position,
+		}
+
+		var result object.Object
+		parameters, err := function.Arguments(names, parameters)
+
+		if nil != err {
+			result = err
+		} else {
+			result = function.Fn(context, parameters...)
+		}
+
+		if err, ok := result.(*object.Error); ok && 0 == err.Position.Line {
+			err.Position = position
+		}
+
+		if nil != result {
 			return result
 		}
 
@@ -385,10 +936,113 @@ func applyFunction(fn object.Object, parameters []object.Object, environment *ob
 	}
 }
 
+// signal : hands the condition to the handlers, the innermost first; a calling handler is called right away, with
+// the handlers from its own on left out, while an exiting one makes the evaluation unwind to its tryCatch, as the
+// error given back does -- warnings and messages no handler takes are reported and the evaluation goes on
+func signal(condition *object.Condition, environment *object.Environment, position object.Position) *object.Error {
+	handlers := environment.Handlers()
+
+	for index := handlers.Len() - 1; index >= 0; index-- {
+		handler := handlers.At(index)
+		function := handler.For(condition)
+
+		if nil == function {
+			continue
+		}
+
+		if !handler.Calling {
+			return &object.Error{
+				Message:   condition.Message,
+				Position:  position,
+				Condition: condition,
+			}
+		}
+
+		disabled := handlers.Disable(index)
+		result := applyFunction(function, nil, []object.Object{condition}, environment, position)
+		handlers.Restore(disabled)
+
+		if err, ok := result.(*object.Error); ok {
+			return err
+		}
+	}
+
+	if "error" != condition.Class {
+		condition.Report()
+
+		return nil
+	}
+
+	return &object.Error{
+		Message:   condition.Message,
+		Position:  position,
+		Condition: condition,
+	}
+}
+
+// evalHandling : `tryCatch(expr, error = function(e) ..., finally = ...)` and `withCallingHandlers(expr, ...)`,
+// which establish their handlers while expr is evaluated; errors raised otherwise than by stop, as those of the
+// builtins, are signalled as error conditions once they reach the innermost of them
+func evalHandling(function string, node *ast.CallExpression, environment *object.Environment) object.Object {
+	position := positionOf(node.Token)
+	expression, arguments, finally, err := object.HandlerArguments(function, node.Parameters)
+
+	if nil != err {
+		return &object.Error{
+			Message:  err.Error(),
+			Position: position,
+		}
+	}
+
+	handler := &object.Handler{
+		Functions: map[string]object.Object{},
+		Calling:   "withCallingHandlers" == function,
+	}
+
+	for class, argument := range arguments {
+		value := Eval(argument, environment)
+
+		if isError(value) {
+			return value
+		}
+
+		handler.Functions[class] = value
+	}
+
+	handlers := environment.Handlers()
+	handlers.Push(handler)
+
+	result := guarded(func() object.Object {
+		return evalOptionalExpression(expression, environment)
+	})
+
+	if err, ok := result.(*object.Error); ok && nil == err.Condition {
+		if signalled := signal(object.ConditionOf(err), environment, err.Position); nil != signalled {
+			result = signalled
+		}
+	}
+
+	handlers.Pop()
+
+	if err, ok := result.(*object.Error); ok && !handler.Calling {
+		if function := handler.For(err.Condition); nil != function {
+			result = applyFunction(function, nil, []object.Object{err.Condition}, environment, position)
+		}
+	}
+
+	if nil != finally {
+		if done := Eval(finally, environment); isError(done) {
+			return done
+		}
+	}
+
+	return result
+}
+
 // applyPartialPointFree :
-func applyPartialPointFree(pf *object.PointFree, parameters []object.Object, environment *object.Environment) object.Object {
+func applyPartialPointFree(pf *object.PointFree, parameters []object.Object, environment *object.Environment, position object.Position) object.Object {
 	for index := len(pf.Functions) - 1; index >= 0; index-- {
-		parameters[0] = applyFunction(pf.Functions[index], parameters, environment)
+		parameters[0] = applyFunction(pf.Functions[index], nil, parameters, environment, position)
 
 		if isError(parameters[0]) {
 			return parameters[0]
@@ -400,14 +1054,13 @@ func applyPartialPointFree(pf *object.PointFree, parameters []object.Object, env
 
 // applyPointFree :
 func applyPointFree(pf *ast.PointFreeExpression, environment *object.Environment) object.Object {
-	parameter := Eval(pf.SeedFunction, environment)
+	var parameter object.Object
 
-	if isError(parameter) {
-		return parameter
-	}
+	parameters := evalExpression(pf.Parameters, environment)
 
-	parameters := make([]object.Object, 1)
-	parameters[0] = parameter
+	if 1 == len(parameters) && isError(parameters[0]) {
+		return parameters[0]
+	}
 
 	for index := len(pf.ToCompose) - 1; index >= 0; index-- {
 		function := evalIdentifier(pf.ToCompose[index], environment)
@@ -422,16 +1075,16 @@ func applyPointFree(pf *ast.PointFreeExpression, environment *object.Environment
 
 		switch kind := function.(type) {
 		case *object.PointFree:
-			parameter = applyPartialPointFree(kind, parameters, environment)
+			parameter = applyPartialPointFree(kind, parameters, environment, positionOf(pf.Token))
 		default:
-			parameter = applyFunction(kind, parameters, environment)
+			parameter = applyFunction(kind, nil, parameters, environment, positionOf(pf.Token))
 		}
 
 		if isError(parameter) {
 			return parameter
 		}
 
-		parameters[0] = parameter
+		parameters = []object.Object{parameter}
 	}
 
 	return parameter
@@ -462,7 +1115,7 @@ func evalPartialPointFreeExpression(pf *ast.PointFreeExpression, environment *ob
 
 // evalPointFreeExpression :
 func evalPointFreeExpression(pf *ast.PointFreeExpression, environment *object.Environment) object.Object {
-	if nil != pf.SeedFunction {
+	if nil != pf.Parameters {
 		return applyPointFree(pf, environment)
 	}
 
@@ -483,9 +1136,20 @@ func Eval(node ast.Node, environment *object.Environment) object.Object {
 			Value: node.Value,
 		}
 
+	case *ast.DoubleLiteral:
+		return &object.Double{
+			Value: node.Value,
+		}
+
 	case *ast.Boolean:
 		return nativeBoolToBooleanObject(node.Value)
 
+	case *ast.NotAvailable:
+		return object.NA
+
+	case *ast.Null:
+		return NULL
+
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, environment)
 
@@ -516,6 +1180,12 @@ func Eval(node ast.Node, environment *object.Environment) object.Object {
 	case *ast.ConditionalExpression:
 		return evalConditionalExpression(node, environment)
 
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, environment)
+
+	case *ast.TypeStatement:
+		return evalTypeStatement(node, environment)
+
 	case *ast.ReturnStatement:
 		value := Eval(node.ReturnValue, environment)
 
@@ -534,6 +1204,8 @@ func Eval(node ast.Node, environment *object.Environment) object.Object {
 			return err
 		}
 
+		return evalExport(node.Name.Value, node.Exported, environment)
+
 	case *ast.LetStatement:
 		err := evalLet(node, environment)
 
@@ -541,6 +1213,14 @@ func Eval(node ast.Node, environment *object.Environment) object.Object {
 			return err
 		}
 
+		return evalExport(node.Name.Value, node.Exported, environment)
+
+	case *ast.ImportStatement:
+		return evalImportStatement(node, environment)
+
+	case *ast.DeclareStatement:
+		return evalDeclareStatement(node, environment)
+
 	case *ast.Identifier:
 		return evalIdentifier(node, environment)
 
@@ -549,6 +1229,7 @@ func Eval(node ast.Node, environment *object.Environment) object.Object {
 		body := node.Body
 		function := &object.Function{
 			Parameters:  parameters,
+			Defaults:    node.Defaults,
 			Environment: environment,
 			Body:        body,
 		}
@@ -560,19 +1241,25 @@ func Eval(node ast.Node, environment *object.Environment) object.Object {
 		return function
 
 	case *ast.CallExpression:
+		if identifier, ok := node.Function.(*ast.Identifier); ok && object.IsHandling(identifier.Value) {
+			if _, defined := environment.Get(identifier.Value); !defined {
+				return evalHandling(identifier.Value, node, environment)
+			}
+		}
+
 		function := Eval(node.Function, environment)
 
 		if isError(function) {
 			return function
 		}
 
-		parameters := evalExpression(node.Parameters, environment)
+		names, parameters := evalArguments(node.Parameters, environment)
 
 		if 1 == len(parameters) && isError(parameters[0]) {
 			return parameters[0]
 		}
 
-		return applyFunction(function, parameters, environment)
+		return applyFunction(function, names, parameters, environment, positionOf(node.Token))
 
 	case *ast.StringLiteral:
 		return &object.String{
@@ -597,13 +1284,23 @@ func Eval(node ast.Node, environment *object.Environment) object.Object {
 			return left
 		}
 
-		index := Eval(node.Index, environment)
+		index := evalOptionalExpression(node.Index, environment)
 
 		if isError(index) {
 			return index
 		}
 
-		return evalIndexExpression(left, index)
+		if !node.Matrix {
+			return evalIndexExpression(left, index)
+		}
+
+		column := evalOptionalExpression(node.Column, environment)
+
+		if isError(column) {
+			return column
+		}
+
+		return evalMatrixIndexExpression(left, index, column)
 
 	case *ast.PointFreeExpression:
 		return evalPointFreeExpression(node, environment)
@@ -1,6 +1,12 @@
 package evaluator
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"../lexer"
@@ -812,3 +818,1217 @@ func TestPointFree(t *testing.T) {
 		testIntegerObject(t, testEval(tt.input), tt.expected)
 	}
 }
+
+// TestHashes :
+func TestHashes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			`hash("one", 1, "two", 2)["two"]`,
+			2,
+		},
+		{
+			`hash(1, 10, TRUE, 20)[TRUE]`,
+			20,
+		},
+		{
+			`hash("one", 1)["three"]`,
+			nil,
+		},
+		{
+			`let h <- hash("one", 1); h["o" + "ne"]`,
+			1,
+		},
+		{
+			`head(keys(hash(3, "c", 1, "a")))`,
+			3,
+		},
+		{
+			`last(values(hash("c", 3, "a", 1, "c", 4)))`,
+			1,
+		},
+		{
+			`let h <- hash("one", 1); let other <- assoc(h, "two", 2); len(keys(h)) + other["two"]`,
+			3,
+		},
+		{
+			`has_key(hash("one", 1), "one")`,
+			true,
+		},
+		{
+			`hash("one", 1)[[1]]`,
+			"unusable as hash key: ARRAY",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+
+			if !ok {
+				t.Errorf("object is not Error, got=%T (%+v)", evaluated, evaluated)
+
+				continue
+			}
+
+			if errObj.Message != expected {
+				t.Errorf("wrong error message, expected=%q, got=%q", expected, errObj.Message)
+			}
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+// TestDoubleExpressions :
+func TestDoubleExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{
+			"1.5 + 1",
+			2.5,
+		},
+		{
+			"3 / 2.0",
+			1.5,
+		},
+		{
+			"-0.5 * 4",
+			-2,
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		result, ok := evaluated.(*object.Double)
+
+		if !ok {
+			t.Errorf("object is not Double, got=%T (%+v)", evaluated, evaluated)
+
+			continue
+		}
+
+		if result.Value != tt.expected {
+			t.Errorf("object has wrong value, got=%f, expected was=%f", result.Value, tt.expected)
+		}
+	}
+}
+
+// TestDataFrames :
+func TestDataFrames(t *testing.T) {
+	frame := `let frame <- data.frame("name", ["ana", "bob", "carl"], "age", [31, 27, 45]);`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			frame + "nrow(frame)",
+			3,
+		},
+		{
+			`nrow(data.frame("x", "a", "y", 1))`,
+			1,
+		},
+		{
+			frame + "frame$age[2]",
+			45,
+		},
+		{
+			frame + `frame[frame$age > 30, "age"][1]`,
+			45,
+		},
+		{
+			frame + `nrow(filter(frame, !(frame$name == "bob")))`,
+			2,
+		},
+		{
+			frame + `mutate(frame, "age", frame$age + 1)$age[0]`,
+			32,
+		},
+		{
+			frame + `arrange(frame, "age")$age[0]`,
+			27,
+		},
+		{
+			frame + `frame[3, ]`,
+			"row index out of range: 3",
+		},
+		{
+			frame + `frame[, "height"]`,
+			"undefined column selected: height",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+
+			if !ok {
+				t.Errorf("object is not Error, got=%T (%+v)", evaluated, evaluated)
+
+				continue
+			}
+
+			if errObj.Message != expected {
+				t.Errorf("wrong error message, expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+// TestNotAvailable :
+func TestNotAvailable(t *testing.T) {
+	tests := []string{
+		"NA",
+		"NA + 1",
+		"2.5 * NA",
+		"NA > 1",
+		"-NA",
+		"![TRUE, NA][1]",
+		"([1, NA, 3] * 2)[1]",
+		"7 / 0",
+		"([4, 6] / [2, 0])[1]",
+		`(data.frame("a", [4, 6])$a / 0)[0]`,
+		"(-9223372036854775807 - 1) / -1",
+	}
+
+	for _, input := range tests {
+		if evaluated := testEval(input); object.NA != evaluated {
+			t.Errorf("object is not NA, got=%T (%+v)", evaluated, evaluated)
+		}
+	}
+}
+
+// TestJSON :
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`toJSON(hash("name", "ana", "age", 31, "tags", ["a", "b"]))`,
+			`{"name":"ana","age":31,"tags":["a","b"]}`,
+		},
+		{
+			`toJSON([1.5, NA, 2])`,
+			`[1.5,null,2]`,
+		},
+		{
+			`toJSON(2.0)`,
+			`2.0`,
+		},
+		{
+			`toJSON(data.frame("x", [1, NA], "y", ["a", "b"]))`,
+			`[{"x":1,"y":"a"},{"y":"b"}]`,
+		},
+		{
+			`toJSON(hash("a", [1, 2]), TRUE)`,
+			"{\n  \"a\": [\n    1,\n    2\n  ]\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		result, ok := evaluated.(*object.String)
+
+		if !ok {
+			t.Errorf("object is not String, got=%T (%+v)", evaluated, evaluated)
+
+			continue
+		}
+
+		if result.Value != tt.expected {
+			t.Errorf("wrong JSON, expected=%q, got=%q", tt.expected, result.Value)
+		}
+	}
+
+	roundTrips := []string{
+		`hash("name", "ana", "age", 31, "tags", ["a", "b"])`,
+		`[1.5, NA, 2.0]`,
+		`[1, "a", TRUE]`,
+		`[hash("a", 1), [1, 2]]`,
+		`data.frame("x", [1, NA], "y", ["a", "b"])`,
+		`hash("inner", hash("flag", FALSE, "score", 0.25))`,
+	}
+
+	for _, input := range roundTrips {
+		original := testEval(input)
+		evaluated := testEval("fromJSON(toJSON(" + input + "))")
+
+		if evaluated.Inspect() != original.Inspect() {
+			t.Errorf("round trip changed the value, expected=%q, got=%q", original.Inspect(), evaluated.Inspect())
+		}
+	}
+
+	if evaluated, ok := testEval(`toJSON(function(x) { x; })`).(*object.Error); !ok || "cannot convert FUNCTION to JSON" != evaluated.Message {
+		t.Errorf("wrong error, got=%+v", evaluated)
+	}
+}
+
+// TestHigherOrderBuiltins :
+func TestHigherOrderBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			`lapply([1, 2, 3], function(x) { x * 2; })[2]`,
+			6,
+		},
+		{
+			`lapply([1, 2, 3], function(x, y) { x * y; }, 2)[2]`,
+			6,
+		},
+		{
+			`sapply([1, 2], function(x, by = 1, plus = 0) { x * by + plus; }, plus = 10)[1]`,
+			12,
+		},
+		{
+			`let offset <- 10; sapply([1, 2], function(x) { [x + offset]; })[1]`,
+			12,
+		},
+		{
+			`Map(function(x, y) { x + y; }, [1, 2, 3], 10)[2]`,
+			13,
+		},
+		{
+			`len(Filter(function(x) { x > 1; }, [3, 1, NA, 2]))`,
+			2,
+		},
+		{
+			`Reduce(function(a, b) { a + b; }, [1, 2, 3], 0, TRUE)[3]`,
+			6,
+		},
+		{
+			`Position(function(x) { x > 1; }, [1, 2, 3], TRUE)`,
+			2,
+		},
+		{
+			`Find(function(x) { x > 1; }, [1, 2, 3])`,
+			2,
+		},
+		{
+			`Find(function(x) { x > 5; }, [1, 2, 3])`,
+			nil,
+		},
+		{
+			`lapply([1, 2], len)`,
+			"parameters to `len` not supported, got=INTEGER",
+		},
+		{
+			`vapply([1, 2], function(x) { "a"; }, 0)`,
+			"values must be INTEGER, but FUN(X[[0]]) result is STRING",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+
+			if !ok {
+				t.Errorf("object is not Error, got=%T (%+v)", evaluated, evaluated)
+
+				continue
+			}
+
+			if errObj.Message != expected {
+				t.Errorf("wrong error message, expected=%q, got=%q", expected, errObj.Message)
+			}
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+// TestBuiltinCallPositions :
+func TestBuiltinCallPositions(t *testing.T) {
+	tests := []struct {
+		input    string
+		message  string
+		position object.Position
+	}{
+		{
+			"len(1)",
+			"parameters to `len` not supported, got=INTEGER",
+			object.Position{
+				Line:   1,
+				Column: 4,
+			},
+		},
+		{
+			"let size <- function(x) {\n  len(x);\n};\nlapply([1], size)",
+			"parameters to `len` not supported, got=INTEGER",
+			object.Position{
+				Line:   2,
+				Column: 6,
+			},
+		},
+		{
+			"sapply([1],\n  head)",
+			"parameter to `head` must be ARRAY, got INTEGER",
+			object.Position{
+				Line:   1,
+				Column: 7,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		err, ok := testEval(tt.input).(*object.Error)
+
+		if !ok {
+			t.Errorf("object is not Error, got=%T", err)
+
+			continue
+		}
+
+		if err.Message != tt.message {
+			t.Errorf("wrong error message, expected=%q, got=%q", tt.message, err.Message)
+		}
+
+		if err.Position != tt.position {
+			t.Errorf("wrong error position, expected=%s, got=%s", tt.position, err.Position)
+		}
+	}
+}
+
+// TestStringBuiltins :
+func TestStringBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`paste("a", 1, TRUE)`,
+			"a 1 TRUE",
+		},
+		{
+			`paste0("x", [1, 2])[1]`,
+			"x2",
+		},
+		{
+			`paste("a", ["x", "y"], sep = "-")[1]`,
+			"a-y",
+		},
+		{
+			`paste("a", ["x", "y"], collapse = "+")`,
+			"a x+a y",
+		},
+		{
+			`paste0("a", ["x", "y"], collapse = "")`,
+			"axay",
+		},
+		{
+			`sprintf("%05.1f and %s", 2.25, "b")`,
+			"002.2 and b",
+		},
+		{
+			`format([1.5, 10.0])[0]`,
+			" 1.5",
+		},
+		{
+			`substr("héllo", 1, 3)`,
+			"éll",
+		},
+		{
+			`strsplit("a-b", "-")[1]`,
+			"b",
+		},
+		{
+			`toupper("abc")`,
+			"ABC",
+		},
+		{
+			`trimws("  abc  ", "right")`,
+			"  abc",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		result, ok := evaluated.(*object.String)
+
+		if !ok {
+			t.Errorf("object is not String, got=%T (%+v)", evaluated, evaluated)
+
+			continue
+		}
+
+		if result.Value != tt.expected {
+			t.Errorf("wrong string, expected=%q, got=%q", tt.expected, result.Value)
+		}
+	}
+
+	testIntegerObject(t, testEval(`nchar("日本語")`), 3)
+	testBooleanObject(t, testEval(`startsWith("apple", "app")`), true)
+}
+
+// TestRegularExpressions :
+func TestRegularExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			`grepl("^E", ["ERROR", "INFO"])[0]`,
+			true,
+		},
+		{
+			`grep("b", ["a", "b", "ab"])[1]`,
+			2,
+		},
+		{
+			`gsub("(a)(b)", "\\2\\1", "abab")`,
+			"baba",
+		},
+		{
+			`sub("+", "-", "1+1", FALSE, FALSE, TRUE)`,
+			"1-1",
+		},
+		{
+			`regexpr("c", "abc")$start[0]`,
+			2,
+		},
+		{
+			`grepl("[", "a")`,
+			"invalid regular expression '[', reason 'missing closing ]'",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			switch result := evaluated.(type) {
+			case *object.String:
+				if result.Value != expected {
+					t.Errorf("wrong string, expected=%q, got=%q", expected, result.Value)
+				}
+			case *object.Error:
+				if result.Message != expected {
+					t.Errorf("wrong error message, expected=%q, got=%q", expected, result.Message)
+				}
+			default:
+				t.Errorf("object is not String nor Error, got=%T (%+v)", evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestMathBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			`sum([0.1, 0.2, 0.3])`,
+			0.6,
+		},
+		{
+			`sum([1, NA, 3], na.rm = TRUE)`,
+			4,
+		},
+		{
+			`sum(1, 2, 3)`,
+			6,
+		},
+		{
+			`max(1, [5, 2])`,
+			5,
+		},
+		{
+			`sum(9007199254740993, 2)`,
+			9007199254740995,
+		},
+		{
+			`median([9007199254740993, 1, 9007199254740995])`,
+			9007199254740993,
+		},
+		{
+			`mean([0.1, 0.2, 0.3])`,
+			0.2,
+		},
+		{
+			`round(2.5) + round(0.15, 1)`,
+			2.1,
+		},
+		{
+			`median([3, 1, 2])`,
+			2,
+		},
+		{
+			`quantile([1, 2, 3, 4, 5, 6, 7, 8, 9, 10])[1]`,
+			3.25,
+		},
+		{
+			`log(1000, 10)`,
+			3.0,
+		},
+		{
+			`max(["a"])`,
+			"parameters to `max` must be numeric, got STRING inside an ARRAY",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case float64:
+			result, ok := evaluated.(*object.Double)
+
+			if !ok || result.Value != expected {
+				t.Errorf("object is not Double %v, got=%T (%+v)", expected, evaluated, evaluated)
+			}
+		case string:
+			result, ok := evaluated.(*object.Error)
+
+			if !ok || result.Message != expected {
+				t.Errorf("wrong error, expected=%q, got=%T (%+v)", expected, evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestRandomNumbers(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{
+			`set.seed(42); runif(3)[2]`,
+			0.2861395347863436,
+		},
+		{
+			`set.seed(42); rnorm(1)[0]`,
+			1.3709584471466685,
+		},
+		{
+			`set.seed(42); let die <- function(n) { sample(6, n, TRUE); }; sum(die(3)) + 0.5`,
+			7.5,
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		result, ok := evaluated.(*object.Double)
+
+		if !ok || result.Value != tt.expected {
+			t.Errorf("object is not Double %v, got=%T (%+v)", tt.expected, evaluated, evaluated)
+		}
+	}
+}
+
+func TestMatrices(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			`let m <- matrix([1, 2, 3, 4, 5, 6], 2, 3); (m %*% t(m))[1, 1]`,
+			56.0,
+		},
+		{
+			`let m <- matrix([1, 2, 3, 4, 5, 6], 2, 3); (-m / 2)[1, 2]`,
+			-3.0,
+		},
+		{
+			`solve(matrix([2, 1, 1, 3], 2, 2), [3, 5])[1]`,
+			1.4,
+		},
+		{
+			`apply(matrix([1, 2, 3, 4, 5, 6], 2, 3), 2, max)[2]`,
+			6.0,
+		},
+		{
+			`let m <- matrix([1, 2, 3, 4, 5, 6], 2, 3); m %*% m`,
+			"non-conformable arguments: 2x3 %*% 2x3",
+		},
+		{
+			`matrix([1, 2, 3, 4], 2, 2) - matrix([1, 2], 1, 2)`,
+			"non-conformable arrays: 2x2 - 1x2",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case float64:
+			result, ok := evaluated.(*object.Double)
+
+			if !ok || result.Value != expected {
+				t.Errorf("object is not Double %v, got=%T (%+v)", expected, evaluated, evaluated)
+			}
+		case string:
+			result, ok := evaluated.(*object.Error)
+
+			if !ok || result.Message != expected {
+				t.Errorf("wrong error, expected=%q, got=%T (%+v)", expected, evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestPipesAndCustomOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{
+			"let `%+%` <- function(a, b) { a * 10 + b; }; 1 %+% 2 %+% 3",
+			123,
+		},
+		{
+			`let twice <- function(x) { x * 2; }; 3 |> twice() %>% twice`,
+			12,
+		},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestDefaultsAndNamedArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			`let f <- function(x, n = 10) { x + n; }; f(1)`,
+			11,
+		},
+		{
+			`let f <- function(x, n = 10) { x + n; }; f(n = 1, 5)`,
+			6,
+		},
+		{
+			`let f <- function(value, number = 2) { value * number; }; f(num = 3, val = 4)`,
+			12,
+		},
+		{
+			`let f <- function(x, n = len(x)) { n; }; f([1, 2, 3])`,
+			3,
+		},
+		{
+			`let g <- function(a = b, b = 1) { a + 1; }; g()`,
+			2,
+		},
+		{
+			`let g <- function(a = b, b = 1) { a; }; g(b = 5)`,
+			5,
+		},
+		{
+			`round(digits = 1, x = 3.14159) * 10 == 31`,
+			true,
+		},
+		{
+			`function(x, n = 1) { x; }()`,
+			`argument "x" is missing, with no default`,
+		},
+		{
+			`function(value, verbose = FALSE) { value; }(v = 1)`,
+			"argument v = 1 matches multiple formal arguments",
+		},
+		{
+			`round(3.2, precision = 1)`,
+			"unused argument (precision = 1)",
+		},
+		{
+			`let g <- function(a = b, b = a) { a; }; g()`,
+			"promise already under evaluation: recursive default argument reference or earlier problems?",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			err, ok := evaluated.(*object.Error)
+
+			if !ok || err.Message != expected {
+				t.Errorf("wrong error for %q, expected=%q, got=%+v", tt.input, expected, evaluated)
+			}
+		}
+	}
+}
+
+func TestVariadicFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			`let f <- function(...) { len(list(...)); }; f(1, 2, 3)`,
+			3,
+		},
+		{
+			`let f <- function(x, ...) { ..2; }; f(1, 2, 3)`,
+			3,
+		},
+		{
+			`let inner <- function(a, b) { a - b; }; let outer <- function(...) { inner(...); }; outer(b = 1, a = 3)`,
+			2,
+		},
+		{
+			`let f <- function(..., number = 1) { len(list(...)); }; f(1, num = 2)`,
+			2,
+		},
+		{
+			`do.call(function(x, y) { x - y; }, list(y = 1, x = 10))`,
+			9,
+		},
+		{
+			`let minus <- function(x, y) { x - y; }; do.call("minus", list(y = 1, x = 10))`,
+			9,
+		},
+		{
+			`do.call("nchar", list("abc"))`,
+			3,
+		},
+		{
+			`do.call("nowhere", list(1))`,
+			"could not find function \"nowhere\"",
+		},
+		{
+			`let f <- function(...) { ..3; }; f(1)`,
+			"the ... list contains fewer than 3 elements",
+		},
+		{
+			`let f <- function(x) { list(...); }; f(1)`,
+			"'...' used in an incorrect context",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			err, ok := evaluated.(*object.Error)
+
+			if !ok || err.Message != expected {
+				t.Errorf("wrong error for %q, expected=%q, got=%+v", tt.input, expected, evaluated)
+			}
+		}
+	}
+}
+
+func TestModules(t *testing.T) {
+	directory, err := ioutil.TempDir("", "typer")
+
+	if nil != err {
+		t.Fatalf("could not create temporary directory: %s", err)
+	}
+
+	defer os.RemoveAll(directory)
+
+	files := map[string]string{
+		"utils.tr":  `export let double <- function(x) { x * 2 }; export scale <- 10; let hidden <- 1; export let unseen <- function() { hidden }`,
+		"shapes.tr": `import { double } from "./utils.tr"; export let area <- function(side) { double(side) * side }`,
+		"a.tr":      `import { b } from "./b.tr"; export let a <- 1`,
+		"b.tr":      `import { a } from "./a.tr"; export let b <- 2`,
+	}
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(directory, name), []byte(content), 0644); nil != err {
+			t.Fatalf("could not write fixture: %s", err)
+		}
+	}
+
+	from := func(name string) string {
+		return fmt.Sprintf("%q", filepath.Join(directory, name))
+	}
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			"import { double, scale } from " + from("utils.tr") + "; double(scale)",
+			20,
+		},
+		{
+			"import { area } from " + from("shapes.tr") + "; area(3)",
+			18,
+		},
+		{
+			"let hidden <- 5; import { unseen } from " + from("utils.tr") + "; unseen() + hidden",
+			6,
+		},
+		{
+			"import { hidden } from " + from("utils.tr"),
+			"hidden is not exported by " + filepath.Join(directory, "utils.tr"),
+		},
+		{
+			"import { a } from " + from("a.tr"),
+			"import cycle: a.tr -> b.tr -> a.tr, in b.tr, in a.tr",
+		},
+		{
+			"import { scale } from " + from("utils.tr") + "; scale <- 1",
+			"constant 'scale' value cannot be overwritten",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			err, ok := evaluated.(*object.Error)
+
+			if !ok || err.Message != expected {
+				t.Errorf("wrong error for %q, expected=%q, got=%+v", tt.input, expected, evaluated)
+			}
+		}
+	}
+}
+
+func TestDeclarations(t *testing.T) {
+	directory, err := ioutil.TempDir("", "typer")
+
+	if nil != err {
+		t.Fatalf("could not create temporary directory: %s", err)
+	}
+
+	defer os.RemoveAll(directory)
+
+	content := "declare function nchar(x: character[]): integer[]\ndeclare function Sys.time(): double"
+
+	if err := ioutil.WriteFile(filepath.Join(directory, "base.d.tr"), []byte(content), 0644); nil != err {
+		t.Fatalf("could not write fixture: %s", err)
+	}
+
+	from := fmt.Sprintf("%q", filepath.Join(directory, "base.d.tr"))
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			`declare function nchar(x: character[]): integer[]; nchar("abcd")`,
+			4,
+		},
+		{
+			`declare function pivot_longer(data: any, ...): any; pivot_longer([1], cols = 2)`,
+			"could not find function \"pivot_longer\"",
+		},
+		{
+			"import { nchar } from " + from + "; nchar(\"abc\")",
+			3,
+		},
+		{
+			"import { Sys.time } from " + from + "; Sys.time()",
+			"could not find function \"Sys.time\"",
+		},
+		{
+			"let f <- function() { declare function g(): any }; f()",
+			"declare is only allowed at the top level",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			err, ok := evaluated.(*object.Error)
+
+			if !ok || err.Message != expected {
+				t.Errorf("wrong error for %q, expected=%q, got=%+v", tt.input, expected, evaluated)
+			}
+		}
+	}
+}
+
+func TestConditions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+		printed  string
+	}{
+		{
+			`tryCatch(stop("boom"), error = function(e) { conditionMessage(e) })`,
+			"boom",
+			"",
+		},
+		{
+			`tryCatch(sapply([1, 0], function(x) { conditionMessage() }), error = function(e) { conditionMessage(e) })`,
+			"runtime error: index out of range [0] with length 0",
+			"",
+		},
+		{
+			`let f <- function() { warning("careful"); 1 }; tryCatch(f(), warning = function(w) { 2 })`,
+			2,
+			"",
+		},
+		{
+			`let f <- function() { warning("careful"); 1 }; f()`,
+			1,
+			"Warning message:\ncareful\n",
+		},
+		{
+			`let f <- function() { message("hello"); 1 }; withCallingHandlers(f(), message = function(m) { warning("seen ", conditionMessage(m)) })`,
+			1,
+			"Warning message:\nseen hello\nhello\n",
+		},
+		{
+			`stop("a", "b", 1)`,
+			object.Error{Message: "ab1"},
+			"",
+		},
+		{
+			`tryCatch(unknown, error = function(e) { conditionMessage(e) })`,
+			"identifier not found: unknown",
+			"",
+		},
+		{
+			`tryCatch(stop("x"), finally = message("done"))`,
+			object.Error{Message: "x"},
+			"done\n",
+		},
+		{
+			`tryCatch(tryCatch(stop("inner"), warning = function(w) { 1 }), error = function(e) { 2 })`,
+			2,
+			"",
+		},
+		{
+			`tryCatch(withCallingHandlers(stop("e"), error = function(e) { message("logged") }), error = function(e) { 3 })`,
+			3,
+			"logged\n",
+		},
+		{
+			`tryCatch(tryCatch(stop("deep"), error = function(e) { stop(e) }), condition = function(e) { conditionMessage(e) })`,
+			"deep",
+			"",
+		},
+		{
+			`tryCatch(1, other = 2)`,
+			object.Error{Message: "tryCatch takes handlers for conditions of class error, warning, message or condition, got other"},
+			"",
+		},
+		{
+			`let tryCatch <- function(x) { x * 2 }; tryCatch(2)`,
+			4,
+			"",
+		},
+		{
+			`sum(9223372036854775806, 2)`,
+			object.NA,
+			"Warning message:\ninteger overflow - use sum(as.numeric(.))\n",
+		},
+		{
+			`tryCatch(cumsum([9223372036854775806, 1, 1]), warning = function(w) { conditionMessage(w) })`,
+			"integer overflow in 'cumsum'; use 'cumsum(as.numeric(.))'",
+			"",
+		},
+	}
+
+	defer func(messages io.Writer) {
+		object.Messages = messages
+	}(object.Messages)
+
+	for _, tt := range tests {
+		var printed bytes.Buffer
+		object.Messages = &printed
+
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if str, ok := evaluated.(*object.String); !ok || str.Value != expected {
+				t.Errorf("wrong value for %q, expected=%q, got=%+v", tt.input, expected, evaluated)
+			}
+		case object.Error:
+			err, ok := evaluated.(*object.Error)
+
+			if !ok || err.Message != expected.Message {
+				t.Errorf("wrong error for %q, expected=%q, got=%+v", tt.input, expected.Message, evaluated)
+			}
+		case *object.NotAvailable:
+			if expected != evaluated {
+				t.Errorf("wrong value for %q, expected=NA, got=%+v", tt.input, evaluated)
+			}
+		}
+
+		if printed.String() != tt.printed {
+			t.Errorf("wrong messages for %q, expected=%q, got=%q", tt.input, tt.printed, printed.String())
+		}
+	}
+}
+
+func TestOptions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			`let half <- function(x) { if (x > 0) { Some(x / 2) } else { None() } }; map(half(8), function(x) { x + 1 })`,
+			"Some(5)",
+		},
+		{
+			`let half <- function(x) { if (x > 0) { Some(x / 2) } else { None() } }; and_then(half(8), half)`,
+			"Some(2)",
+		},
+		{
+			`let half <- function(x) { if (x > 0) { Some(x / 2) } else { None() } }; map(half(-1), half)`,
+			"None()",
+		},
+		{
+			`unwrap_or(and_then(Ok(1), function(x) { Err("too small") }), 10)`,
+			10,
+		},
+		{
+			`unwrap_or(Ok(3), 10)`,
+			3,
+		},
+		{
+			`map(Err("boom"), function(x) { x })`,
+			"Err(boom)",
+		},
+		{
+			`and_then(Some(1), function(x) { x })`,
+			object.Error{Message: "function given to `and_then` must give OPTION, got INTEGER"},
+		},
+		{
+			`unwrap_or(1, 0)`,
+			object.Error{Message: "parameter to `unwrap_or` must be OPTION or RESULT, got INTEGER"},
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if _, ok := evaluated.(*object.Variant); !ok || evaluated.Inspect() != expected {
+				t.Errorf("wrong value for %q, expected=%q, got=%+v", tt.input, expected, evaluated)
+			}
+		case object.Error:
+			err, ok := evaluated.(*object.Error)
+
+			if !ok || err.Message != expected.Message {
+				t.Errorf("wrong error for %q, expected=%q, got=%+v", tt.input, expected.Message, evaluated)
+			}
+		}
+	}
+}
+
+func TestAlgebraicTypes(t *testing.T) {
+	shape := "type Shape = Circle(r: double) | Rect(w: double, h: double) | Empty; " +
+		"let area <- function(s) { match (s) { Circle(r) -> 3 * r * r, Rect(w, h) -> { w * h }, Empty -> 0 } }; "
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			shape + "area(Circle(2))",
+			12,
+		},
+		{
+			shape + "area(Rect(h = 3, w = 2))",
+			6,
+		},
+		{
+			shape + "area(Empty())",
+			0,
+		},
+		{
+			shape + "Rect(1, 2)",
+			"Rect(1, 2)",
+		},
+		{
+			`match (Some(2)) { None -> 0, Some(_) -> 7 }`,
+			7,
+		},
+		{
+			`match (Err("boom")) { Ok(x) -> x, _ -> 1 }`,
+			1,
+		},
+		{
+			`match (None()) { Some(x) -> x }`,
+			object.Error{Message: "no pattern matches None()"},
+		},
+		{
+			`match (Some(1)) { Some(x, y) -> x }`,
+			object.Error{Message: "pattern Some takes 1 fields, got=2"},
+		},
+		{
+			shape + `r <- 1; match (Circle(2)) { Circle(r) -> r }`,
+			object.Error{Message: "constant 'r' value cannot be overwritten"},
+		},
+		{
+			`type Numbers = integer[]; let first <- function<T>(xs: T[]): T { xs[0] }; first([3, 4])`,
+			3,
+		},
+		{
+			`type Tree<T: numeric> = Leaf(value: T) | Node(left: Tree[T], right: Tree[T]); match (Node(Leaf(1), Leaf(2))) { Node(l, _) -> match (l) { Leaf(v) -> v, _ -> 0 }, _ -> 0 }`,
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if _, ok := evaluated.(*object.Variant); !ok || evaluated.Inspect() != expected {
+				t.Errorf("wrong value for %q, expected=%q, got=%+v", tt.input, expected, evaluated)
+			}
+		case object.Error:
+			err, ok := evaluated.(*object.Error)
+
+			if !ok || err.Message != expected.Message {
+				t.Errorf("wrong error for %q, expected=%q, got=%+v", tt.input, expected.Message, evaluated)
+			}
+		}
+	}
+}
@@ -1,6 +1,13 @@
 package lexer
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
 	"../token"
 )
 
@@ -11,55 +18,101 @@ type Lexer struct {
 	position int
 	// current reading position in input (after current char)
 	readPosition int
-	// current char under examination
-	char byte
+	// current char under examination, positions being in bytes
+	char rune
+	// where each line starts in input
+	lines []int
+	// problems found while reading the input, such as unterminated strings
+	errors []string
+	// r tells the input is R source, see InitializeRLexer
+	r bool
+	// the parentheses, brackets and braces left open, in R source, as newlines only end statements outside of
+	// parentheses and brackets
+	brackets []token.TokenType
+}
+
+// escapes : R's escape sequences made of a single character
+var escapes = map[rune]rune{
+	'n':  '\n',
+	't':  '\t',
+	'r':  '\r',
+	'a':  '\a',
+	'b':  '\b',
+	'f':  '\f',
+	'v':  '\v',
+	'\\': '\\',
+	'"':  '"',
+	'\'': '\'',
+	'`':  '`',
+	' ':  ' ',
+	'\n': '\n',
+}
+
+// rawDelimiters : the brackets a raw string can be enclosed by, along with the ones closing them
+var rawDelimiters = map[rune]rune{
+	'(': ')',
+	'[': ']',
+	'{': '}',
 }
 
-// isLetter : maybe PLUS '?' and '!' as valid also in a near future -- R doesn't allow it
-func isLetter(char byte) bool {
-	return ('a' <= char && char <= 'z') || ('A' <= char && char <= 'Z') || char == '_'
+// isLetter : just like R, any Unicode letter is -- `média` is a valid name; maybe PLUS '?' and '!' as valid also in
+// a near future -- R doesn't allow it
+func isLetter(char rune) bool {
+	return unicode.IsLetter(char) || char == '_'
 }
 
-// isDigit :
-func isDigit(char byte) bool {
+// isDigit : numbers are only made of ASCII digits
+func isDigit(char rune) bool {
 	return '0' <= char && char <= '9'
 }
 
+// isIdentifierCharacter : after the first letter, R also allows digits and dots -- `data.frame` or `x1`; combining
+// marks are kept, so decomposed accents are part of the name
+func isIdentifierCharacter(char rune) bool {
+	return isLetter(char) || unicode.IsDigit(char) || unicode.IsMark(char) || '.' == char
+}
+
 // peekChar :
-func (l *Lexer) peekChar() byte {
+func (l *Lexer) peekChar() rune {
 	if l.readPosition >= len(l.input) {
 		return 0
 	}
 
-	return l.input[l.readPosition]
+	char, _ := utf8.DecodeRuneInString(l.input[l.readPosition:])
+
+	return char
 }
 
-// readChar :
+// readChar : invalid UTF-8 is read a byte at a time, as utf8.RuneError
 func (l *Lexer) readChar() {
+	width := 1
+
 	if l.readPosition >= len(l.input) {
 		l.char = 0
 	} else {
-		l.char = l.input[l.readPosition]
+		l.char, width = utf8.DecodeRuneInString(l.input[l.readPosition:])
 	}
 
 	l.position = l.readPosition
-	l.readPosition++
+	l.readPosition += width
 }
 
 // goBackChar :
 func (l *Lexer) goBackChar() {
-	if 0 == l.position {
+	width := 1
+
+	if 0 == l.position || l.position > len(l.input) {
 		l.char = 0
 	} else {
-		l.char = l.input[l.position-1]
+		l.char, width = utf8.DecodeLastRuneInString(l.input[:l.position])
 	}
 
 	l.readPosition = l.position
-	l.position--
+	l.position -= width
 }
 
 // readIt :
-func readIt(l *Lexer, isIt func(char byte) bool) string {
+func readIt(l *Lexer, isIt func(char rune) bool) string {
 	position := l.position
 
 	for isIt(l.char) {
@@ -71,31 +124,330 @@ func readIt(l *Lexer, isIt func(char byte) bool) string {
 
 // readIdentifier :
 func (l *Lexer) readIdentifier() string {
-	return readIt(l, isLetter)
+	return readIt(l, isIdentifierCharacter)
 }
 
-// readNumber :
-func (l *Lexer) readNumber() string {
-	return readIt(l, isDigit)
+// readDots : `...` stands for the extra arguments of a variadic function, and `..1`, `..2` and so on for each of them
+func (l *Lexer) readDots() token.Token {
+	position := l.position
+
+	l.readChar()
+	l.readChar()
+
+	if isDigit(l.char) {
+		readIt(l, isDigit)
+
+		return token.Token{
+			Type:    token.IDENTIFIER,
+			Literal: l.input[position:l.position],
+		}
+	}
+
+	if '.' != l.char {
+		return l.error(position, "unexpected characters '..'")
+	}
+
+	l.readChar()
+
+	return token.Token{
+		Type:    token.ELLIPSIS,
+		Literal: "...",
+	}
 }
 
-// readString :
-func (l *Lexer) readString() string {
-	postion := l.position + 1
+// readNumber : a point followed by a digit turns the integer into a double
+func (l *Lexer) readNumber() (token.TokenType, string) {
+	position := l.position
+
+	readIt(l, isDigit)
+
+	if '.' != l.char || !isDigit(l.peekChar()) {
+		return token.INT, l.input[position:l.position]
+	}
+
+	l.readChar()
+	readIt(l, isDigit)
+
+	return token.DOUBLE, l.input[position:l.position]
+}
+
+// isHexadecimal :
+func isHexadecimal(char rune) bool {
+	return isDigit(char) || ('a' <= char && char <= 'f') || ('A' <= char && char <= 'F')
+}
+
+// readDigits : reads up to max following characters satisfying isIt, leaving the lexer on the last one
+func (l *Lexer) readDigits(max int, isIt func(char rune) bool) string {
+	position := l.readPosition
+
+	for count := 0; count < max && isIt(l.peekChar()); count++ {
+		l.readChar()
+	}
+
+	return l.input[position:l.readPosition]
+}
+
+// readUnicodeEscape : `\u1234`, `\u{1234}`, `\U12345678` or `\U{12345678}`, the lexer being on the `u`
+func (l *Lexer) readUnicodeEscape(out *strings.Builder) string {
+	escape := l.char
+	max := 4
+
+	if 'U' == escape {
+		max = 8
+	}
+
+	braced := '{' == l.peekChar()
+
+	if braced {
+		l.readChar()
+	}
+
+	digits := l.readDigits(max, isHexadecimal)
+
+	if "" == digits {
+		return fmt.Sprintf("'\\%c' used without hex digits in character string", escape)
+	}
+
+	if braced {
+		if '}' != l.peekChar() {
+			return fmt.Sprintf("invalid \\%c{xxxx} sequence", escape)
+		}
+
+		l.readChar()
+	}
+
+	value, _ := strconv.ParseUint(digits, 16, 32)
+
+	switch {
+	case 0 == value:
+		return "nul character not allowed"
+	case !utf8.ValidRune(rune(value)):
+		return fmt.Sprintf("invalid \\%c value %s", escape, digits)
+	}
+
+	out.WriteRune(rune(value))
+
+	return ""
+}
+
+// readEscape : the lexer is on the character after the backslash; gives what is wrong with the escape, if anything
+func (l *Lexer) readEscape(out *strings.Builder) string {
+	if char, ok := escapes[l.char]; ok {
+		out.WriteRune(char)
+
+		return ""
+	}
+
+	switch {
+	case 'x' == l.char:
+		digits := l.readDigits(2, isHexadecimal)
+
+		if "" == digits {
+			return "'\\x' used without hex digits in character string"
+		}
+
+		value, _ := strconv.ParseUint(digits, 16, 8)
+
+		if 0 == value {
+			return "nul character not allowed"
+		}
+
+		out.WriteByte(byte(value))
+	case '0' <= l.char && l.char <= '7':
+		digits := string(l.char) + l.readDigits(2, func(char rune) bool {
+			return '0' <= char && char <= '7'
+		})
+
+		value, _ := strconv.ParseUint(digits, 8, 16)
+
+		if 0 == value {
+			return "nul character not allowed"
+		}
+
+		out.WriteByte(byte(value))
+	case 'u' == l.char, 'U' == l.char:
+		return l.readUnicodeEscape(out)
+	case 0 == l.char:
+		return "unterminated string"
+	default:
+		return fmt.Sprintf("'\\%c' is an unrecognized escape in character string", l.char)
+	}
+
+	return ""
+}
+
+// error : keeps the problem found at the given position of the input, giving an ILLEGAL token for it
+func (l *Lexer) error(position int, message string) token.Token {
+	line, column := l.location(position)
+	message = fmt.Sprintf("%s at line %d, column %d", message, line, column)
+
+	l.errors = append(l.errors, message)
+
+	return token.Token{
+		Type:    token.ILLEGAL,
+		Literal: message,
+	}
+}
+
+// readString : strings are enclosed by either double or single quotes, escapes being R's
+func (l *Lexer) readString() token.Token {
+	var out strings.Builder
+
+	start := l.position
+	quote := l.char
+	problem := ""
+	problemPosition := start
 
 	for {
 		l.readChar()
 
-		if '"' == l.char || 0 == l.char {
-			break
+		switch l.char {
+		case 0:
+			return l.error(start, "unterminated string")
+		case quote:
+			if "" != problem {
+				return l.error(problemPosition, problem)
+			}
+
+			return token.Token{
+				Type:    token.STRING,
+				Literal: out.String(),
+			}
+		case '\\':
+			position := l.position
+			l.readChar()
+
+			if message := l.readEscape(&out); "" != message && "" == problem {
+				problem, problemPosition = message, position
+			}
+
+			if 0 == l.char {
+				return l.error(start, "unterminated string")
+			}
+		default:
+			out.WriteRune(l.char)
+		}
+	}
+}
+
+// readEnclosed : what goes between the current character and the next delimiter on the same line, the lexer being
+// left on the latter
+func (l *Lexer) readEnclosed(delimiter rune) (string, bool) {
+	start := l.position + 1
+	end := strings.IndexAny(l.input[start:], string(delimiter)+"\n")
+
+	if -1 == end || delimiter != rune(l.input[start+end]) {
+		return "", false
+	}
+
+	for l.position < start+end {
+		l.readChar()
+	}
+
+	return l.input[start : start+end], true
+}
+
+// specialOperators : the `%any%` operators the language knows, any other being a call to the function named after it
+var specialOperators = map[string]token.TokenType{
+	token.MATRIX_PRODUCT: token.MATRIX_PRODUCT,
+	"%>%":                token.PIPE,
+}
+
+// readSpecialOperator : R's `%any%` operators, anything but a newline going between the percent signs
+func (l *Lexer) readSpecialOperator() token.Token {
+	start := l.position
+	name, ok := l.readEnclosed('%')
+
+	if !ok {
+		return l.error(start, "unexpected character '%'")
+	}
+
+	literal := "%" + name + "%"
+	tokenType, ok := specialOperators[literal]
+
+	if !ok {
+		tokenType = token.SPECIAL
+	}
+
+	return token.Token{
+		Type:    tokenType,
+		Literal: literal,
+	}
+}
+
+// readQuotedIdentifier : R's backquoted names, as in `%+%` or `my name`, which are never taken as keywords
+func (l *Lexer) readQuotedIdentifier() token.Token {
+	start := l.position
+	name, ok := l.readEnclosed('`')
+
+	switch {
+	case !ok:
+		return l.error(start, "unterminated backquoted name")
+	case "" == name:
+		return l.error(start, "attempt to use zero-length variable name")
+	}
+
+	return token.Token{
+		Type:    token.IDENTIFIER,
+		Literal: name,
+	}
+}
+
+// isRawString : R 4.0 raw strings, as in `r"(...)"`
+func (l *Lexer) isRawString() bool {
+	return ('r' == l.char || 'R' == l.char) && ('"' == l.peekChar() || '\'' == l.peekChar())
+}
+
+// readRawString : `r"(...)"`, `r"[...]"` or `r"{...}"` with any number of dashes between the quote and the bracket,
+// so the closing sequence can be told apart from the content
+func (l *Lexer) readRawString() token.Token {
+	start := l.position
+
+	l.readChar()
+
+	quote := l.char
+	dashes := l.readDigits(len(l.input), func(char rune) bool {
+		return '-' == char
+	})
+
+	l.readChar()
+
+	closing, ok := rawDelimiters[l.char]
+
+	if !ok {
+		// skips what was meant to be the string, so its closing quote does not open another one
+		for 0 != l.char && quote != l.char {
+			l.readChar()
+		}
+
+		return l.error(start, "malformed raw string literal")
+	}
+
+	terminator := string(closing) + dashes + string(quote)
+	length := strings.Index(l.input[l.readPosition:], terminator)
+
+	if -1 == length {
+		for 0 != l.char {
+			l.readChar()
 		}
+
+		return l.error(start, "unterminated string")
+	}
+
+	content := l.input[l.readPosition : l.readPosition+length]
+
+	for count := 0; count < length+len(terminator); count++ {
+		l.readChar()
 	}
 
-	return l.input[postion:l.position]
+	return token.Token{
+		Type:    token.STRING,
+		Literal: content,
+	}
 }
 
 // newToken :
-func newToken(tokenType token.TokenType, char byte) token.Token {
+func newToken(tokenType token.TokenType, char rune) token.Token {
 	return token.Token{
 		Type:    tokenType,
 		Literal: string(char),
@@ -115,24 +467,219 @@ func newPeekedToken(l *Lexer, t token.TokenType) token.Token {
 	}
 }
 
-// skipWhitespace :
-func (l *Lexer) skipWhitespace() {
-	for l.char == ' ' || l.char == '\t' || l.char == '\n' || l.char == '\r' {
+// skipWhitespace : R comments, from `#` to the end of the line, are skipped as well in R source; tells whether a
+// newline was skipped
+func (l *Lexer) skipWhitespace() bool {
+	newline := false
+
+	for {
+		switch {
+		case l.char == ' ' || l.char == '\t' || l.char == '\r':
+		case l.char == '\n':
+			newline = true
+		case l.r && l.char == '#':
+			for 0 != l.char && '\n' != l.char {
+				l.readChar()
+			}
+
+			continue
+		default:
+			return newline
+		}
+
 		l.readChar()
 	}
 }
 
+// location : the line and column of a position in input, both starting at 1; columns are counted in characters
+func (l *Lexer) location(position int) (int, int) {
+	// going back from the very first character leaves the lexer before the input
+	if 0 > position {
+		position = 0
+	}
+
+	line := sort.Search(len(l.lines), func(index int) bool {
+		return l.lines[index] > position
+	})
+
+	// reading past the end of the input keeps moving a byte at a time
+	beyond := 0
+
+	if position > len(l.input) {
+		beyond, position = position-len(l.input), len(l.input)
+	}
+
+	return line, utf8.RuneCountInString(l.input[l.lines[line-1]:position]) + beyond + 1
+}
+
 // NextToken :
 func (l *Lexer) NextToken() token.Token {
+	newline := l.skipWhitespace()
+
+	start := l.position
+	tok := l.readToken()
+	tok.Line, tok.Column = l.location(start)
+
+	if l.r {
+		tok.Newline = newline && (0 == len(l.brackets) || token.LEFT_BRACE == l.brackets[len(l.brackets)-1])
+
+		switch tok.Type {
+		case token.LEFT_PARENTHESIS, token.LEFT_BRACKET, token.LEFT_BRACE:
+			l.brackets = append(l.brackets, tok.Type)
+		case token.RIGHT_PARENTHESIS, token.RIGHT_BRACKET, token.RIGHT_BRACE:
+			if 0 != len(l.brackets) {
+				l.brackets = l.brackets[:len(l.brackets)-1]
+			}
+		}
+	}
+
+	return tok
+}
+
+// rKeywords : the keywords of R source, the others of TypeR being names there
+var rKeywords = map[string]token.TokenType{
+	"if":       token.IF,
+	"else":     token.ELSE,
+	"function": token.FUNCTION,
+	"TRUE":     token.TRUE,
+	"FALSE":    token.FALSE,
+	"NA":       token.NA,
+	"return":   token.RETURN,
+}
+
+// rUnsupported : the R operators TypeR has no counterpart for, the longer ones coming before those they start with
+var rUnsupported = []string{"<<-", "->>", ":::", "::", ":=", "&&", "||", "&", "|", "**", "^", "<=", ">=", "~", "@", "?", ":", "\\"}
+
+// readRNumber : R numbers may start with a point, have an exponent, as in `1e-3`, be hexadecimal, as in `0xFF`, or
+// end with the `L` marking integers, as in `10L`; whole numbers are integers, as they are in TypeR
+func (l *Lexer) readRNumber() token.Token {
+	position := l.position
+	tokenType := token.TokenType(token.INT)
+
+	if '0' == l.char && ('x' == l.peekChar() || 'X' == l.peekChar()) {
+		l.readChar()
+		l.readChar()
+		readIt(l, isHexadecimal)
+	} else {
+		readIt(l, isDigit)
+
+		if '.' == l.char {
+			tokenType = token.DOUBLE
+
+			l.readChar()
+			readIt(l, isDigit)
+		}
+
+		if 'e' == l.char || 'E' == l.char {
+			tokenType = token.DOUBLE
+
+			l.readChar()
+
+			if '+' == l.char || '-' == l.char {
+				l.readChar()
+			}
+
+			if !isDigit(l.char) {
+				return l.error(position, "malformed number")
+			}
+
+			readIt(l, isDigit)
+		}
+	}
+
+	literal := l.input[position:l.position]
+
+	if 'L' != l.char {
+		return token.Token{
+			Type:    tokenType,
+			Literal: literal,
+		}
+	}
+
+	l.readChar()
+
+	if token.DOUBLE == tokenType {
+		value, _ := strconv.ParseFloat(literal, 64)
+
+		if value != float64(int64(value)) {
+			return l.error(position, fmt.Sprintf("%sL is not an integer", literal))
+		}
+
+		literal = strconv.FormatInt(int64(value), 10)
+	}
+
+	return token.Token{
+		Type:    token.INT,
+		Literal: literal,
+	}
+}
+
+// readRToken : what R source reads differently from TypeR, if the current character starts any of it
+func (l *Lexer) readRToken() (token.Token, bool) {
+	if l.position >= len(l.input) {
+		return token.Token{}, false
+	}
+
+	rest := l.input[l.position:]
+
+	switch {
+	case l.isRawString(), strings.HasPrefix(rest, "|>"):
+		return token.Token{}, false
+	case isLetter(l.char), '.' == l.char && isLetter(l.peekChar()):
+		literal := l.readIdentifier()
+		tokenType, ok := rKeywords[literal]
+
+		if !ok {
+			tokenType = token.IDENTIFIER
+		}
+
+		return token.Token{
+			Type:    tokenType,
+			Literal: literal,
+		}, true
+	case isDigit(l.char), '.' == l.char && isDigit(l.peekChar()):
+		return l.readRNumber(), true
+	case strings.HasPrefix(rest, "->") && !strings.HasPrefix(rest, "->>"):
+		tok := newPeekedToken(l, token.RIGHT_ASSIGN)
+		l.readChar()
+
+		return tok, true
+	}
+
+	for _, operator := range rUnsupported {
+		if strings.HasPrefix(rest, operator) {
+			start := l.position
+
+			for range operator {
+				l.readChar()
+			}
+
+			return l.error(start, fmt.Sprintf("`%s` is not supported", operator)), true
+		}
+	}
+
+	return token.Token{}, false
+}
+
+// readToken :
+func (l *Lexer) readToken() token.Token {
 	var tok token.Token
 
-	l.skipWhitespace()
+	if l.r {
+		if tok, ok := l.readRToken(); ok {
+			return tok
+		}
+	}
 
 	switch l.char {
 	case '+':
 		tok = newToken(token.PLUS, l.char)
 	case '-':
-		tok = newToken(token.MINUS, l.char)
+		if l.peekChar() == '>' {
+			tok = newPeekedToken(l, token.RIGHT_ASSIGN)
+		} else {
+			tok = newToken(token.MINUS, l.char)
+		}
 	case '*':
 		tok = newToken(token.ASTERISK, l.char)
 	case '/':
@@ -150,13 +697,31 @@ func (l *Lexer) NextToken() token.Token {
 	case ']':
 		tok = newToken(token.RIGHT_BRACKET, l.char)
 	case '.':
+		if strings.HasPrefix(l.input[l.position:], "..") {
+			return l.readDots()
+		}
+
 		tok = newToken(token.POINT, l.char)
+	case '$':
+		tok = newToken(token.DOLLAR, l.char)
 	case ',':
 		tok = newToken(token.COMMA, l.char)
 	case ';':
 		tok = newToken(token.SEMICOLON, l.char)
+	case ':':
+		tok = newToken(token.COLON, l.char)
 	case '>':
 		tok = newToken(token.GREATER_THAN, l.char)
+	case '%':
+		tok = l.readSpecialOperator()
+	case '|':
+		if l.peekChar() == '>' {
+			tok = newPeekedToken(l, token.PIPE)
+		} else {
+			tok = newToken(token.BAR, l.char)
+		}
+	case '`':
+		tok = l.readQuotedIdentifier()
 	case '<':
 		if l.peekChar() == '-' {
 			tok = newPeekedToken(l, token.ASSIGN)
@@ -169,8 +734,7 @@ func (l *Lexer) NextToken() token.Token {
 		if l.peekChar() == '=' {
 			tok = newPeekedToken(l, token.DOUBLE_EQUAL)
 		} else {
-			// In lists
-			tok = newToken(token.ILLEGAL, l.char)
+			tok = newToken(token.EQUAL, l.char)
 		}
 	case '!':
 		if l.peekChar() == '=' {
@@ -178,25 +742,25 @@ func (l *Lexer) NextToken() token.Token {
 		} else {
 			tok = newToken(token.BANG, l.char)
 		}
-	case '"':
-		tok.Type = token.STRING
-		tok.Literal = l.readString()
+	case '"', '\'':
+		tok = l.readString()
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
 	default:
-		if isLetter(l.char) {
+		if l.isRawString() {
+			tok = l.readRawString()
+		} else if isLetter(l.char) {
 			tok.Literal = l.readIdentifier()
 			tok.Type = token.LookupIdentifier(tok.Literal)
 
 			return tok
 		} else if isDigit(l.char) {
-			tok.Type = token.INT
-			tok.Literal = l.readNumber()
+			tok.Type, tok.Literal = l.readNumber()
 
 			return tok
 		} else {
-			tok = newToken(token.ILLEGAL, l.char)
+			tok = l.error(l.position, fmt.Sprintf("unexpected character '%c'", l.char))
 		}
 	}
 
@@ -205,19 +769,47 @@ func (l *Lexer) NextToken() token.Token {
 	return tok
 }
 
-// PreviousToken :
+// PreviousToken : the token is read again, so its problems, if any, are not reported twice
 func (l *Lexer) PreviousToken() token.Token {
+	errors := len(l.errors)
+
 	l.goBackChar()
 	token := l.NextToken()
 	l.goBackChar()
 
+	l.errors = l.errors[:errors]
+
 	return token
 }
 
+// Errors :
+func (l *Lexer) Errors() []string {
+	return l.errors
+}
+
 // InitializeLexer :
 func InitializeLexer(input string) *Lexer {
-	l := &Lexer{input: input}
+	l := &Lexer{
+		input: input,
+		lines: []int{0},
+	}
+
+	for position, char := range input {
+		if '\n' == char {
+			l.lines = append(l.lines, position+1)
+		}
+	}
+
 	l.readChar()
 
 	return l
 }
+
+// InitializeRLexer : a lexer reading R source, for the subset of R TypeR has a counterpart for; comments and R
+// numbers are read, the keywords are R's, and the operators TypeR does not have are reported
+func InitializeRLexer(input string) *Lexer {
+	l := InitializeLexer(input)
+	l.r = true
+
+	return l
+}
@@ -388,3 +388,684 @@ f . g(x)
 		}
 	}
 }
+
+// TestNumbersAndDottedIdentifiers :
+func TestNumbersAndDottedIdentifiers(t *testing.T) {
+	input := `frame <- data.frame("x1", [1.5, 22])
+frame$x1
+f . g(3.)
+f.g(1)`
+
+	test := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{
+			token.IDENTIFIER,
+			"frame",
+		},
+		{
+			token.ASSIGN,
+			"<-",
+		},
+		{
+			token.IDENTIFIER,
+			"data.frame",
+		},
+		{
+			token.LEFT_PARENTHESIS,
+			"(",
+		},
+		{
+			token.STRING,
+			"x1",
+		},
+		{
+			token.COMMA,
+			",",
+		},
+		{
+			token.LEFT_BRACKET,
+			"[",
+		},
+		{
+			token.DOUBLE,
+			"1.5",
+		},
+		{
+			token.COMMA,
+			",",
+		},
+		{
+			token.INT,
+			"22",
+		},
+		{
+			token.RIGHT_BRACKET,
+			"]",
+		},
+		{
+			token.RIGHT_PARENTHESIS,
+			")",
+		},
+		{
+			token.IDENTIFIER,
+			"frame",
+		},
+		{
+			token.DOLLAR,
+			"$",
+		},
+		{
+			token.IDENTIFIER,
+			"x1",
+		},
+		{
+			token.IDENTIFIER,
+			"f",
+		},
+		{
+			token.POINT,
+			".",
+		},
+		{
+			token.IDENTIFIER,
+			"g",
+		},
+		{
+			token.LEFT_PARENTHESIS,
+			"(",
+		},
+		{
+			token.INT,
+			"3",
+		},
+		{
+			token.POINT,
+			".",
+		},
+		{
+			token.RIGHT_PARENTHESIS,
+			")",
+		},
+		{
+			token.IDENTIFIER,
+			"f.g",
+		},
+		{
+			token.LEFT_PARENTHESIS,
+			"(",
+		},
+		{
+			token.INT,
+			"1",
+		},
+		{
+			token.RIGHT_PARENTHESIS,
+			")",
+		},
+		{
+			token.EOF,
+			"",
+		},
+	}
+
+	l := InitializeLexer(input)
+
+	for i, tt := range test {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - token type wrong\n\texpected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong\n\texpected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+// TestTokenPositions :
+func TestTokenPositions(t *testing.T) {
+	input := `x <- 1
+  lapply(x, "f")`
+
+	test := []struct {
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{
+			"x",
+			1,
+			1,
+		},
+		{
+			"<-",
+			1,
+			3,
+		},
+		{
+			"1",
+			1,
+			6,
+		},
+		{
+			"lapply",
+			2,
+			3,
+		},
+		{
+			"(",
+			2,
+			9,
+		},
+		{
+			"x",
+			2,
+			10,
+		},
+		{
+			",",
+			2,
+			11,
+		},
+		{
+			"f",
+			2,
+			13,
+		},
+		{
+			")",
+			2,
+			16,
+		},
+		{
+			"",
+			2,
+			17,
+		},
+	}
+
+	l := InitializeLexer(input)
+
+	for i, tt := range test {
+		tok := l.NextToken()
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong\n\texpected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - position wrong\n\texpected=%d:%d, got=%d:%d", i, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+	}
+}
+
+func TestStrings(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedLiteral string
+	}{
+		{
+			`"say \"hi\""`,
+			`say "hi"`,
+		},
+		{
+			`"a\tb\nc"`,
+			"a\tb\nc",
+		},
+		{
+			`'it\'s "quoted"'`,
+			`it's "quoted"`,
+		},
+		{
+			`"\\d+"`,
+			`\d+`,
+		},
+		{
+			`"\x41\101\u00e9\u{e9}\U0001F600\U{1F600}"`,
+			"AAéé😀😀",
+		},
+		{
+			`r"(C:\path\to "file")"`,
+			`C:\path\to "file"`,
+		},
+		{
+			`R'[\d]'`,
+			`\d`,
+		},
+		{
+			`r"-(a)" still)-"`,
+			`a)" still`,
+		},
+	}
+
+	for i, tt := range tests {
+		l := InitializeLexer(tt.input)
+		tok := l.NextToken()
+
+		if token.STRING != tok.Type {
+			t.Fatalf("tests[%d] - tokentype wrong\n\texpected=%q, got=%q (%v)", i, token.STRING, tok.Type, l.Errors())
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong\n\texpected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if token.EOF != l.NextToken().Type {
+			t.Fatalf("tests[%d] - the string should be the whole input", i)
+		}
+	}
+}
+
+func TestStringErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{
+			"x <- \"open\n",
+			"unterminated string at line 1, column 6",
+		},
+		{
+			`y <- 'a\'`,
+			"unterminated string at line 1, column 6",
+		},
+		{
+			`"\d"`,
+			`'\d' is an unrecognized escape in character string at line 1, column 2`,
+		},
+		{
+			`"\u{zz}"`,
+			`'\u' used without hex digits in character string at line 1, column 2`,
+		},
+		{
+			`"\u{1F600}"`,
+			`invalid \u{xxxx} sequence at line 1, column 2`,
+		},
+		{
+			`"\0"`,
+			"nul character not allowed at line 1, column 2",
+		},
+		{
+			`r"abc"`,
+			"malformed raw string literal at line 1, column 1",
+		},
+		{
+			`r"(abc"`,
+			"unterminated string at line 1, column 1",
+		},
+		{
+			"a # b",
+			"unexpected character '#' at line 1, column 3",
+		},
+	}
+
+	for i, tt := range tests {
+		l := InitializeLexer(tt.input)
+
+		for tok := l.NextToken(); token.EOF != tok.Type; tok = l.NextToken() {
+		}
+
+		if 1 != len(l.Errors()) {
+			t.Fatalf("tests[%d] - wrong number of errors, got=%v", i, l.Errors())
+		}
+
+		if l.Errors()[0] != tt.expectedError {
+			t.Fatalf("tests[%d] - error wrong\n\texpected=%q, got=%q", i, tt.expectedError, l.Errors()[0])
+		}
+	}
+}
+
+func TestUnicodeIdentifiers(t *testing.T) {
+	input := `média <- "ção"; número.2 <- média
+  π_ñ → 1`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{
+			token.IDENTIFIER,
+			"média",
+			1,
+			1,
+		},
+		{
+			token.ASSIGN,
+			"<-",
+			1,
+			7,
+		},
+		{
+			token.STRING,
+			"ção",
+			1,
+			10,
+		},
+		{
+			token.SEMICOLON,
+			";",
+			1,
+			15,
+		},
+		{
+			token.IDENTIFIER,
+			"número.2",
+			1,
+			17,
+		},
+		{
+			token.ASSIGN,
+			"<-",
+			1,
+			26,
+		},
+		{
+			token.IDENTIFIER,
+			"média",
+			1,
+			29,
+		},
+		{
+			token.IDENTIFIER,
+			"π_ñ",
+			2,
+			3,
+		},
+		{
+			token.ILLEGAL,
+			"unexpected character '→' at line 2, column 7",
+			2,
+			7,
+		},
+		{
+			token.INT,
+			"1",
+			2,
+			9,
+		},
+		{
+			token.EOF,
+			"",
+			2,
+			10,
+		},
+	}
+
+	l := InitializeLexer(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - token wrong\n\texpected=%s %q, got=%s %q", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - position wrong\n\texpected=%d:%d, got=%d:%d", i, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+	}
+}
+
+func TestOperatorsAndQuotedNames(t *testing.T) {
+	input := "`%+%` <- f; a %+% b |> g() %>% h %*% m %"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{
+			token.IDENTIFIER,
+			"%+%",
+			1,
+			1,
+		},
+		{
+			token.ASSIGN,
+			"<-",
+			1,
+			7,
+		},
+		{
+			token.IDENTIFIER,
+			"f",
+			1,
+			10,
+		},
+		{
+			token.SEMICOLON,
+			";",
+			1,
+			11,
+		},
+		{
+			token.IDENTIFIER,
+			"a",
+			1,
+			13,
+		},
+		{
+			token.SPECIAL,
+			"%+%",
+			1,
+			15,
+		},
+		{
+			token.IDENTIFIER,
+			"b",
+			1,
+			19,
+		},
+		{
+			token.PIPE,
+			"|>",
+			1,
+			21,
+		},
+		{
+			token.IDENTIFIER,
+			"g",
+			1,
+			24,
+		},
+		{
+			token.LEFT_PARENTHESIS,
+			"(",
+			1,
+			25,
+		},
+		{
+			token.RIGHT_PARENTHESIS,
+			")",
+			1,
+			26,
+		},
+		{
+			token.PIPE,
+			"%>%",
+			1,
+			28,
+		},
+		{
+			token.IDENTIFIER,
+			"h",
+			1,
+			32,
+		},
+		{
+			token.MATRIX_PRODUCT,
+			"%*%",
+			1,
+			34,
+		},
+		{
+			token.IDENTIFIER,
+			"m",
+			1,
+			38,
+		},
+		{
+			token.ILLEGAL,
+			"unexpected character '%' at line 1, column 40",
+			1,
+			40,
+		},
+		{
+			token.EOF,
+			"",
+			1,
+			41,
+		},
+	}
+
+	l := InitializeLexer(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - token wrong\n\texpected=%s %q, got=%s %q", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - position wrong\n\texpected=%d:%d, got=%d:%d", i, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+	}
+}
+
+func TestDots(t *testing.T) {
+	input := "function(x, ...) { ..12 }; .."
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedColumn  int
+	}{
+		{token.FUNCTION, "function", 1},
+		{token.LEFT_PARENTHESIS, "(", 9},
+		{token.IDENTIFIER, "x", 10},
+		{token.COMMA, ",", 11},
+		{token.ELLIPSIS, "...", 13},
+		{token.RIGHT_PARENTHESIS, ")", 16},
+		{token.LEFT_BRACE, "{", 18},
+		{token.IDENTIFIER, "..12", 20},
+		{token.RIGHT_BRACE, "}", 25},
+		{token.SEMICOLON, ";", 26},
+		{token.ILLEGAL, "unexpected characters '..' at line 1, column 28", 28},
+		{token.EOF, "", 30},
+	}
+
+	l := InitializeLexer(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - token wrong\n\texpected=%s %q, got=%s %q", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+
+		if tok.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - column wrong\n\texpected=%d, got=%d", i, tt.expectedColumn, tok.Column)
+		}
+	}
+}
+
+func TestRSource(t *testing.T) {
+	input := "f <- function(x = 1e-3, n = 10L) { # a comment\n  x ^ 2\n}\nlet -> .y; f(a,\n  b)[[1]]\nx <<- 0x1F"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+		expectedNewline bool
+	}{
+		{token.IDENTIFIER, "f", 1, 1, false},
+		{token.ASSIGN, "<-", 1, 3, false},
+		{token.FUNCTION, "function", 1, 6, false},
+		{token.LEFT_PARENTHESIS, "(", 1, 14, false},
+		{token.IDENTIFIER, "x", 1, 15, false},
+		{token.EQUAL, "=", 1, 17, false},
+		{token.DOUBLE, "1e-3", 1, 19, false},
+		{token.COMMA, ",", 1, 23, false},
+		{token.IDENTIFIER, "n", 1, 25, false},
+		{token.EQUAL, "=", 1, 27, false},
+		{token.INT, "10", 1, 29, false},
+		{token.RIGHT_PARENTHESIS, ")", 1, 32, false},
+		{token.LEFT_BRACE, "{", 1, 34, false},
+		{token.IDENTIFIER, "x", 2, 3, true},
+		{token.ILLEGAL, "`^` is not supported at line 2, column 5", 2, 5, false},
+		{token.INT, "2", 2, 7, false},
+		{token.RIGHT_BRACE, "}", 3, 1, true},
+		{token.IDENTIFIER, "let", 4, 1, true},
+		{token.RIGHT_ASSIGN, "->", 4, 5, false},
+		{token.IDENTIFIER, ".y", 4, 8, false},
+		{token.SEMICOLON, ";", 4, 10, false},
+		{token.IDENTIFIER, "f", 4, 12, false},
+		{token.LEFT_PARENTHESIS, "(", 4, 13, false},
+		{token.IDENTIFIER, "a", 4, 14, false},
+		{token.COMMA, ",", 4, 15, false},
+		{token.IDENTIFIER, "b", 5, 3, false},
+		{token.RIGHT_PARENTHESIS, ")", 5, 4, false},
+		{token.LEFT_BRACKET, "[", 5, 5, false},
+		{token.LEFT_BRACKET, "[", 5, 6, false},
+		{token.INT, "1", 5, 7, false},
+		{token.RIGHT_BRACKET, "]", 5, 8, false},
+		{token.RIGHT_BRACKET, "]", 5, 9, false},
+		{token.IDENTIFIER, "x", 6, 1, true},
+		{token.ILLEGAL, "`<<-` is not supported at line 6, column 3", 6, 3, false},
+		{token.INT, "0x1F", 6, 7, false},
+		{token.EOF, "", 6, 11, false},
+	}
+
+	l := InitializeRLexer(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - token wrong\n\texpected=%s %q, got=%s %q", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn || tok.Newline != tt.expectedNewline {
+			t.Fatalf("tests[%d] - position wrong\n\texpected=%d:%d %t, got=%d:%d %t", i, tt.expectedLine, tt.expectedColumn, tt.expectedNewline, tok.Line, tok.Column, tok.Newline)
+		}
+	}
+}
+
+func TestRightAssign(t *testing.T) {
+	input := "1 -> x; y - >z = 2"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.INT, "1"},
+		{token.RIGHT_ASSIGN, "->"},
+		{token.IDENTIFIER, "x"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "y"},
+		{token.MINUS, "-"},
+		{token.GREATER_THAN, ">"},
+		{token.IDENTIFIER, "z"},
+		{token.EQUAL, "="},
+		{token.INT, "2"},
+		{token.EOF, ""},
+	}
+
+	l := InitializeLexer(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - token wrong\n\texpected=%s %q, got=%s %q", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
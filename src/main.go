@@ -1,20 +1,57 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"os/user"
 
+	"./object"
 	"./repl"
 )
 
+var sandbox = flag.String("sandbox", "", "restricts file access to the given directory")
+var readOnly = flag.Bool("readonly", false, "forbids writing files, even inside the sandbox")
+var emit = flag.Bool("emit", false, "writes the R source of the file given instead of running it")
+
 func main() {
+	flag.Parse()
+
 	user, err := user.Current()
 
 	if nil != err {
 		panic(err)
 	}
 
+	if "" != *sandbox {
+		object.Files = object.SandboxedFiles{
+			Root:     *sandbox,
+			ReadOnly: *readOnly,
+		}
+	} else if *readOnly {
+		object.Files = object.SandboxedFiles{
+			Root:     "/",
+			ReadOnly: true,
+		}
+	}
+
+	if 0 < flag.NArg() {
+		run := repl.Run
+
+		if *emit {
+			run = func(path string) error {
+				return repl.Emit(path, os.Stdout)
+			}
+		}
+
+		if err := run(flag.Arg(0)); nil != err {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	fmt.Printf("Hello %s! This is TypeR programming language!\n", user.Username)
 	fmt.Printf("Fell free to type in commands\n")
 
@@ -0,0 +1,94 @@
+package module
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"../ast"
+	"../lexer"
+	"../object"
+	"../parser"
+)
+
+// Resolve : the absolute path of the module imported as path by the module at from, relative paths being taken
+// from the directory of the importer; the main program, whose from is "", imports from the working directory
+func Resolve(from string, path string) (string, error) {
+	if !filepath.IsAbs(path) && "" != from {
+		path = filepath.Join(filepath.Dir(from), path)
+	}
+
+	return filepath.Abs(path)
+}
+
+// Cycle : the error telling that a module ends up importing itself; chain holds the paths of the modules being
+// loaded, from the one importing path back to the first one
+func Cycle(path string, chain []string) error {
+	names := []string{filepath.Base(path)}
+
+	for _, importer := range chain {
+		names = append([]string{filepath.Base(importer)}, names...)
+
+		if importer == path {
+			break
+		}
+	}
+
+	return fmt.Errorf("import cycle: %s", strings.Join(names, " -> "))
+}
+
+// IsDeclarationFile : `.d.tr` files only declare functions written in R, much like the `.d.ts` files of TypeScript
+func IsDeclarationFile(path string) bool {
+	return strings.HasSuffix(path, ".d.tr")
+}
+
+// IsRFile : `.R` files hold R source, read as far as TypeR has a counterpart for it; every binding made at their top
+// level can be imported, as R has no exports
+func IsRFile(path string) bool {
+	return ".R" == filepath.Ext(path) || ".r" == filepath.Ext(path)
+}
+
+// Load : the program in the file, read as the file policy allows; a declaration file may hold nothing but
+// declarations
+func Load(path string) (*ast.Program, error) {
+	if err := object.Files.Allow(path, false); nil != err {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(path)
+
+	if nil != err {
+		return nil, fmt.Errorf("could not load module: %s", err)
+	}
+
+	p := parser.InitializeParser(lexer.InitializeLexer(string(content)))
+
+	if IsRFile(path) {
+		p = parser.InitializeRParser(lexer.InitializeRLexer(string(content)))
+	}
+
+	program := p.ParseProgram()
+
+	if 0 != len(p.Errors()) {
+		return nil, fmt.Errorf("%s: %s", filepath.Base(path), strings.Join(p.Errors(), "; "))
+	}
+
+	if IsRFile(path) {
+		for _, statement := range program.Statements {
+			if let, ok := statement.(*ast.LetStatement); ok {
+				let.Exported = true
+			}
+		}
+	}
+
+	if IsDeclarationFile(path) {
+		for _, statement := range program.Statements {
+			if _, ok := statement.(*ast.DeclareStatement); !ok {
+				return nil, fmt.Errorf("%s: only declarations can be in a declaration file, got %s", filepath.Base(path), statement)
+			}
+		}
+	}
+
+	return program, nil
+}
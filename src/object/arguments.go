@@ -0,0 +1,203 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+)
+
+// describeArgument : how an argument shows up in the errors, as R prints it
+func describeArgument(names []string, arguments []Object, index int) string {
+	value := "..."
+
+	if index < len(arguments) && nil != arguments[index] {
+		value = arguments[index].Inspect()
+	}
+
+	if index < len(names) && "" != names[index] {
+		return fmt.Sprintf("%s = %s", names[index], value)
+	}
+
+	return value
+}
+
+// MatchArguments : tells, for every parameter, the position of the argument bound to it, -1 when it was left out,
+// and the positions of those bound to `...`, if the parameters have it; just like R, names are matched exactly first,
+// then by unique prefixes -- only of the parameters before `...` -- and the unnamed arguments fill the remaining
+// parameters before `...` in order, anything left going to `...`. names may be nil when every argument is given by
+// position
+func MatchArguments(parameters []string, names []string, arguments []Object) ([]int, []int, *Error) {
+	bound := make([]int, len(parameters))
+	used := make([]bool, len(arguments))
+	dots := []int{}
+	positional := len(parameters)
+
+	for index, parameter := range parameters {
+		bound[index] = -1
+
+		if "..." == parameter && positional == len(parameters) {
+			positional = index
+		}
+	}
+
+	variadic := positional != len(parameters)
+
+	for index, name := range names {
+		if "" == name {
+			continue
+		}
+
+		for parameter, parameterName := range parameters {
+			if parameterName != name || "..." == name {
+				continue
+			}
+
+			if -1 != bound[parameter] {
+				return nil, nil, newError("formal argument \"%s\" matched by multiple actual arguments", name)
+			}
+
+			bound[parameter] = index
+			used[index] = true
+
+			break
+		}
+	}
+
+	for index, name := range names {
+		if "" == name || used[index] {
+			continue
+		}
+
+		candidate := -1
+
+		for parameter, parameterName := range parameters[:positional] {
+			if -1 != bound[parameter] || !strings.HasPrefix(parameterName, name) {
+				continue
+			}
+
+			if -1 != candidate {
+				return nil, nil, newError("argument %s matches multiple formal arguments", describeArgument(names, arguments, index))
+			}
+
+			candidate = parameter
+		}
+
+		if -1 != candidate {
+			bound[candidate] = index
+			used[index] = true
+		} else if !variadic {
+			return nil, nil, newError("unused argument (%s)", describeArgument(names, arguments, index))
+		}
+	}
+
+	parameter := 0
+
+	for index := range arguments {
+		if used[index] {
+			continue
+		}
+
+		if index < len(names) && "" != names[index] {
+			dots = append(dots, index)
+
+			continue
+		}
+
+		for parameter < positional && -1 != bound[parameter] {
+			parameter++
+		}
+
+		if parameter < positional {
+			bound[parameter] = index
+
+			continue
+		}
+
+		if !variadic {
+			return nil, nil, newError("unused argument (%s)", describeArgument(names, arguments, index))
+		}
+
+		dots = append(dots, index)
+	}
+
+	return bound, dots, nil
+}
+
+// variadicPosition : where `...` is among the parameters, -1 when it is not
+func variadicPosition(parameters []string) int {
+	for index, parameter := range parameters {
+		if "..." == parameter {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// MissingArgument : the error R raises when a parameter without a default value is left out
+func MissingArgument(parameter string) *Error {
+	return newError("argument \"%s\" is missing, with no default", parameter)
+}
+
+// RecursiveDefault : the error R raises when a default value uses a parameter whose default value is not worked out
+func RecursiveDefault() *Error {
+	return newError("promise already under evaluation: recursive default argument reference or earlier problems?")
+}
+
+// Arguments : the arguments of a call to the builtin in the order it takes them; a builtin that names its parameters
+// can be called with named arguments, those left out before the last one given being NULL, and one taking `...`
+// gets those bound to it as a LIST
+func (b *Builtin) Arguments(names []string, arguments []Object) ([]Object, *Error) {
+	dotsAt := variadicPosition(b.Parameters)
+
+	if nil == names && -1 == dotsAt {
+		return arguments, nil
+	}
+
+	if nil == b.Parameters {
+		for index, name := range names {
+			if "" != name {
+				return nil, newError("unused argument (%s)", describeArgument(names, arguments, index))
+			}
+		}
+
+		return arguments, nil
+	}
+
+	bound, dots, err := MatchArguments(b.Parameters, names, arguments)
+
+	if nil != err {
+		return nil, err
+	}
+
+	last := len(bound) - 1
+
+	for 0 <= last && -1 == bound[last] && last != dotsAt {
+		last--
+	}
+
+	ordered := make([]Object, last+1)
+
+	for parameter := range ordered {
+		switch {
+		case parameter == dotsAt:
+			ordered[parameter] = PackDots(dots, names, arguments)
+		case -1 == bound[parameter]:
+			ordered[parameter] = &Null{}
+		default:
+			ordered[parameter] = arguments[bound[parameter]]
+		}
+	}
+
+	return ordered, nil
+}
+
+// given : whether the optional parameter at index was given, a named argument leaving those before it NULL
+func given(parameters []Object, index int) bool {
+	if index >= len(parameters) {
+		return false
+	}
+
+	_, null := parameters[index].(*Null)
+
+	return !null
+}
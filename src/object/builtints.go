@@ -1,6 +1,10 @@
 package object
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
 
 // newError :
 func newError(format string, variables ...interface{}) *Error {
@@ -17,7 +21,7 @@ var Builtins = []struct {
 	{
 		"puts",
 		&Builtin{
-			Fn: func(parameters ...Object) Object {
+			Fn: func(context CallContext, parameters ...Object) Object {
 				for _, parameter := range parameters {
 					fmt.Println(parameter.Inspect())
 				}
@@ -29,7 +33,7 @@ var Builtins = []struct {
 	{
 		"len",
 		&Builtin{
-			Fn: func(parameters ...Object) Object {
+			Fn: func(context CallContext, parameters ...Object) Object {
 				if 1 != len(parameters) {
 					return newError("wrong number of parameters, got=%d, want=1", len(parameters))
 				}
@@ -40,21 +44,27 @@ var Builtins = []struct {
 						Value: int64(len(parameter.Elements)),
 					}
 
+				case *List:
+					return &Integer{
+						Value: int64(len(parameter.Elements)),
+					}
+
 				case *String:
 					return &Integer{
-						Value: int64(len(parameter.Value)),
+						Value: int64(utf8.RuneCountInString(parameter.Value)),
 					}
 
 				default:
 					return newError("parameters to `len` not supported, got=%s", parameters[0].Type())
 				}
 			},
+			Signature: "(x: any): integer",
 		},
 	},
 	{
 		"head",
 		&Builtin{
-			Fn: func(parameters ...Object) Object {
+			Fn: func(context CallContext, parameters ...Object) Object {
 				if 1 != len(parameters) {
 					return newError("wrong number of parameters, got=%d, want=1", len(parameters))
 				}
@@ -71,12 +81,13 @@ var Builtins = []struct {
 
 				return nil
 			},
+			Signature: "<T>(xs: T[]): T",
 		},
 	},
 	{
 		"tail",
 		&Builtin{
-			Fn: func(parameters ...Object) Object {
+			Fn: func(context CallContext, parameters ...Object) Object {
 				if 1 != len(parameters) {
 					return newError("wrong number of parameters, got=%d, want=1", len(parameters))
 				}
@@ -100,12 +111,13 @@ var Builtins = []struct {
 
 				return nil
 			},
+			Signature: "<T>(xs: T[]): T[]",
 		},
 	},
 	{
 		"last",
 		&Builtin{
-			Fn: func(parameters ...Object) Object {
+			Fn: func(context CallContext, parameters ...Object) Object {
 				if 1 != len(parameters) {
 					return newError("wrong number of parameters, got=%d, want=1", len(parameters))
 				}
@@ -123,12 +135,13 @@ var Builtins = []struct {
 
 				return nil
 			},
+			Signature: "<T>(xs: T[]): T",
 		},
 	},
 	{
 		"push",
 		&Builtin{
-			Fn: func(parameters ...Object) Object {
+			Fn: func(context CallContext, parameters ...Object) Object {
 				if 2 != len(parameters) {
 					return newError("wrong number of parameters, got=%d, want=2", len(parameters))
 				}
@@ -150,6 +163,696 @@ var Builtins = []struct {
 					Elements: newElements,
 				}
 			},
+			Signature: "<T>(xs: T[], x: T): T[]",
+		},
+	},
+	{
+		"hash",
+		&Builtin{
+			Fn: func(context CallContext, parameters ...Object) Object {
+				if 0 != len(parameters)%2 {
+					return newError("wrong number of parameters, got=%d, want an even number", len(parameters))
+				}
+
+				hash := InitializeHash()
+
+				for index := 0; index < len(parameters); index += 2 {
+					if _, ok := parameters[index].(Hashable); !ok {
+						return newError("unusable as hash key: %s", parameters[index].Type())
+					}
+
+					hash.Set(parameters[index], parameters[index+1])
+				}
+
+				return hash
+			},
+		},
+	},
+	{
+		"keys",
+		&Builtin{
+			Fn: func(context CallContext, parameters ...Object) Object {
+				if 1 != len(parameters) {
+					return newError("wrong number of parameters, got=%d, want=1", len(parameters))
+				}
+
+				if HASH_OBJECT != parameters[0].Type() {
+					return newError("parameter to `keys` must be HASH, got %s", parameters[0].Type())
+				}
+
+				hash := parameters[0].(*Hash)
+				elements := make([]Object, len(hash.Order))
+
+				for index, key := range hash.Order {
+					elements[index] = hash.Pairs[key].Key
+				}
+
+				return &Array{
+					Elements: elements,
+				}
+			},
+		},
+	},
+	{
+		"values",
+		&Builtin{
+			Fn: func(context CallContext, parameters ...Object) Object {
+				if 1 != len(parameters) {
+					return newError("wrong number of parameters, got=%d, want=1", len(parameters))
+				}
+
+				if HASH_OBJECT != parameters[0].Type() {
+					return newError("parameter to `values` must be HASH, got %s", parameters[0].Type())
+				}
+
+				hash := parameters[0].(*Hash)
+				elements := make([]Object, len(hash.Order))
+
+				for index, key := range hash.Order {
+					elements[index] = hash.Pairs[key].Value
+				}
+
+				return &Array{
+					Elements: elements,
+				}
+			},
+		},
+	},
+	{
+		"has_key",
+		&Builtin{
+			Fn: func(context CallContext, parameters ...Object) Object {
+				if 2 != len(parameters) {
+					return newError("wrong number of parameters, got=%d, want=2", len(parameters))
+				}
+
+				if HASH_OBJECT != parameters[0].Type() {
+					return newError("parameter to `has_key` must be HASH, got %s", parameters[0].Type())
+				}
+
+				key, ok := parameters[1].(Hashable)
+
+				if !ok {
+					return newError("unusable as hash key: %s", parameters[1].Type())
+				}
+
+				_, ok = parameters[0].(*Hash).Get(key)
+
+				return &Boolean{
+					Value: ok,
+				}
+			},
+		},
+	},
+	{
+		"assoc",
+		&Builtin{
+			Fn: func(context CallContext, parameters ...Object) Object {
+				if 3 != len(parameters) {
+					return newError("wrong number of parameters, got=%d, want=3", len(parameters))
+				}
+
+				if HASH_OBJECT != parameters[0].Type() {
+					return newError("parameter to `assoc` must be HASH, got %s", parameters[0].Type())
+				}
+
+				if _, ok := parameters[1].(Hashable); !ok {
+					return newError("unusable as hash key: %s", parameters[1].Type())
+				}
+
+				hash := parameters[0].(*Hash).Copy()
+				hash.Set(parameters[1], parameters[2])
+
+				return hash
+			},
+		},
+	},
+	{
+		"data.frame",
+		&Builtin{
+			Fn: dataFrameBuiltin,
+		},
+	},
+	{
+		"nrow",
+		&Builtin{
+			Fn:         nrowBuiltin,
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"ncol",
+		&Builtin{
+			Fn:         ncolBuiltin,
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"names",
+		&Builtin{
+			Fn:         namesBuiltin,
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"filter",
+		&Builtin{
+			Fn: filterBuiltin,
+		},
+	},
+	{
+		"select",
+		&Builtin{
+			Fn: selectBuiltin,
+		},
+	},
+	{
+		"mutate",
+		&Builtin{
+			Fn: mutateBuiltin,
+		},
+	},
+	{
+		"arrange",
+		&Builtin{
+			Fn: arrangeBuiltin,
+		},
+	},
+	{
+		"summarise",
+		&Builtin{
+			Fn: summariseBuiltin,
+		},
+	},
+	{
+		"read.csv",
+		&Builtin{
+			Fn:         readCSVBuiltin,
+			Parameters: []string{"file", "header", "sep"},
+		},
+	},
+	{
+		"write.csv",
+		&Builtin{
+			Fn:         writeCSVBuiltin,
+			Parameters: []string{"x", "file"},
+		},
+	},
+	{
+		"fromJSON",
+		&Builtin{
+			Fn:         fromJSONBuiltin,
+			Parameters: []string{"txt"},
+		},
+	},
+	{
+		"toJSON",
+		&Builtin{
+			Fn:         toJSONBuiltin,
+			Parameters: []string{"x", "pretty"},
+		},
+	},
+	{
+		"lapply",
+		&Builtin{
+			Fn:         lapplyBuiltin,
+			Parameters: []string{"X", "FUN", "..."},
+			Signature:  "(X: any, FUN: function, ...): any",
+		},
+	},
+	{
+		"sapply",
+		&Builtin{
+			Fn:         sapplyBuiltin,
+			Parameters: []string{"X", "FUN", "..."},
+			Signature:  "(X: any, FUN: function, ...): any",
+		},
+	},
+	{
+		"vapply",
+		&Builtin{
+			Fn:         vapplyBuiltin,
+			Parameters: []string{"X", "FUN", "FUN.VALUE", "..."},
+			Signature:  "(X: any, FUN: function, FUN.VALUE: any, ...): any",
+		},
+	},
+	{
+		"Map",
+		&Builtin{
+			Fn:        mapFunctionBuiltin,
+			Signature: "(f: function, ...): any",
+		},
+	},
+	{
+		"Filter",
+		&Builtin{
+			Fn:         filterFunctionBuiltin,
+			Parameters: []string{"f", "x"},
+			Signature:  "<T>(f: function, x: T[]): T[]",
+		},
+	},
+	{
+		"Reduce",
+		&Builtin{
+			Fn:         reduceBuiltin,
+			Parameters: []string{"f", "x", "init", "accumulate"},
+			Signature:  "(f: function, x: any, init: any = NULL, accumulate: logical = FALSE): any",
+		},
+	},
+	{
+		"Position",
+		&Builtin{
+			Fn:         positionBuiltin,
+			Parameters: []string{"f", "x", "right"},
+			Signature:  "(f: function, x: any, right: logical = FALSE): integer",
+		},
+	},
+	{
+		"Find",
+		&Builtin{
+			Fn:         findBuiltin,
+			Parameters: []string{"f", "x", "right"},
+			Signature:  "<T>(f: function, x: T[], right: logical = FALSE): T",
+		},
+	},
+	{
+		"paste",
+		&Builtin{
+			Fn:         pasteBuiltin,
+			Parameters: []string{"...", "sep", "collapse"},
+		},
+	},
+	{
+		"paste0",
+		&Builtin{
+			Fn:         paste0Builtin,
+			Parameters: []string{"...", "collapse"},
+		},
+	},
+	{
+		"sprintf",
+		&Builtin{
+			Fn: sprintfBuiltin,
+		},
+	},
+	{
+		"format",
+		&Builtin{
+			Fn:         formatBuiltin,
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"nchar",
+		&Builtin{
+			Fn:         ncharBuiltin,
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"substr",
+		&Builtin{
+			Fn:         substrBuiltin,
+			Parameters: []string{"x", "start", "stop"},
+		},
+	},
+	{
+		"strsplit",
+		&Builtin{
+			Fn:         strsplitBuiltin,
+			Parameters: []string{"x", "split", "fixed", "perl"},
+		},
+	},
+	{
+		"toupper",
+		&Builtin{
+			Fn:         toupperBuiltin,
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"tolower",
+		&Builtin{
+			Fn:         tolowerBuiltin,
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"trimws",
+		&Builtin{
+			Fn:         trimwsBuiltin,
+			Parameters: []string{"x", "which"},
+		},
+	},
+	{
+		"startsWith",
+		&Builtin{
+			Fn:         startsWithBuiltin,
+			Parameters: []string{"x", "prefix"},
+		},
+	},
+	{
+		"endsWith",
+		&Builtin{
+			Fn:         endsWithBuiltin,
+			Parameters: []string{"x", "suffix"},
+		},
+	},
+	{
+		"grepl",
+		&Builtin{
+			Fn:         greplBuiltin,
+			Parameters: []string{"pattern", "x", "ignore.case", "perl", "fixed"},
+		},
+	},
+	{
+		"grep",
+		&Builtin{
+			Fn:         grepBuiltin,
+			Parameters: []string{"pattern", "x", "ignore.case", "perl", "value", "fixed"},
+		},
+	},
+	{
+		"sub",
+		&Builtin{
+			Fn:         subBuiltin,
+			Parameters: []string{"pattern", "replacement", "x", "ignore.case", "perl", "fixed"},
+		},
+	},
+	{
+		"gsub",
+		&Builtin{
+			Fn:         gsubBuiltin,
+			Parameters: []string{"pattern", "replacement", "x", "ignore.case", "perl", "fixed"},
+		},
+	},
+	{
+		"regexpr",
+		&Builtin{
+			Fn:         regexprBuiltin,
+			Parameters: []string{"pattern", "text", "ignore.case", "perl", "fixed"},
+		},
+	},
+	{
+		"regmatches",
+		&Builtin{
+			Fn:         regmatchesBuiltin,
+			Parameters: []string{"x", "m"},
+		},
+	},
+	{
+		"abs",
+		&Builtin{
+			Fn:         absBuiltin,
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"sqrt",
+		&Builtin{
+			Fn:         mathFunction("sqrt", math.Sqrt),
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"exp",
+		&Builtin{
+			Fn:         mathFunction("exp", math.Exp),
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"log",
+		&Builtin{
+			Fn:         logBuiltin,
+			Parameters: []string{"x", "base"},
+		},
+	},
+	{
+		"round",
+		&Builtin{
+			Fn:         roundBuiltin,
+			Parameters: []string{"x", "digits"},
+		},
+	},
+	{
+		"floor",
+		&Builtin{
+			Fn:         mathFunction("floor", math.Floor),
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"ceiling",
+		&Builtin{
+			Fn:         mathFunction("ceiling", math.Ceil),
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"min",
+		&Builtin{
+			Fn:         minBuiltin,
+			Parameters: []string{"...", "na.rm"},
+		},
+	},
+	{
+		"max",
+		&Builtin{
+			Fn:         maxBuiltin,
+			Parameters: []string{"...", "na.rm"},
+		},
+	},
+	{
+		"sum",
+		&Builtin{
+			Fn:         sumBuiltin,
+			Parameters: []string{"...", "na.rm"},
+		},
+	},
+	{
+		"prod",
+		&Builtin{
+			Fn:         prodBuiltin,
+			Parameters: []string{"...", "na.rm"},
+		},
+	},
+	{
+		"mean",
+		&Builtin{
+			Fn:         meanBuiltin,
+			Parameters: []string{"x", "na.rm"},
+		},
+	},
+	{
+		"median",
+		&Builtin{
+			Fn:         medianBuiltin,
+			Parameters: []string{"x", "na.rm"},
+		},
+	},
+	{
+		"var",
+		&Builtin{
+			Fn:         varBuiltin,
+			Parameters: []string{"x", "na.rm"},
+		},
+	},
+	{
+		"sd",
+		&Builtin{
+			Fn:         sdBuiltin,
+			Parameters: []string{"x", "na.rm"},
+		},
+	},
+	{
+		"quantile",
+		&Builtin{
+			Fn:         quantileBuiltin,
+			Parameters: []string{"x", "probs", "na.rm"},
+		},
+	},
+	{
+		"cumsum",
+		&Builtin{
+			Fn:         cumsumBuiltin,
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"cor",
+		&Builtin{
+			Fn:         corBuiltin,
+			Parameters: []string{"x", "y", "use"},
+		},
+	},
+	{
+		"range",
+		&Builtin{
+			Fn:         rangeBuiltin,
+			Parameters: []string{"...", "na.rm"},
+		},
+	},
+	{
+		"set.seed",
+		&Builtin{
+			Fn:         setSeedBuiltin,
+			Parameters: []string{"seed"},
+		},
+	},
+	{
+		"runif",
+		&Builtin{
+			Fn:         runifBuiltin,
+			Parameters: []string{"n", "min", "max"},
+		},
+	},
+	{
+		"rnorm",
+		&Builtin{
+			Fn:         rnormBuiltin,
+			Parameters: []string{"n", "mean", "sd"},
+		},
+	},
+	{
+		"sample",
+		&Builtin{
+			Fn:         sampleBuiltin,
+			Parameters: []string{"x", "size", "replace"},
+			Signature:  "<T>(x: T[], size: numeric = NULL, replace: logical = FALSE): T[]",
+		},
+	},
+	{
+		"rbinom",
+		&Builtin{
+			Fn:         rbinomBuiltin,
+			Parameters: []string{"n", "size", "prob"},
+		},
+	},
+	{
+		"matrix",
+		&Builtin{
+			Fn:         matrixBuiltin,
+			Parameters: []string{"data", "nrow", "ncol", "byrow"},
+		},
+	},
+	{
+		"dim",
+		&Builtin{
+			Fn:         dimBuiltin,
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"t",
+		&Builtin{
+			Fn:         tBuiltin,
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"diag",
+		&Builtin{
+			Fn:         diagBuiltin,
+			Parameters: []string{"x"},
+		},
+	},
+	{
+		"solve",
+		&Builtin{
+			Fn:         solveBuiltin,
+			Parameters: []string{"a", "b"},
+		},
+	},
+	{
+		"apply",
+		&Builtin{
+			Fn:         applyBuiltin,
+			Parameters: []string{"X", "MARGIN", "FUN"},
+		},
+	},
+	{
+		"list",
+		&Builtin{
+			Fn:         listBuiltin,
+			Parameters: []string{"..."},
+		},
+	},
+	{
+		"do.call",
+		&Builtin{
+			Fn:         doCallBuiltin,
+			Parameters: []string{"what", "args"},
+		},
+	},
+	{
+		"stop",
+		&Builtin{
+			Fn: signalBuiltin("stop", "error"),
+		},
+	},
+	{
+		"warning",
+		&Builtin{
+			Fn: signalBuiltin("warning", "warning"),
+		},
+	},
+	{
+		"message",
+		&Builtin{
+			Fn: signalBuiltin("message", "message"),
+		},
+	},
+	{
+		"conditionMessage",
+		&Builtin{
+			Fn:         conditionMessageBuiltin,
+			Parameters: []string{"c"},
+		},
+	},
+	{
+		"Some",
+		&Builtin{
+			Fn: constructorBuiltin("Option", "Some", "value"),
+		},
+	},
+	{
+		"None",
+		&Builtin{
+			Fn: constructorBuiltin("Option", "None"),
+		},
+	},
+	{
+		"Ok",
+		&Builtin{
+			Fn: constructorBuiltin("Result", "Ok", "value"),
+		},
+	},
+	{
+		"Err",
+		&Builtin{
+			Fn: constructorBuiltin("Result", "Err", "error"),
+		},
+	},
+	{
+		"map",
+		&Builtin{
+			Fn:         mapBuiltin,
+			Parameters: []string{"o", "f"},
+		},
+	},
+	{
+		"and_then",
+		&Builtin{
+			Fn:         andThenBuiltin,
+			Parameters: []string{"o", "f"},
+		},
+	},
+	{
+		"unwrap_or",
+		&Builtin{
+			Fn:         unwrapOrBuiltin,
+			Parameters: []string{"o", "default"},
 		},
 	},
 }
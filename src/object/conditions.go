@@ -0,0 +1,223 @@
+package object
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"../ast"
+)
+
+// Condition : what stop, warning and message signal, just like R's conditions; Class is either "error", "warning"
+// or "message", and Position tells where the builtin failing was called, for the errors raised some other way
+type Condition struct {
+	Class    string
+	Message  string
+	Position Position
+}
+
+// Type :
+func (c *Condition) Type() ObjectType {
+	return CONDITION_OBJECT
+}
+
+// Inspect : as R prints them, `<simpleError: boom>`
+func (c *Condition) Inspect() string {
+	return fmt.Sprintf("<simple%s: %s>", strings.Title(c.Class), c.Message)
+}
+
+// ConditionOf : the condition the error was signalled with, an error condition holding its message when it was
+// raised some other way, as by a builtin
+func ConditionOf(err *Error) *Condition {
+	if nil != err.Condition {
+		return err.Condition
+	}
+
+	return &Condition{
+		Class:    "error",
+		Message:  err.Message,
+		Position: err.Position,
+	}
+}
+
+// Messages : where the warnings and messages no handler takes are written
+var Messages io.Writer = os.Stderr
+
+// Report : writes the warning or message no handler took, as R does
+func (c *Condition) Report() {
+	if "warning" == c.Class {
+		fmt.Fprintf(Messages, "Warning message:\n%s\n", c.Message)
+
+		return
+	}
+
+	fmt.Fprintln(Messages, c.Message)
+}
+
+// Handler : the functions established by tryCatch or withCallingHandlers, by the class of the conditions they
+// handle; calling handlers are called where the condition is signalled, the others once the evaluation unwound to
+// the tryCatch
+type Handler struct {
+	Functions map[string]Object
+	Calling   bool
+}
+
+// For : the function handling the condition, nil when the handler does not take it; `condition` takes any
+func (h *Handler) For(condition *Condition) Object {
+	if function, ok := h.Functions[condition.Class]; ok {
+		return function
+	}
+
+	return h.Functions["condition"]
+}
+
+// Handlers : the handlers established, the innermost last, shared by a whole run
+type Handlers struct {
+	stack []*Handler
+}
+
+// Push :
+func (h *Handlers) Push(handler *Handler) {
+	h.stack = append(h.stack, handler)
+}
+
+// Pop :
+func (h *Handlers) Pop() {
+	h.stack = h.stack[:len(h.stack)-1]
+}
+
+// Len :
+func (h *Handlers) Len() int {
+	return len(h.stack)
+}
+
+// At :
+func (h *Handlers) At(index int) *Handler {
+	return h.stack[index]
+}
+
+// Disable : leaves out the handlers from index on, giving them back so they can be restored, as R does while a
+// calling handler runs, so it does not handle what it signals itself
+func (h *Handlers) Disable(index int) []*Handler {
+	disabled := append([]*Handler{}, h.stack[index:]...)
+	h.stack = h.stack[:index]
+
+	return disabled
+}
+
+// Restore :
+func (h *Handlers) Restore(disabled []*Handler) {
+	h.stack = append(h.stack, disabled...)
+}
+
+// handledClasses : the classes of the conditions tryCatch and withCallingHandlers take handlers for
+var handledClasses = map[string]bool{
+	"error":     true,
+	"warning":   true,
+	"message":   true,
+	"condition": true,
+}
+
+// HandlerArguments : the expression given to tryCatch or withCallingHandlers, the handlers by class and, for
+// tryCatch, the expression given as finally
+func HandlerArguments(function string, arguments []ast.Expression) (ast.Expression, map[string]ast.Expression, ast.Expression, error) {
+	var expression, finally ast.Expression
+	handlers := map[string]ast.Expression{}
+
+	for _, argument := range arguments {
+		named, ok := argument.(*ast.NamedArgument)
+
+		switch {
+		case !ok && nil == expression:
+			expression = argument
+		case !ok:
+			return nil, nil, nil, fmt.Errorf("unused argument (%s) to %s", argument, function)
+		case "expr" == named.Name && nil == expression:
+			expression = named.Value
+		case "finally" == named.Name && "tryCatch" == function:
+			finally = named.Value
+		case handledClasses[named.Name]:
+			handlers[named.Name] = named.Value
+		default:
+			return nil, nil, nil, fmt.Errorf("%s takes handlers for conditions of class error, warning, message or condition, got %s", function, named.Name)
+		}
+	}
+
+	return expression, handlers, finally, nil
+}
+
+// IsHandling : whether the call establishes handlers, the function being tryCatch or withCallingHandlers
+func IsHandling(name string) bool {
+	return "tryCatch" == name || "withCallingHandlers" == name
+}
+
+// conditionText : the message of a condition, the arguments being pasted together as R does
+func conditionText(function string, parameters []Object) (string, *Error) {
+	pasted := pasteStrings(function, "", parameters)
+
+	switch pasted := pasted.(type) {
+	case *Error:
+		return "", pasted
+	case *Array:
+		values := make([]string, len(pasted.Elements))
+
+		for index, element := range pasted.Elements {
+			values[index] = element.(*String).Value
+		}
+
+		return strings.Join(values, ""), nil
+	default:
+		return pasted.(*String).Value, nil
+	}
+}
+
+// signalBuiltin : `stop(...)`, `warning(...)` and `message(...)`, signalling a condition of the class, a condition
+// given alone being signalled again; warning gives back its message
+func signalBuiltin(function string, class string) BuiltinFunction {
+	return func(context CallContext, parameters ...Object) Object {
+		condition, ok := &Condition{}, false
+
+		if 1 == len(parameters) {
+			condition, ok = parameters[0].(*Condition)
+		}
+
+		if !ok {
+			message, err := conditionText(function, parameters)
+
+			if nil != err {
+				return err
+			}
+
+			condition = &Condition{
+				Class:   class,
+				Message: message,
+			}
+		}
+
+		if err := context.Signal(condition); nil != err {
+			return err
+		}
+
+		if "warning" == class {
+			return &String{
+				Value: condition.Message,
+			}
+		}
+
+		return nil
+	}
+}
+
+// conditionMessageBuiltin : `conditionMessage(c)`
+func conditionMessageBuiltin(context CallContext, parameters ...Object) Object {
+	condition, ok := parameters[0].(*Condition)
+
+	if !ok {
+		return newError("argument to `conditionMessage` must be a CONDITION, got %s", parameters[0].Type())
+	}
+
+	return &String{
+		Value: condition.Message,
+	}
+}
@@ -0,0 +1,351 @@
+package object
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// logicals : the spellings R's `type.convert` accepts as logical values
+var logicals = map[string]bool{
+	"TRUE":  true,
+	"True":  true,
+	"true":  true,
+	"T":     true,
+	"FALSE": false,
+	"False": false,
+	"false": false,
+	"F":     false,
+}
+
+// cell : a field of a CSV file, along with whether it was quoted
+type cell struct {
+	text   string
+	quoted bool
+}
+
+// readRecords : the records of a CSV file, fields being quoted with double quotes, which are doubled within them;
+// blank lines are left out
+func readRecords(text string, separator rune) ([][]cell, error) {
+	records, record := [][]cell{}, []cell{}
+	field := strings.Builder{}
+	quoted, quoting, line, start := false, false, 1, 1
+	runes := []rune(text)
+
+	end := func() {
+		record = append(record, cell{
+			text:   field.String(),
+			quoted: quoted,
+		})
+		field.Reset()
+		quoted = false
+	}
+
+	for index := 0; index < len(runes); index++ {
+		r := runes[index]
+
+		switch {
+		case quoting && '"' == r && index+1 < len(runes) && '"' == runes[index+1]:
+			field.WriteRune(r)
+			index++
+		case quoting && '"' == r:
+			quoting = false
+		case quoting:
+			if '\n' == r {
+				line++
+			}
+
+			field.WriteRune(r)
+		case '"' == r && !quoted && 0 == field.Len():
+			quoted, quoting = true, true
+		case separator == r:
+			end()
+		case '\r' == r && index+1 < len(runes) && '\n' == runes[index+1]:
+		case '\n' == r:
+			if quoted || 0 != field.Len() || 0 != len(record) {
+				end()
+				records, record = append(records, record), []cell{}
+			}
+
+			line++
+			start = line
+		case '"' == r || quoted:
+			return nil, fmt.Errorf("line %d: extraneous or missing \" in field", line)
+		default:
+			field.WriteRune(r)
+		}
+	}
+
+	if quoting {
+		return nil, fmt.Errorf("line %d: extraneous or missing \" in field", start)
+	}
+
+	if quoted || 0 != field.Len() || 0 != len(record) {
+		end()
+		records = append(records, record)
+	}
+
+	for index, record := range records {
+		if len(record) != len(records[0]) {
+			return nil, fmt.Errorf("record %d: wrong number of fields, got %d, want %d", index+1, len(record), len(records[0]))
+		}
+	}
+
+	return records, nil
+}
+
+// isMissing : only unquoted cells are missing values, `NA` ones, and empty ones outside of character columns
+func isMissing(c cell, kind ObjectType) bool {
+	return !c.quoted && ("NA" == c.text || ("" == c.text && STRING_OBJECT != kind))
+}
+
+// inferType : picks the narrowest type able to hold every cell, from integer to character; quoted cells are strings,
+// whatever they hold, as written by write.csv
+func inferType(cells []cell) ObjectType {
+	integer, double, logical := true, true, true
+	present, quoted := false, false
+
+	for _, c := range cells {
+		if isMissing(c, NULL_OBJECT) {
+			continue
+		}
+
+		present, quoted = true, quoted || c.quoted
+
+		if _, err := strconv.ParseInt(c.text, 10, 64); nil != err {
+			integer = false
+		}
+
+		if _, err := strconv.ParseFloat(c.text, 64); nil != err {
+			double = false
+		}
+
+		if _, ok := logicals[c.text]; !ok {
+			logical = false
+		}
+	}
+
+	switch {
+	case quoted:
+		return STRING_OBJECT
+	case !present, logical:
+		return BOOLEAN_OBJECT
+	case integer:
+		return INTEGER_OBJECT
+	case double:
+		return DOUBLE_OBJECT
+	default:
+		return STRING_OBJECT
+	}
+}
+
+// parseCell :
+func parseCell(c cell, kind ObjectType) Object {
+	if isMissing(c, kind) {
+		return NA
+	}
+
+	cell := c.text
+
+	switch kind {
+	case INTEGER_OBJECT:
+		value, _ := strconv.ParseInt(cell, 10, 64)
+
+		return &Integer{
+			Value: value,
+		}
+	case DOUBLE_OBJECT:
+		value, _ := strconv.ParseFloat(cell, 64)
+
+		return &Double{
+			Value: value,
+		}
+	case BOOLEAN_OBJECT:
+		return &Boolean{
+			Value: logicals[cell],
+		}
+	default:
+		return &String{
+			Value: cell,
+		}
+	}
+}
+
+// quote : as R's write.csv writes strings, double quotes being doubled within them
+func quote(text string) string {
+	return `"` + strings.Replace(text, `"`, `""`, -1) + `"`
+}
+
+// formatCell : doubles are written with every digit, so nothing is lost in a round trip, and strings are quoted, so
+// they are read back as strings whatever they hold
+func formatCell(element Object) string {
+	switch element := element.(type) {
+	case *Double:
+		return strconv.FormatFloat(element.Value, 'g', -1, 64)
+	case *String:
+		return quote(element.Value)
+	default:
+		return element.Inspect()
+	}
+}
+
+// readCSVBuiltin : `read.csv(path, header, sep)`, the last two being optional
+func readCSVBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 > len(parameters) || 3 < len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1 to 3", len(parameters))
+	}
+
+	path, ok := parameters[0].(*String)
+
+	if !ok {
+		return newError("path to `read.csv` must be STRING, got %s", parameters[0].Type())
+	}
+
+	header := true
+
+	if given(parameters, 1) {
+		flag, ok := parameters[1].(*Boolean)
+
+		if !ok {
+			return newError("header to `read.csv` must be BOOLEAN, got %s", parameters[1].Type())
+		}
+
+		header = flag.Value
+	}
+
+	separator := ','
+
+	if 2 < len(parameters) {
+		sep, ok := parameters[2].(*String)
+
+		if !ok || 1 != utf8.RuneCountInString(sep.Value) {
+			return newError("sep to `read.csv` must be a single character STRING, got %s", parameters[2].Inspect())
+		}
+
+		separator, _ = utf8.DecodeRuneInString(sep.Value)
+	}
+
+	if err := Files.Allow(path.Value, false); nil != err {
+		return newError("%s", err)
+	}
+
+	content, err := ioutil.ReadFile(path.Value)
+
+	if nil != err {
+		return newError("cannot open file '%s': %s", path.Value, err)
+	}
+
+	records, err := readRecords(string(content), separator)
+
+	if nil != err {
+		return newError("cannot read '%s': %s", path.Value, err)
+	}
+
+	if 0 == len(records) {
+		return &DataFrame{}
+	}
+
+	names := make([]string, len(records[0]))
+
+	for index := range names {
+		names[index] = fmt.Sprintf("V%d", index+1)
+	}
+
+	if header {
+		for index, name := range records[0] {
+			names[index] = name.text
+		}
+
+		records = records[1:]
+	}
+
+	columns := make([]*Array, len(names))
+
+	for index := range columns {
+		cells := make([]cell, len(records))
+
+		for row, record := range records {
+			cells[row] = record[index]
+		}
+
+		kind := inferType(cells)
+		elements := make([]Object, len(cells))
+
+		for row, cell := range cells {
+			elements[row] = parseCell(cell, kind)
+		}
+
+		columns[index] = &Array{
+			Elements: elements,
+		}
+	}
+
+	frame, failure := InitializeDataFrame(names, columns)
+
+	if nil != failure {
+		return failure
+	}
+
+	return frame
+}
+
+// writeCSVBuiltin : `write.csv(frame, path)`, failing when the file can not be closed, as what is written may be lost
+func writeCSVBuiltin(context CallContext, parameters ...Object) (result Object) {
+	if 2 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=2", len(parameters))
+	}
+
+	frame, failure := frameParameter("write.csv", parameters)
+
+	if nil != failure {
+		return failure
+	}
+
+	path, ok := parameters[1].(*String)
+
+	if !ok {
+		return newError("path to `write.csv` must be STRING, got %s", parameters[1].Type())
+	}
+
+	if err := Files.Allow(path.Value, true); nil != err {
+		return newError("%s", err)
+	}
+
+	file, err := os.Create(path.Value)
+
+	if nil != err {
+		return newError("cannot open file '%s': %s", path.Value, err)
+	}
+
+	defer func() {
+		if err := file.Close(); nil != err && nil == result {
+			result = newError("cannot close '%s': %s", path.Value, err)
+		}
+	}()
+
+	lines := strings.Builder{}
+	record := make([]string, len(frame.Columns))
+
+	for index, name := range frame.Names {
+		record[index] = quote(name)
+	}
+
+	lines.WriteString(strings.Join(record, ",") + "\n")
+
+	for row := 0; row < frame.NumberOfRows(); row++ {
+		for index, column := range frame.Columns {
+			record[index] = formatCell(column.Elements[row])
+		}
+
+		lines.WriteString(strings.Join(record, ",") + "\n")
+	}
+
+	if _, err := file.WriteString(lines.String()); nil != err {
+		return newError("cannot write '%s': %s", path.Value, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,731 @@
+package object
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DataFrame : named columns of the same length, every column holding a single type of element
+type DataFrame struct {
+	Names   []string
+	Columns []*Array
+	Types   []ObjectType
+}
+
+// Type :
+func (df *DataFrame) Type() ObjectType {
+	return DATA_FRAME_OBJECT
+}
+
+// Inspect : prints the frame as a table, with the row index on the left
+func (df *DataFrame) Inspect() string {
+	rows := df.NumberOfRows()
+	cells := make([][]string, len(df.Columns)+1)
+	cells[0] = make([]string, rows+1)
+
+	for row := 0; row < rows; row++ {
+		cells[0][row+1] = fmt.Sprintf("%d", row)
+	}
+
+	for index, column := range df.Columns {
+		cells[index+1] = make([]string, rows+1)
+		cells[index+1][0] = df.Names[index]
+
+		for row, element := range column.Elements {
+			cells[index+1][row+1] = element.Inspect()
+		}
+	}
+
+	return formatTable(cells)
+}
+
+// NumberOfRows :
+func (df *DataFrame) NumberOfRows() int {
+	if 0 == len(df.Columns) {
+		return 0
+	}
+
+	return len(df.Columns[0].Elements)
+}
+
+// columnPosition :
+func (df *DataFrame) columnPosition(index Object) (int, *Error) {
+	switch index := index.(type) {
+	case *String:
+		for position, name := range df.Names {
+			if name == index.Value {
+				return position, nil
+			}
+		}
+
+		return -1, nil
+	case *Integer:
+		if index.Value < 0 || index.Value >= int64(len(df.Columns)) {
+			return -1, nil
+		}
+
+		return int(index.Value), nil
+	default:
+		return -1, newError("column index must be INTEGER or STRING, got %s", index.Type())
+	}
+}
+
+// Index : `frame["name"]`, `frame$name` or `frame[position]` return the column itself, NULL when it does not exist
+func (df *DataFrame) Index(index Object) Object {
+	position, err := df.columnPosition(index)
+
+	if nil != err {
+		return err
+	}
+
+	if -1 == position {
+		return nil
+	}
+
+	return df.Columns[position]
+}
+
+// selectRows : NULL selects every row, booleans work as a mask and integers as positions
+func (df *DataFrame) selectRows(rows Object) ([]int, *Error) {
+	length := df.NumberOfRows()
+	selected := []int{}
+
+	switch rows := rows.(type) {
+	case *Null:
+		for row := 0; row < length; row++ {
+			selected = append(selected, row)
+		}
+	case *Integer:
+		if rows.Value < 0 || rows.Value >= int64(length) {
+			return nil, newError("row index out of range: %d", rows.Value)
+		}
+
+		selected = append(selected, int(rows.Value))
+	case *Array:
+		if isMask(rows) {
+			if len(rows.Elements) != length {
+				return nil, newError("logical row index has length %d, want=%d", len(rows.Elements), length)
+			}
+
+			for row, element := range rows.Elements {
+				if keep, ok := element.(*Boolean); ok && keep.Value {
+					selected = append(selected, row)
+				}
+			}
+
+			return selected, nil
+		}
+
+		for _, element := range rows.Elements {
+			selection, err := df.selectRows(element)
+
+			if nil != err {
+				return nil, err
+			}
+
+			selected = append(selected, selection...)
+		}
+	default:
+		return nil, newError("row index must be INTEGER, ARRAY or NULL, got %s", rows.Type())
+	}
+
+	return selected, nil
+}
+
+// selectColumns :
+func (df *DataFrame) selectColumns(columns Object) ([]int, *Error) {
+	selected := []int{}
+
+	switch columns := columns.(type) {
+	case *Null:
+		for column := range df.Columns {
+			selected = append(selected, column)
+		}
+	case *Array:
+		for _, element := range columns.Elements {
+			selection, err := df.selectColumns(element)
+
+			if nil != err {
+				return nil, err
+			}
+
+			selected = append(selected, selection...)
+		}
+	default:
+		position, err := df.columnPosition(columns)
+
+		if nil != err {
+			return nil, err
+		}
+
+		if -1 == position {
+			return nil, newError("undefined column selected: %s", columns.Inspect())
+		}
+
+		selected = append(selected, position)
+	}
+
+	return selected, nil
+}
+
+// subset :
+func (df *DataFrame) subset(rows, columns []int) *DataFrame {
+	frame := &DataFrame{
+		Names:   make([]string, len(columns)),
+		Columns: make([]*Array, len(columns)),
+		Types:   make([]ObjectType, len(columns)),
+	}
+
+	for index, column := range columns {
+		elements := make([]Object, len(rows))
+
+		for position, row := range rows {
+			elements[position] = df.Columns[column].Elements[row]
+		}
+
+		frame.Names[index] = df.Names[column]
+		frame.Types[index] = df.Types[column]
+		frame.Columns[index] = &Array{
+			Elements: elements,
+		}
+	}
+
+	return frame
+}
+
+// Slice : `frame[rows, columns]`, a single column selected by name or position is returned as a vector and a single cell as a scalar
+func (df *DataFrame) Slice(rows, columns Object) Object {
+	selectedRows, err := df.selectRows(rows)
+
+	if nil != err {
+		return err
+	}
+
+	selectedColumns, err := df.selectColumns(columns)
+
+	if nil != err {
+		return err
+	}
+
+	frame := df.subset(selectedRows, selectedColumns)
+
+	switch columns.(type) {
+	case *String, *Integer:
+		if _, ok := rows.(*Integer); ok {
+			return frame.Columns[0].Elements[0]
+		}
+
+		return frame.Columns[0]
+	}
+
+	return frame
+}
+
+// formatTable : lays out the given columns, the first one holding the labels of the rows, each one as wide as its widest cell
+func formatTable(cells [][]string) string {
+	var out bytes.Buffer
+
+	widths := make([]int, len(cells))
+
+	for index, column := range cells {
+		for _, cell := range column {
+			if len(cell) > widths[index] {
+				widths[index] = len(cell)
+			}
+		}
+	}
+
+	rows := len(cells[0]) - 1
+
+	for row := 0; row <= rows; row++ {
+		line := make([]string, len(cells))
+
+		for index, column := range cells {
+			if 0 == index {
+				line[index] = fmt.Sprintf("%-*s", widths[index], column[row])
+			} else {
+				line[index] = fmt.Sprintf("%*s", widths[index], column[row])
+			}
+		}
+
+		out.WriteString(strings.Join(line, " "))
+
+		if row < rows {
+			out.WriteString("\n")
+		}
+	}
+
+	return out.String()
+}
+
+// isMask : missing values are accepted, the rows holding them are just left out
+func isMask(array *Array) bool {
+	booleans := 0
+
+	for _, element := range array.Elements {
+		switch element.Type() {
+		case BOOLEAN_OBJECT:
+			booleans++
+		case NOT_AVAILABLE_OBJECT:
+		default:
+			return false
+		}
+	}
+
+	return 0 < booleans
+}
+
+// columnType : integers mixed with doubles are promoted to doubles, as R does
+func columnType(name string, column *Array) (*Array, ObjectType, *Error) {
+	kind := ObjectType(NULL_OBJECT)
+
+	for _, element := range column.Elements {
+		switch element.Type() {
+		case NOT_AVAILABLE_OBJECT:
+			continue
+		case INTEGER_OBJECT, DOUBLE_OBJECT, BOOLEAN_OBJECT, STRING_OBJECT:
+		default:
+			return nil, kind, newError("column '%s' cannot hold %s", name, element.Type())
+		}
+
+		switch {
+		case NULL_OBJECT == kind || kind == element.Type():
+			kind = element.Type()
+		case INTEGER_OBJECT == kind && DOUBLE_OBJECT == element.Type():
+			kind = DOUBLE_OBJECT
+		case DOUBLE_OBJECT == kind && INTEGER_OBJECT == element.Type():
+		default:
+			return nil, kind, newError("column '%s' mixes %s and %s", name, kind, element.Type())
+		}
+	}
+
+	if NULL_OBJECT == kind && 0 < len(column.Elements) {
+		kind = BOOLEAN_OBJECT
+	}
+
+	if DOUBLE_OBJECT != kind {
+		return column, kind, nil
+	}
+
+	elements := make([]Object, len(column.Elements))
+
+	for index, element := range column.Elements {
+		if integer, ok := element.(*Integer); ok {
+			elements[index] = &Double{
+				Value: float64(integer.Value),
+			}
+		} else {
+			elements[index] = element
+		}
+	}
+
+	return &Array{
+		Elements: elements,
+	}, kind, nil
+}
+
+// InitializeDataFrame :
+func InitializeDataFrame(names []string, columns []*Array) (*DataFrame, *Error) {
+	frame := &DataFrame{
+		Names:   make([]string, len(names)),
+		Columns: make([]*Array, len(columns)),
+		Types:   make([]ObjectType, len(columns)),
+	}
+
+	for index, name := range names {
+		for _, previous := range names[:index] {
+			if previous == name {
+				return nil, newError("duplicated column name: %s", name)
+			}
+		}
+
+		if len(columns[index].Elements) != len(columns[0].Elements) {
+			return nil, newError("column '%s' has %d rows, want=%d", name, len(columns[index].Elements), len(columns[0].Elements))
+		}
+
+		column, kind, err := columnType(name, columns[index])
+
+		if nil != err {
+			return nil, err
+		}
+
+		frame.Names[index] = name
+		frame.Columns[index] = column
+		frame.Types[index] = kind
+	}
+
+	return frame, nil
+}
+
+// namedColumns : reads the `"name", value, ...` pairs given to `data.frame`, `mutate` and `summarise`
+func namedColumns(function string, parameters []Object) ([]string, []Object, *Error) {
+	if 0 != len(parameters)%2 {
+		return nil, nil, newError("`%s` expects name and value pairs, got %d parameters", function, len(parameters))
+	}
+
+	names := []string{}
+	values := []Object{}
+
+	for index := 0; index < len(parameters); index += 2 {
+		name, ok := parameters[index].(*String)
+
+		if !ok {
+			return nil, nil, newError("column name to `%s` must be STRING, got %s", function, parameters[index].Type())
+		}
+
+		names = append(names, name.Value)
+		values = append(values, parameters[index+1])
+	}
+
+	return names, values, nil
+}
+
+// recycle : scalars are repeated to fill every row
+func recycle(value Object, length int) *Array {
+	if array, ok := value.(*Array); ok {
+		return array
+	}
+
+	elements := make([]Object, length)
+
+	for index := range elements {
+		elements[index] = value
+	}
+
+	return &Array{
+		Elements: elements,
+	}
+}
+
+// frameParameter :
+func frameParameter(function string, parameters []Object) (*DataFrame, *Error) {
+	if 0 == len(parameters) {
+		return nil, newError("wrong number of parameters, got=0, want at least 1")
+	}
+
+	frame, ok := parameters[0].(*DataFrame)
+
+	if !ok {
+		return nil, newError("parameter to `%s` must be DATA_FRAME, got %s", function, parameters[0].Type())
+	}
+
+	return frame, nil
+}
+
+// dataFrameBuiltin : `data.frame("x", [1, 2], "y", ["a", "b"])`
+func dataFrameBuiltin(context CallContext, parameters ...Object) Object {
+	names, values, err := namedColumns("data.frame", parameters)
+
+	if nil != err {
+		return err
+	}
+
+	// single values make a row, unless a column is a vector
+	length, vector := 1, false
+
+	for _, value := range values {
+		if array, ok := value.(*Array); ok && (!vector || len(array.Elements) > length) {
+			length, vector = len(array.Elements), true
+		}
+	}
+
+	if 0 == len(values) {
+		return &DataFrame{}
+	}
+
+	columns := make([]*Array, len(values))
+
+	for index, value := range values {
+		columns[index] = recycle(value, length)
+	}
+
+	frame, err := InitializeDataFrame(names, columns)
+
+	if nil != err {
+		return err
+	}
+
+	return frame
+}
+
+// nrowBuiltin :
+func nrowBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1", len(parameters))
+	}
+
+	frame, err := frameParameter("nrow", parameters)
+
+	if nil != err {
+		return err
+	}
+
+	return &Integer{
+		Value: int64(frame.NumberOfRows()),
+	}
+}
+
+// ncolBuiltin :
+func ncolBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1", len(parameters))
+	}
+
+	frame, err := frameParameter("ncol", parameters)
+
+	if nil != err {
+		return err
+	}
+
+	return &Integer{
+		Value: int64(len(frame.Columns)),
+	}
+}
+
+// namesBuiltin :
+func namesBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1", len(parameters))
+	}
+
+	frame, err := frameParameter("names", parameters)
+
+	if nil != err {
+		return err
+	}
+
+	elements := make([]Object, len(frame.Names))
+
+	for index, name := range frame.Names {
+		elements[index] = &String{
+			Value: name,
+		}
+	}
+
+	return &Array{
+		Elements: elements,
+	}
+}
+
+// filterBuiltin : `filter(frame, frame$x > 1)` keeps the rows where the mask is TRUE
+func filterBuiltin(context CallContext, parameters ...Object) Object {
+	if 2 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=2", len(parameters))
+	}
+
+	frame, err := frameParameter("filter", parameters)
+
+	if nil != err {
+		return err
+	}
+
+	mask, ok := parameters[1].(*Array)
+
+	if !ok || (!isMask(mask) && 0 != len(mask.Elements)) {
+		return newError("condition to `filter` must be an ARRAY of BOOLEAN, got %s", parameters[1].Type())
+	}
+
+	return frame.Slice(mask, &Null{})
+}
+
+// selectBuiltin : `select(frame, "x", "y")`
+func selectBuiltin(context CallContext, parameters ...Object) Object {
+	frame, err := frameParameter("select", parameters)
+
+	if nil != err {
+		return err
+	}
+
+	columns, err := frame.selectColumns(&Array{
+		Elements: parameters[1:],
+	})
+
+	if nil != err {
+		return err
+	}
+
+	rows, _ := frame.selectRows(&Null{})
+
+	return frame.subset(rows, columns)
+}
+
+// mutateBuiltin : `mutate(frame, "z", frame$x * 2)` adds or replaces columns, returning a new frame
+func mutateBuiltin(context CallContext, parameters ...Object) Object {
+	frame, err := frameParameter("mutate", parameters)
+
+	if nil != err {
+		return err
+	}
+
+	names, values, err := namedColumns("mutate", parameters[1:])
+
+	if nil != err {
+		return err
+	}
+
+	newNames := append([]string{}, frame.Names...)
+	newColumns := append([]*Array{}, frame.Columns...)
+
+	for index, name := range names {
+		column := recycle(values[index], frame.NumberOfRows())
+		position, _ := frame.columnPosition(&String{
+			Value: name,
+		})
+
+		if -1 == position {
+			newNames = append(newNames, name)
+			newColumns = append(newColumns, column)
+		} else {
+			newColumns[position] = column
+		}
+	}
+
+	mutated, err := InitializeDataFrame(newNames, newColumns)
+
+	if nil != err {
+		return err
+	}
+
+	return mutated
+}
+
+// compareElements : missing values are always placed last
+func compareElements(left, right Object) int {
+	switch {
+	case NA == left && NA == right:
+		return 0
+	case NA == left:
+		return 1
+	case NA == right:
+		return -1
+	}
+
+	switch left := left.(type) {
+	case *String:
+		return strings.Compare(left.Value, right.(*String).Value)
+	case *Boolean:
+		if left.Value == right.(*Boolean).Value {
+			return 0
+		} else if left.Value {
+			return 1
+		}
+
+		return -1
+	}
+
+	leftValue := numericValue(left)
+	rightValue := numericValue(right)
+
+	switch {
+	case leftValue < rightValue:
+		return -1
+	case leftValue > rightValue:
+		return 1
+	}
+
+	return 0
+}
+
+// numericValue :
+func numericValue(obj Object) float64 {
+	switch obj := obj.(type) {
+	case *Integer:
+		return float64(obj.Value)
+	case *Double:
+		return obj.Value
+	}
+
+	return 0
+}
+
+// arrangeBuiltin : `arrange(frame, "x", "y")` sorts the rows by the given columns, a trailing TRUE sorts them decreasingly
+func arrangeBuiltin(context CallContext, parameters ...Object) Object {
+	frame, err := frameParameter("arrange", parameters)
+
+	if nil != err {
+		return err
+	}
+
+	keys := parameters[1:]
+	decreasing := false
+
+	if 0 < len(keys) {
+		if flag, ok := keys[len(keys)-1].(*Boolean); ok {
+			decreasing = flag.Value
+			keys = keys[:len(keys)-1]
+		}
+	}
+
+	columns, err := frame.selectColumns(&Array{
+		Elements: keys,
+	})
+
+	if nil != err {
+		return err
+	}
+
+	rows, _ := frame.selectRows(&Null{})
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, column := range columns {
+			left := frame.Columns[column].Elements[rows[i]]
+			right := frame.Columns[column].Elements[rows[j]]
+			comparison := compareElements(left, right)
+
+			if 0 != comparison && (NA == left || NA == right) {
+				return comparison < 0
+			}
+
+			if 0 != comparison {
+				return (comparison < 0) != decreasing
+			}
+		}
+
+		return false
+	})
+
+	all, _ := frame.selectColumns(&Null{})
+
+	return frame.subset(rows, all)
+}
+
+// summariseBuiltin : `summarise(frame, "rows", nrow(frame))` builds a single row frame out of scalars
+func summariseBuiltin(context CallContext, parameters ...Object) Object {
+	_, err := frameParameter("summarise", parameters)
+
+	if nil != err {
+		return err
+	}
+
+	names, values, err := namedColumns("summarise", parameters[1:])
+
+	if nil != err {
+		return err
+	}
+
+	columns := make([]*Array, len(values))
+
+	for index, value := range values {
+		if array, ok := value.(*Array); ok {
+			if 1 != len(array.Elements) {
+				return newError("summary '%s' must be a single value, got %d", names[index], len(array.Elements))
+			}
+
+			columns[index] = array
+
+			continue
+		}
+
+		columns[index] = recycle(value, 1)
+	}
+
+	summary, err := InitializeDataFrame(names, columns)
+
+	if nil != err {
+		return err
+	}
+
+	return summary
+}
@@ -0,0 +1,12 @@
+package object
+
+// Declared : what a function only declared, being written in R, is bound to; its arguments are matched just like
+// those of any builtin, but calling it fails the way R does when the function is nowhere to be found
+func Declared(name string, parameters []string) *Builtin {
+	return &Builtin{
+		Fn: func(context CallContext, arguments ...Object) Object {
+			return newError("could not find function \"%s\"", name)
+		},
+		Parameters: parameters,
+	}
+}
@@ -11,6 +11,16 @@ type Environment struct {
 	store       map[string]Field
 	outer       *Environment
 	memoization map[string]Object
+	// only the outermost environment holds a generator, shared by the whole run
+	random *Random
+	// as is the stack of the handlers established by tryCatch and withCallingHandlers
+	handlers *Handlers
+	// every module has an outermost environment of its own, path being the file it was loaded from -- "" for the
+	// main program -- and importer the environment that imported it first; modules are shared by all of them
+	path     string
+	importer *Environment
+	exports  map[string]bool
+	modules  map[string]*Environment
 }
 
 // InitializeEnvironment :
@@ -25,6 +35,92 @@ func InitializeEnvironment() *Environment {
 	}
 }
 
+// outermost :
+func (e *Environment) outermost() *Environment {
+	if nil != e.outer {
+		return e.outer.outermost()
+	}
+
+	return e
+}
+
+// IsOutermost : whether the environment is the global one of a module, where bindings can be exported
+func (e *Environment) IsOutermost() bool {
+	return nil == e.outer
+}
+
+// Module : the outermost environment of the module at path, imported from e, sharing the generator of the run
+func (e *Environment) Module(path string) *Environment {
+	importer := e.outermost()
+
+	if nil == importer.modules {
+		importer.modules = map[string]*Environment{}
+	}
+
+	module := InitializeEnvironment()
+	module.path = path
+	module.importer = importer
+	module.random = importer.Random()
+	module.handlers = importer.Handlers()
+	module.modules = importer.modules
+
+	return module
+}
+
+// Path : the file the module the environment belongs to was loaded from, "" for the main program
+func (e *Environment) Path() string {
+	return e.outermost().path
+}
+
+// Importers : the paths of the modules being loaded, from the one e belongs to back to the main program
+func (e *Environment) Importers() []string {
+	paths := []string{}
+
+	for module := e.outermost(); nil != module && "" != module.path; module = module.importer {
+		paths = append(paths, module.path)
+	}
+
+	return paths
+}
+
+// Loaded : the module at path, when it was already loaded by the run
+func (e *Environment) Loaded(path string) (*Environment, bool) {
+	module, ok := e.outermost().modules[path]
+
+	return module, ok
+}
+
+// Keep : keeps the module loaded, so importing it again only binds names
+func (e *Environment) Keep(module *Environment) {
+	importer := e.outermost()
+
+	if nil == importer.modules {
+		importer.modules = map[string]*Environment{}
+	}
+
+	importer.modules[module.path] = module
+}
+
+// Export :
+func (e *Environment) Export(name string) {
+	if nil == e.exports {
+		e.exports = map[string]bool{}
+	}
+
+	e.exports[name] = true
+}
+
+// Exported : the value bound to name, when the module exports it
+func (e *Environment) Exported(name string) (Object, bool) {
+	field, ok := e.store[name]
+
+	if !ok || !e.exports[name] {
+		return nil, false
+	}
+
+	return field.Value, true
+}
+
 // InitializeEnclosedEnvironment :
 func InitializeEnclosedEnvironment(outer *Environment) *Environment {
 	environment := InitializeEnvironment()
@@ -56,6 +152,32 @@ func (e *Environment) Set(name string, constant bool, value Object) Object {
 	return value
 }
 
+// Random : the random number generator of the outermost environment, created when first needed
+func (e *Environment) Random() *Random {
+	if nil != e.outer {
+		return e.outer.Random()
+	}
+
+	if nil == e.random {
+		e.random = InitializeRandom()
+	}
+
+	return e.random
+}
+
+// Handlers : the handlers established in the run, held by the outermost environment
+func (e *Environment) Handlers() *Handlers {
+	if nil != e.outer {
+		return e.outer.Handlers()
+	}
+
+	if nil == e.handlers {
+		e.handlers = &Handlers{}
+	}
+
+	return e.handlers
+}
+
 // GetMemoization :
 func (e *Environment) GetMemoization(name string) (Object, bool) {
 	obj, ok := e.memoization[name]
@@ -0,0 +1,108 @@
+package object
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FilePolicy : decides which paths the I/O builtins may touch, so embedders such as the REPL can sandbox the language
+type FilePolicy interface {
+	Allow(path string, write bool) error
+}
+
+// AllowAllFiles : the default policy, every path can be read and written
+type AllowAllFiles struct{}
+
+// DenyAllFiles : no I/O at all
+type DenyAllFiles struct{}
+
+// SandboxedFiles : only paths inside Root are allowed, writing to them just when ReadOnly is false
+type SandboxedFiles struct {
+	Root     string
+	ReadOnly bool
+}
+
+// Files : the policy being currently used by the builtins
+var Files FilePolicy = AllowAllFiles{}
+
+// Allow :
+func (AllowAllFiles) Allow(path string, write bool) error {
+	return nil
+}
+
+// Allow :
+func (DenyAllFiles) Allow(path string, write bool) error {
+	return fmt.Errorf("access to '%s' denied: file access is disabled", path)
+}
+
+// resolve : the absolute path every symbolic link leads to, so none of them can lead out of the sandbox; what does not
+// exist yet, such as a file about to be written, is taken from the directory it would be in
+func resolve(path string) (string, error) {
+	absolute, err := filepath.Abs(path)
+
+	if nil != err {
+		return "", err
+	}
+
+	resolved, err := filepath.EvalSymlinks(absolute)
+
+	if nil == err {
+		return resolved, nil
+	}
+
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	// a link to something missing would be followed once the file is written
+	if target, err := os.Readlink(absolute); nil == err {
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(absolute), target)
+		}
+
+		return resolve(target)
+	}
+
+	parent := filepath.Dir(absolute)
+
+	if parent == absolute {
+		return absolute, nil
+	}
+
+	parent, err = resolve(parent)
+
+	if nil != err {
+		return "", err
+	}
+
+	return filepath.Join(parent, filepath.Base(absolute)), nil
+}
+
+// Allow :
+func (s SandboxedFiles) Allow(path string, write bool) error {
+	root, err := resolve(s.Root)
+
+	if nil != err {
+		return err
+	}
+
+	absolute, err := resolve(path)
+
+	if nil != err {
+		return err
+	}
+
+	relative, err := filepath.Rel(root, absolute)
+
+	if nil != err || ".." == relative || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("access to '%s' denied: outside of '%s'", path, s.Root)
+	}
+
+	if write && s.ReadOnly {
+		return fmt.Errorf("access to '%s' denied: sandbox is read only", path)
+	}
+
+	return nil
+}
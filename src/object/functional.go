@@ -0,0 +1,524 @@
+package object
+
+import (
+	"fmt"
+)
+
+// sequence : the elements a higher-order builtin walks through, keys being kept so hashes and data frames keep their names
+type sequence struct {
+	keys     []Object
+	elements []Object
+}
+
+// sequenceParameter : vectors, hashes and data frames -- which are walked column by column -- can be iterated
+func sequenceParameter(function string, parameter Object) (*sequence, *Error) {
+	switch parameter := parameter.(type) {
+	case *Array:
+		return &sequence{
+			elements: parameter.Elements,
+		}, nil
+	case *List:
+		return &sequence{
+			elements: parameter.Elements,
+		}, nil
+	case *Hash:
+		seq := &sequence{}
+
+		for _, key := range parameter.Order {
+			pair := parameter.Pairs[key]
+
+			seq.keys = append(seq.keys, pair.Key)
+			seq.elements = append(seq.elements, pair.Value)
+		}
+
+		return seq, nil
+	case *DataFrame:
+		seq := &sequence{}
+
+		for index, name := range parameter.Names {
+			seq.keys = append(seq.keys, &String{
+				Value: name,
+			})
+			seq.elements = append(seq.elements, parameter.Columns[index])
+		}
+
+		return seq, nil
+	case *Null:
+		return &sequence{}, nil
+	default:
+		return nil, newError("parameter to `%s` must be ARRAY, HASH or DATA_FRAME, got %s", function, parameter.Type())
+	}
+}
+
+// rebuild : keyed sequences give back a hash, anything else a vector
+func (s *sequence) rebuild(elements []Object, keep []bool) Object {
+	if nil == s.keys {
+		if nil == keep {
+			return &Array{
+				Elements: elements,
+			}
+		}
+
+		kept := []Object{}
+
+		for index, element := range elements {
+			if keep[index] {
+				kept = append(kept, element)
+			}
+		}
+
+		return &Array{
+			Elements: kept,
+		}
+	}
+
+	hash := InitializeHash()
+
+	for index, element := range elements {
+		if nil == keep || keep[index] {
+			hash.Set(s.keys[index], element)
+		}
+	}
+
+	return hash
+}
+
+// isFunctionObject :
+func isFunctionObject(obj Object) bool {
+	switch obj.(type) {
+	case *Function, *Closure, *Builtin:
+		return true
+	}
+
+	return false
+}
+
+// functionParameter :
+func functionParameter(function string, parameter Object) *Error {
+	if !isFunctionObject(parameter) {
+		return newError("function given to `%s` must be a function, got %s", function, parameter.Type())
+	}
+
+	return nil
+}
+
+// predicate : just like in R, anything but TRUE -- even NA -- is taken as false
+func predicate(name string, context CallContext, function Object, element Object) (bool, *Error) {
+	result, err := context.Call(function, element)
+
+	if nil != err {
+		return false, err
+	}
+
+	switch result := result.(type) {
+	case *Boolean:
+		return result.Value, nil
+	case *NotAvailable:
+		return false, nil
+	default:
+		return false, newError("predicate given to `%s` must return BOOLEAN, got %s", name, result.Type())
+	}
+}
+
+// mapSequence : calls the function over every element, followed by the extra arguments, stopping at the first error
+func mapSequence(context CallContext, function Object, elements []Object, extra *List) ([]Object, *Error) {
+	results := make([]Object, len(elements))
+
+	for index, element := range elements {
+		names, arguments := extra.Spread([]string{""}, []Object{element})
+		result, err := context.CallNamed(function, names, arguments)
+
+		if nil != err {
+			return nil, err
+		}
+
+		results[index] = result
+	}
+
+	return results, nil
+}
+
+// lapplyBuiltin : `lapply(x, f, ...)`, the extra arguments being given to f after every element
+func lapplyBuiltin(context CallContext, parameters ...Object) Object {
+	if !given(parameters, 0) {
+		return MissingArgument("X")
+	}
+
+	if !given(parameters, 1) {
+		return MissingArgument("FUN")
+	}
+
+	seq, err := sequenceParameter("lapply", parameters[0])
+
+	if nil != err {
+		return err
+	}
+
+	if err := functionParameter("lapply", parameters[1]); nil != err {
+		return err
+	}
+
+	results, err := mapSequence(context, parameters[1], seq.elements, parameters[2].(*List))
+
+	if nil != err {
+		return err
+	}
+
+	return seq.rebuild(results, nil)
+}
+
+// sapplyBuiltin : `sapply(x, f, ...)`, just like `lapply` but results of length one are unwrapped
+func sapplyBuiltin(context CallContext, parameters ...Object) Object {
+	if !given(parameters, 0) {
+		return MissingArgument("X")
+	}
+
+	if !given(parameters, 1) {
+		return MissingArgument("FUN")
+	}
+
+	seq, err := sequenceParameter("sapply", parameters[0])
+
+	if nil != err {
+		return err
+	}
+
+	if err := functionParameter("sapply", parameters[1]); nil != err {
+		return err
+	}
+
+	results, err := mapSequence(context, parameters[1], seq.elements, parameters[2].(*List))
+
+	if nil != err {
+		return err
+	}
+
+	for index, result := range results {
+		if array, ok := result.(*Array); ok && 1 == len(array.Elements) {
+			results[index] = array.Elements[0]
+		}
+	}
+
+	return seq.rebuild(results, nil)
+}
+
+// vapplyBuiltin : `vapply(x, f, value, ...)`, where every result must have the same type -- and length -- of value
+func vapplyBuiltin(context CallContext, parameters ...Object) Object {
+	if !given(parameters, 0) {
+		return MissingArgument("X")
+	}
+
+	if !given(parameters, 1) {
+		return MissingArgument("FUN")
+	}
+
+	if !given(parameters, 2) {
+		return MissingArgument("FUN.VALUE")
+	}
+
+	seq, err := sequenceParameter("vapply", parameters[0])
+
+	if nil != err {
+		return err
+	}
+
+	if err := functionParameter("vapply", parameters[1]); nil != err {
+		return err
+	}
+
+	template := parameters[2]
+	results, err := mapSequence(context, parameters[1], seq.elements, parameters[3].(*List))
+
+	if nil != err {
+		return err
+	}
+
+	for index, result := range results {
+		checked, ok := conform(result, template)
+
+		if !ok {
+			return newError("values must be %s, but FUN(X[[%d]]) result is %s", describe(template), index, describe(result))
+		}
+
+		results[index] = checked
+	}
+
+	return seq.rebuild(results, nil)
+}
+
+// describe : used by `vapply` errors
+func describe(value Object) string {
+	if array, ok := value.(*Array); ok {
+		if 0 == len(array.Elements) {
+			return "length 0"
+		}
+
+		return fmt.Sprintf("length %d %s", len(array.Elements), array.Elements[0].Type())
+	}
+
+	return string(value.Type())
+}
+
+// conform : checks a result against the template, integers being promoted when doubles are expected
+func conform(value, template Object) (Object, bool) {
+	if array, ok := template.(*Array); ok {
+		result, ok := value.(*Array)
+
+		if !ok || len(result.Elements) != len(array.Elements) {
+			return nil, false
+		}
+
+		if 0 == len(array.Elements) {
+			return result, true
+		}
+
+		elements := make([]Object, len(result.Elements))
+
+		for index, element := range result.Elements {
+			if elements[index], ok = conform(element, array.Elements[0]); !ok {
+				return nil, false
+			}
+		}
+
+		return &Array{
+			Elements: elements,
+		}, true
+	}
+
+	switch {
+	case NA == value || value.Type() == template.Type():
+		return value, true
+	case INTEGER_OBJECT == value.Type() && DOUBLE_OBJECT == template.Type():
+		return &Double{
+			Value: float64(value.(*Integer).Value),
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// mapFunctionBuiltin : `Map(f, ...)`, calling f with the i-th element of every vector, which are recycled to the longest one
+func mapFunctionBuiltin(context CallContext, parameters ...Object) Object {
+	if 2 > len(parameters) {
+		return newError("wrong number of parameters, got=%d, want at least 2", len(parameters))
+	}
+
+	if err := functionParameter("Map", parameters[0]); nil != err {
+		return err
+	}
+
+	vectors := make([][]Object, len(parameters)-1)
+	length := 0
+
+	for index, parameter := range parameters[1:] {
+		if array, ok := parameter.(*Array); ok {
+			vectors[index] = array.Elements
+		} else {
+			vectors[index] = []Object{parameter}
+		}
+
+		if len(vectors[index]) > length {
+			length = len(vectors[index])
+		}
+	}
+
+	for _, vector := range vectors {
+		if 0 == len(vector) {
+			length = 0
+		}
+	}
+
+	results := make([]Object, length)
+
+	for position := range results {
+		arguments := make([]Object, len(vectors))
+
+		for index, vector := range vectors {
+			arguments[index] = vector[position%len(vector)]
+		}
+
+		result, err := context.Call(parameters[0], arguments...)
+
+		if nil != err {
+			return err
+		}
+
+		results[position] = result
+	}
+
+	return &Array{
+		Elements: results,
+	}
+}
+
+// filterFunctionBuiltin : `Filter(f, x)`
+func filterFunctionBuiltin(context CallContext, parameters ...Object) Object {
+	if 2 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=2", len(parameters))
+	}
+
+	if err := functionParameter("Filter", parameters[0]); nil != err {
+		return err
+	}
+
+	seq, err := sequenceParameter("Filter", parameters[1])
+
+	if nil != err {
+		return err
+	}
+
+	keep := make([]bool, len(seq.elements))
+
+	for index, element := range seq.elements {
+		if keep[index], err = predicate("Filter", context, parameters[0], element); nil != err {
+			return err
+		}
+	}
+
+	return seq.rebuild(seq.elements, keep)
+}
+
+// reduceBuiltin : `Reduce(f, x, init, accumulate)`, the last two being optional and a NULL init meaning no init at all
+func reduceBuiltin(context CallContext, parameters ...Object) Object {
+	if 2 > len(parameters) || 4 < len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=2 to 4", len(parameters))
+	}
+
+	if err := functionParameter("Reduce", parameters[0]); nil != err {
+		return err
+	}
+
+	seq, err := sequenceParameter("Reduce", parameters[1])
+
+	if nil != err {
+		return err
+	}
+
+	elements := seq.elements
+
+	if 2 < len(parameters) && NULL_OBJECT != parameters[2].Type() {
+		elements = append([]Object{parameters[2]}, elements...)
+	}
+
+	accumulate := false
+
+	if 3 < len(parameters) {
+		flag, ok := parameters[3].(*Boolean)
+
+		if !ok {
+			return newError("accumulate to `Reduce` must be BOOLEAN, got %s", parameters[3].Type())
+		}
+
+		accumulate = flag.Value
+	}
+
+	if 0 == len(elements) {
+		if accumulate {
+			return &Array{
+				Elements: []Object{},
+			}
+		}
+
+		return nil
+	}
+
+	current := elements[0]
+	steps := []Object{current}
+
+	for _, element := range elements[1:] {
+		if current, err = context.Call(parameters[0], current, element); nil != err {
+			return err
+		}
+
+		steps = append(steps, current)
+	}
+
+	if accumulate {
+		return &Array{
+			Elements: steps,
+		}
+	}
+
+	return current
+}
+
+// search : the position of the first element -- or last, when right is set -- satisfying the predicate
+func search(function string, context CallContext, parameters []Object) (*sequence, int, *Error) {
+	if 2 > len(parameters) || 3 < len(parameters) {
+		return nil, -1, newError("wrong number of parameters, got=%d, want=2 or 3", len(parameters))
+	}
+
+	if err := functionParameter(function, parameters[0]); nil != err {
+		return nil, -1, err
+	}
+
+	seq, err := sequenceParameter(function, parameters[1])
+
+	if nil != err {
+		return nil, -1, err
+	}
+
+	right := false
+
+	if 2 < len(parameters) {
+		flag, ok := parameters[2].(*Boolean)
+
+		if !ok {
+			return nil, -1, newError("right to `%s` must be BOOLEAN, got %s", function, parameters[2].Type())
+		}
+
+		right = flag.Value
+	}
+
+	for step := range seq.elements {
+		index := step
+
+		if right {
+			index = len(seq.elements) - 1 - step
+		}
+
+		found, err := predicate(function, context, parameters[0], seq.elements[index])
+
+		if nil != err {
+			return nil, -1, err
+		}
+
+		if found {
+			return seq, index, nil
+		}
+	}
+
+	return seq, -1, nil
+}
+
+// positionBuiltin : `Position(f, x, right)`, giving NA when nothing is found
+func positionBuiltin(context CallContext, parameters ...Object) Object {
+	_, index, err := search("Position", context, parameters)
+
+	if nil != err {
+		return err
+	}
+
+	if -1 == index {
+		return NA
+	}
+
+	return &Integer{
+		Value: int64(index),
+	}
+}
+
+// findBuiltin : `Find(f, x, right)`, giving NULL when nothing is found
+func findBuiltin(context CallContext, parameters ...Object) Object {
+	seq, index, err := search("Find", context, parameters)
+
+	if nil != err {
+		return err
+	}
+
+	if -1 == index {
+		return nil
+	}
+
+	return seq.elements[index]
+}
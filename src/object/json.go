@@ -0,0 +1,417 @@
+package object
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// decodeJSONValue : walks the tokens by hand, since decoding into a Go map would lose the order of the keys
+func decodeJSONValue(decoder *json.Decoder) (Object, error) {
+	tok, err := decoder.Token()
+
+	if nil != err {
+		return nil, err
+	}
+
+	switch tok := tok.(type) {
+	case json.Delim:
+		if '{' == tok {
+			return decodeJSONObject(decoder)
+		}
+
+		return decodeJSONArray(decoder)
+	case json.Number:
+		if integer, err := strconv.ParseInt(tok.String(), 10, 64); nil == err {
+			return &Integer{
+				Value: integer,
+			}, nil
+		}
+
+		double, err := tok.Float64()
+
+		if nil != err {
+			return nil, err
+		}
+
+		return &Double{
+			Value: double,
+		}, nil
+	case string:
+		return &String{
+			Value: tok,
+		}, nil
+	case bool:
+		return &Boolean{
+			Value: tok,
+		}, nil
+	default:
+		return &Null{}, nil
+	}
+}
+
+// decodeJSONObject : objects become hashes keyed by strings
+func decodeJSONObject(decoder *json.Decoder) (Object, error) {
+	hash := InitializeHash()
+
+	for decoder.More() {
+		key, err := decoder.Token()
+
+		if nil != err {
+			return nil, err
+		}
+
+		value, err := decodeJSONValue(decoder)
+
+		if nil != err {
+			return nil, err
+		}
+
+		hash.Set(&String{
+			Value: key.(string),
+		}, value)
+	}
+
+	_, err := decoder.Token()
+
+	return hash, err
+}
+
+// decodeJSONArray :
+func decodeJSONArray(decoder *json.Decoder) (Object, error) {
+	elements := []Object{}
+
+	for decoder.More() {
+		element, err := decodeJSONValue(decoder)
+
+		if nil != err {
+			return nil, err
+		}
+
+		elements = append(elements, element)
+	}
+
+	_, err := decoder.Token()
+
+	if nil != err {
+		return nil, err
+	}
+
+	if frame := simplifyDataFrame(elements); nil != frame {
+		return frame, nil
+	}
+
+	return simplifyVector(elements), nil
+}
+
+// isScalar :
+func isScalar(obj Object) bool {
+	switch obj.Type() {
+	case INTEGER_OBJECT, DOUBLE_OBJECT, BOOLEAN_OBJECT, STRING_OBJECT, NULL_OBJECT:
+		return true
+	}
+
+	return false
+}
+
+// simplifyVector : following jsonlite, homogeneous arrays are vectors where null stands for NA, anything else is a list
+func simplifyVector(elements []Object) *Array {
+	kind := ObjectType(NULL_OBJECT)
+
+	for _, element := range elements {
+		switch {
+		case !isScalar(element):
+			return &Array{
+				Elements: elements,
+			}
+		case NULL_OBJECT == element.Type():
+		case NULL_OBJECT == kind || kind == element.Type():
+			kind = element.Type()
+		case INTEGER_OBJECT == kind && DOUBLE_OBJECT == element.Type(), DOUBLE_OBJECT == kind && INTEGER_OBJECT == element.Type():
+			kind = DOUBLE_OBJECT
+		default:
+			return &Array{
+				Elements: elements,
+			}
+		}
+	}
+
+	vector := make([]Object, len(elements))
+
+	for index, element := range elements {
+		switch element := element.(type) {
+		case *Null:
+			vector[index] = NA
+		case *Integer:
+			if DOUBLE_OBJECT == kind {
+				vector[index] = &Double{
+					Value: float64(element.Value),
+				}
+			} else {
+				vector[index] = element
+			}
+		default:
+			vector[index] = element
+		}
+	}
+
+	return &Array{
+		Elements: vector,
+	}
+}
+
+// simplifyDataFrame : an array of records holding only scalars is read as a data frame, missing fields being NA
+func simplifyDataFrame(elements []Object) *DataFrame {
+	if 0 == len(elements) {
+		return nil
+	}
+
+	names := []string{}
+	seen := map[string]bool{}
+
+	for _, element := range elements {
+		record, ok := element.(*Hash)
+
+		if !ok {
+			return nil
+		}
+
+		for _, key := range record.Order {
+			pair := record.Pairs[key]
+
+			if !isScalar(pair.Value) {
+				return nil
+			}
+
+			name := pair.Key.(*String).Value
+
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	columns := make([]*Array, len(names))
+
+	for index, name := range names {
+		cells := make([]Object, len(elements))
+
+		for row, element := range elements {
+			value, ok := element.(*Hash).Get(&String{
+				Value: name,
+			})
+
+			if !ok {
+				value = &Null{}
+			}
+
+			cells[row] = value
+		}
+
+		columns[index] = simplifyVector(cells)
+	}
+
+	frame, err := InitializeDataFrame(names, columns)
+
+	if nil != err {
+		return nil
+	}
+
+	return frame
+}
+
+// encodeJSON :
+func encodeJSON(out *bytes.Buffer, value Object) *Error {
+	switch value := value.(type) {
+	case *Null, *NotAvailable:
+		out.WriteString("null")
+	case *Boolean:
+		out.WriteString(strconv.FormatBool(value.Value))
+	case *Integer:
+		out.WriteString(strconv.FormatInt(value.Value, 10))
+	case *Double:
+		if math.IsNaN(value.Value) || math.IsInf(value.Value, 0) {
+			out.WriteString("null")
+
+			break
+		}
+
+		number := strconv.FormatFloat(value.Value, 'g', -1, 64)
+
+		// keeps the value a double when it is read back
+		if !strings.ContainsAny(number, ".e") {
+			number += ".0"
+		}
+
+		out.WriteString(number)
+	case *String:
+		encoded, _ := json.Marshal(value.Value)
+		out.Write(encoded)
+	case *Array:
+		out.WriteString("[")
+
+		for index, element := range value.Elements {
+			if 0 < index {
+				out.WriteString(",")
+			}
+
+			if err := encodeJSON(out, element); nil != err {
+				return err
+			}
+		}
+
+		out.WriteString("]")
+	case *Hash:
+		out.WriteString("{")
+
+		for index, key := range value.Order {
+			pair := value.Pairs[key]
+
+			if 0 < index {
+				out.WriteString(",")
+			}
+
+			name := pair.Key.Inspect()
+			encoded, _ := json.Marshal(name)
+			out.Write(encoded)
+			out.WriteString(":")
+
+			if err := encodeJSON(out, pair.Value); nil != err {
+				return err
+			}
+		}
+
+		out.WriteString("}")
+	case *DataFrame:
+		out.WriteString("[")
+
+		for row := 0; row < value.NumberOfRows(); row++ {
+			if 0 < row {
+				out.WriteString(",")
+			}
+
+			record := InitializeHash()
+
+			// just like jsonlite, missing values are left out of the record
+			for index, column := range value.Columns {
+				if NA != column.Elements[row] {
+					record.Set(&String{
+						Value: value.Names[index],
+					}, column.Elements[row])
+				}
+			}
+
+			encodeJSON(out, record)
+		}
+
+		out.WriteString("]")
+	default:
+		return newError("cannot convert %s to JSON", value.Type())
+	}
+
+	return nil
+}
+
+// jsonText : just like jsonlite, the text may also be the path to a file holding it
+func jsonText(text string) (io.Reader, *Error) {
+	trimmed := strings.TrimSpace(text)
+
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") || nil != Files.Allow(text, false) {
+		return strings.NewReader(text), nil
+	}
+
+	if _, err := os.Stat(text); nil != err {
+		return strings.NewReader(text), nil
+	}
+
+	content, err := ioutil.ReadFile(text)
+
+	if nil != err {
+		return nil, newError("cannot open file '%s': %s", text, err)
+	}
+
+	return bytes.NewReader(content), nil
+}
+
+// fromJSONBuiltin : `fromJSON(text)`, where text is either JSON or a path to a JSON file
+func fromJSONBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1", len(parameters))
+	}
+
+	text, ok := parameters[0].(*String)
+
+	if !ok {
+		return newError("parameter to `fromJSON` must be STRING, got %s", parameters[0].Type())
+	}
+
+	reader, failure := jsonText(text.Value)
+
+	if nil != failure {
+		return failure
+	}
+
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+
+	value, err := decodeJSONValue(decoder)
+
+	if nil == err && decoder.More() {
+		_, err = decoder.Token()
+
+		if nil == err {
+			return newError("invalid JSON: unexpected content after the value")
+		}
+	}
+
+	if nil != err {
+		return newError("invalid JSON: %s", err)
+	}
+
+	return value
+}
+
+// toJSONBuiltin : `toJSON(value, pretty)`, pretty being optional
+func toJSONBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 > len(parameters) || 2 < len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1 or 2", len(parameters))
+	}
+
+	pretty := false
+
+	if 2 == len(parameters) {
+		flag, ok := parameters[1].(*Boolean)
+
+		if !ok {
+			return newError("pretty to `toJSON` must be BOOLEAN, got %s", parameters[1].Type())
+		}
+
+		pretty = flag.Value
+	}
+
+	var out bytes.Buffer
+
+	if err := encodeJSON(&out, parameters[0]); nil != err {
+		return err
+	}
+
+	if pretty {
+		var indented bytes.Buffer
+
+		json.Indent(&indented, out.Bytes(), "", "  ")
+
+		return &String{
+			Value: indented.String(),
+		}
+	}
+
+	return &String{
+		Value: out.String(),
+	}
+}
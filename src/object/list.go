@@ -0,0 +1,180 @@
+package object
+
+import (
+	"strconv"
+	"strings"
+)
+
+// List : what R's `list` makes, any kind of elements, each one possibly named; the extra arguments of a variadic
+// function, its `...`, are held in one
+type List struct {
+	Elements []Object
+	// Names holds the name of every element, "" for those without one; nil when none is named
+	Names []string
+}
+
+// Type :
+func (l *List) Type() ObjectType {
+	return LIST_OBJECT
+}
+
+// Inspect : as the list would be made, `list(a = 1, 2)`
+func (l *List) Inspect() string {
+	elements := make([]string, len(l.Elements))
+
+	for index, element := range l.Elements {
+		elements[index] = element.Inspect()
+
+		if name := l.Name(index); "" != name {
+			elements[index] = name + " = " + elements[index]
+		}
+	}
+
+	return "list(" + strings.Join(elements, ", ") + ")"
+}
+
+// Name : the name of the element at index, "" when it has none
+func (l *List) Name(index int) string {
+	if index >= len(l.Names) {
+		return ""
+	}
+
+	return l.Names[index]
+}
+
+// Index : elements are found by their position or by their name, nil when there is no such element
+func (l *List) Index(index Object) Object {
+	switch index := index.(type) {
+	case *Integer:
+		if 0 <= index.Value && index.Value < int64(len(l.Elements)) {
+			return l.Elements[index.Value]
+		}
+	case *String:
+		for position, name := range l.Names {
+			if name == index.Value {
+				return l.Elements[position]
+			}
+		}
+	default:
+		return newError("index to a LIST must be INTEGER or STRING, got %s", index.Type())
+	}
+
+	return nil
+}
+
+// Dot : `..1`, `..2` and so on, counted from one just like in R
+func (l *List) Dot(position int) (Object, *Error) {
+	if position > len(l.Elements) {
+		return nil, newError("the ... list contains fewer than %d elements", position)
+	}
+
+	return l.Elements[position-1], nil
+}
+
+// DotPosition : `..1`, `..2` and so on stand for the arguments held by `...`
+func DotPosition(name string) (int, bool) {
+	if !strings.HasPrefix(name, "..") {
+		return 0, false
+	}
+
+	position, err := strconv.Atoi(name[2:])
+
+	return position, nil == err && 0 < position
+}
+
+// Spread : the elements as the arguments of a call, along with their names
+func (l *List) Spread(names []string, arguments []Object) ([]string, []Object) {
+	for index, element := range l.Elements {
+		names = append(names, l.Name(index))
+		arguments = append(arguments, element)
+	}
+
+	return names, arguments
+}
+
+// PackDots : the arguments bound to `...`, in the order they were given
+func PackDots(dots []int, names []string, arguments []Object) *List {
+	list := &List{
+		Elements: []Object{},
+	}
+
+	for _, index := range dots {
+		name := ""
+
+		if index < len(names) {
+			name = names[index]
+		}
+
+		if "" != name && nil == list.Names {
+			list.Names = make([]string, len(list.Elements))
+		}
+
+		if nil != list.Names {
+			list.Names = append(list.Names, name)
+		}
+
+		list.Elements = append(list.Elements, arguments[index])
+	}
+
+	return list
+}
+
+// listBuiltin : `list(...)`
+func listBuiltin(context CallContext, parameters ...Object) Object {
+	return parameters[0]
+}
+
+// doCallBuiltin : `do.call(what, args)` calls what -- a function or its name -- with the elements of args as its
+// arguments, the names of a list or a hash naming them
+func doCallBuiltin(context CallContext, parameters ...Object) Object {
+	if 2 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=2", len(parameters))
+	}
+
+	what := parameters[0]
+
+	if name, ok := what.(*String); ok {
+		what = context.Function(name.Value)
+
+		if nil == what || !isFunctionObject(what) {
+			return newError("could not find function \"%s\"", name.Value)
+		}
+	}
+
+	if err := functionParameter("do.call", what); nil != err {
+		return err
+	}
+
+	var names []string
+	var arguments []Object
+
+	switch args := parameters[1].(type) {
+	case *List:
+		names, arguments = args.Spread(nil, nil)
+	case *Array:
+		arguments = args.Elements
+	case *Hash:
+		for _, key := range args.Order {
+			pair := args.Pairs[key]
+			name, ok := pair.Key.(*String)
+
+			if !ok {
+				return newError("names of the args to `do.call` must be STRING, got %s", pair.Key.Type())
+			}
+
+			names = append(names, name.Value)
+			arguments = append(arguments, pair.Value)
+		}
+	case *Null:
+	default:
+		return newError("args to `do.call` must be a LIST, ARRAY or HASH, got %s", args.Type())
+	}
+
+	result, err := context.CallNamed(what, names, arguments)
+
+	if nil != err {
+		return err
+	}
+
+	return result
+}
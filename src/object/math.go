@@ -0,0 +1,1029 @@
+package object
+
+import (
+	"math"
+	"math/big"
+	"sort"
+)
+
+// longDoublePrecision : R accumulates sums in long double, whose mantissa has 64 bits on x86-64; big.Float set to it
+// rounds every step just like R does, so results are the very same doubles
+const longDoublePrecision = 64
+
+// longDouble :
+func longDouble(value float64) *big.Float {
+	return new(big.Float).SetPrec(longDoublePrecision).SetFloat64(value)
+}
+
+// toDouble : rounds a long double back to a double, just like a C cast
+func toDouble(value *big.Float) float64 {
+	result, _ := value.Float64()
+
+	return result
+}
+
+// allFinite : big.Float has no NaN, so sums over NaN or infinite values are left to plain IEEE arithmetic
+func allFinite(values []float64) bool {
+	for _, value := range values {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// longDoubleSum : values must be finite
+func longDoubleSum(values []float64) *big.Float {
+	sum := longDouble(0)
+
+	for _, value := range values {
+		sum.Add(sum, longDouble(value))
+	}
+
+	return sum
+}
+
+// asDouble : BOOLEAN counts as 0 or 1, just like in R
+func asDouble(obj Object) float64 {
+	switch obj := obj.(type) {
+	case *Integer:
+		return float64(obj.Value)
+	case *Double:
+		return obj.Value
+	case *Boolean:
+		if obj.Value {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// numericElements : the elements of a numeric vector, or of a single number, telling whether it was a vector; a matrix
+// counts as the vector it is stored as
+func numericElements(function string, x Object) ([]Object, bool, *Error) {
+	elements, vector := []Object{x}, false
+
+	switch x := x.(type) {
+	case *Array:
+		elements, vector = x.Elements, true
+	case *Matrix:
+		elements, vector = x.Elements(), true
+	}
+
+	for _, element := range elements {
+		switch element.(type) {
+		case *Integer, *Double, *Boolean, *NotAvailable:
+		default:
+			if vector {
+				return nil, false, newError("parameters to `%s` must be numeric, got %s inside an ARRAY", function, element.Type())
+			}
+
+			return nil, false, newError("parameters to `%s` must be numeric, got %s", function, element.Type())
+		}
+	}
+
+	return elements, vector, nil
+}
+
+// numericValues : the values of x as doubles, telling whether none of them was a double and whether any was missing;
+// with na.rm, missing values and NaN are left out, just like R does
+func numericValues(function string, x Object, naRm bool) ([]float64, bool, bool, *Error) {
+	elements, _, err := numericElements(function, x)
+
+	if nil != err {
+		return nil, false, false, err
+	}
+
+	values := []float64{}
+	integer := true
+	missing := false
+
+	for _, element := range elements {
+		value := asDouble(element)
+
+		switch {
+		case NA == element:
+			missing = missing || !naRm
+
+			continue
+		case naRm && math.IsNaN(value):
+			continue
+		}
+
+		if DOUBLE_OBJECT == element.Type() {
+			integer = false
+		}
+
+		values = append(values, value)
+	}
+
+	return values, integer, missing, nil
+}
+
+// summaryParameters : `f(x, na.rm)`, na.rm being optional
+func summaryParameters(function string, parameters []Object) ([]float64, bool, bool, *Error) {
+	if 1 > len(parameters) || 2 < len(parameters) {
+		return nil, false, false, newError("wrong number of parameters, got=%d, want=1 or 2", len(parameters))
+	}
+
+	flags, err := logicalFlags(function, parameters[1:], "na.rm")
+
+	if nil != err {
+		return nil, false, false, err
+	}
+
+	return numericValues(function, parameters[0], flags["na.rm"])
+}
+
+// summaryDotsParameters : `f(..., na.rm)`, na.rm being optional; the values of all the arguments are summarised
+// together, just like R does
+func summaryDotsParameters(function string, parameters []Object) ([]float64, bool, bool, *Error) {
+	flags, err := logicalFlags(function, parameters[1:], "na.rm")
+
+	if nil != err {
+		return nil, false, false, err
+	}
+
+	values := []float64{}
+	integer := true
+	missing := false
+
+	for _, argument := range parameters[0].(*List).Elements {
+		argumentValues, argumentInteger, argumentMissing, err := numericValues(function, argument, flags["na.rm"])
+
+		if nil != err {
+			return nil, false, false, err
+		}
+
+		values = append(values, argumentValues...)
+		integer = integer && argumentInteger
+		missing = missing || argumentMissing
+	}
+
+	return values, integer, missing, nil
+}
+
+// mapNumeric : applies fn to every element of x, missing values staying missing and a matrix keeping its dimensions
+func mapNumeric(function string, x Object, fn func(element Object) Object) Object {
+	elements, vector, err := numericElements(function, x)
+
+	if nil != err {
+		return err
+	}
+
+	results := make([]Object, len(elements))
+
+	for index, element := range elements {
+		if NA == element {
+			results[index] = NA
+		} else {
+			results[index] = fn(element)
+		}
+	}
+
+	if matrix, ok := x.(*Matrix); ok {
+		values, _ := matrixData("x", function, &Array{
+			Elements: results,
+		})
+
+		return &Matrix{
+			Rows:    matrix.Rows,
+			Columns: matrix.Columns,
+			Values:  values,
+		}
+	}
+
+	return vectorResult(results, vector)
+}
+
+// mathFunction : builtins taking a single vector, giving a double for each element
+func mathFunction(function string, fn func(value float64) float64) BuiltinFunction {
+	return func(context CallContext, parameters ...Object) Object {
+		if 1 != len(parameters) {
+			return newError("wrong number of parameters, got=%d, want=1", len(parameters))
+		}
+
+		return mapNumeric(function, parameters[0], func(element Object) Object {
+			return &Double{
+				Value: fn(asDouble(element)),
+			}
+		})
+	}
+}
+
+// numericParameter : a single number, such as the base of `log` or the digits of `round`
+func numericParameter(name string, function string, parameter Object) (float64, *Error) {
+	switch parameter.(type) {
+	case *Integer, *Double:
+		return asDouble(parameter), nil
+	}
+
+	return 0, newError("%s to `%s` must be numeric, got %s", name, function, parameter.Type())
+}
+
+// absBuiltin : integers stay integers
+func absBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1", len(parameters))
+	}
+
+	return mapNumeric("abs", parameters[0], func(element Object) Object {
+		if DOUBLE_OBJECT == element.Type() {
+			return &Double{
+				Value: math.Abs(asDouble(element)),
+			}
+		}
+
+		value := int64(asDouble(element))
+
+		if 0 > value {
+			value = -value
+		}
+
+		return &Integer{
+			Value: value,
+		}
+	})
+}
+
+// log10 : Go computes it as log(x) / log(10), missing exact powers of ten that C's log10 gets right
+func log10(value float64) float64 {
+	result := math.Log10(value)
+
+	if rounded := math.Round(result); rounded != result && math.Pow(10, rounded) == value {
+		return rounded
+	}
+
+	return result
+}
+
+// logBuiltin : `log(x, base)`, base being e unless given; just like R, 2 and 10 get their own functions
+func logBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 > len(parameters) || 2 < len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1 or 2", len(parameters))
+	}
+
+	logarithm := math.Log
+
+	if 2 == len(parameters) {
+		base, err := numericParameter("base", "log", parameters[1])
+
+		if nil != err {
+			return err
+		}
+
+		switch base {
+		case 10:
+			logarithm = log10
+		case 2:
+			logarithm = math.Log2
+		default:
+			logarithm = func(value float64) float64 {
+				return math.Log(value) / math.Log(base)
+			}
+		}
+	}
+
+	return mapNumeric("log", parameters[0], func(element Object) Object {
+		return &Double{
+			Value: logarithm(asDouble(element)),
+		}
+	})
+}
+
+// powerDI : R's R_pow_di, multiplying by squares so large powers round the same way
+func powerDI(base float64, exponent int) float64 {
+	result := 1.0
+	negative := 0 > exponent
+
+	if negative {
+		exponent = -exponent
+	}
+
+	for {
+		if 1 == exponent&1 {
+			result *= base
+		}
+
+		if exponent >>= 1; 0 == exponent {
+			break
+		}
+
+		base *= base
+	}
+
+	if negative {
+		return 1 / result
+	}
+
+	return result
+}
+
+// roundDigits : R 4's algorithm, which picks whichever of the two candidates around x is closest to it, ties going
+// to the even one -- so round(0.15, 1) is 0.1, as 0.15 is actually slightly less than that
+func roundDigits(x float64, digits float64) float64 {
+	const maximumDigits = 308
+
+	switch {
+	case math.IsNaN(x) || math.IsNaN(digits):
+		return x + digits
+	case math.IsInf(x, 0) || digits > maximumDigits+15 || 0 == x:
+		return x
+	case digits < -maximumDigits:
+		return 0
+	case 0 == digits:
+		return math.RoundToEven(x)
+	}
+
+	dig := int(math.Floor(digits + 0.5))
+	sign := 1.0
+
+	if 0 > x {
+		sign, x = -1, -x
+	}
+
+	// asking for more digits than a double holds leaves x as it is
+	if math.Log10(2)*(0.5+math.Logb(x))+float64(dig) > 15 {
+		return sign * x
+	}
+
+	var x10, i10, down, up float64
+
+	if 0 < dig {
+		power := powerDI(10, dig)
+		x10 = power * x
+		i10 = math.Floor(x10)
+		down, up = i10/power, math.Ceil(x10)/power
+	} else {
+		power := powerDI(10, -dig)
+		x10 = x / power
+		i10 = math.Floor(x10)
+		down, up = i10*power, math.Ceil(x10)*power
+	}
+
+	distanceUp, distanceDown := up-x, x-down
+
+	if distanceUp < distanceDown || (distanceUp == distanceDown && 1 == math.Mod(i10, 2)) {
+		return sign * up
+	}
+
+	return sign * down
+}
+
+// roundBuiltin : `round(x, digits)`, digits being 0 unless given; integers are left untouched unless digits is negative
+func roundBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 > len(parameters) || 2 < len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1 or 2", len(parameters))
+	}
+
+	digits := 0.0
+
+	if 2 == len(parameters) {
+		var err *Error
+
+		if digits, err = numericParameter("digits", "round", parameters[1]); nil != err {
+			return err
+		}
+	}
+
+	return mapNumeric("round", parameters[0], func(element Object) Object {
+		if DOUBLE_OBJECT != element.Type() && 0 <= digits {
+			return &Integer{
+				Value: int64(asDouble(element)),
+			}
+		}
+
+		return &Double{
+			Value: roundDigits(asDouble(element), digits),
+		}
+	})
+}
+
+// DivideIntegers : integers are divided leaving out the remainder, just like R's `%/%`; dividing by zero, or the
+// smallest integer by -1 whose quotient does not fit in an integer, gives NA just as R does
+func DivideIntegers(dividend int64, divisor int64) Object {
+	if 0 == divisor || (math.MinInt64 == dividend && -1 == divisor) {
+		return NA
+	}
+
+	return &Integer{
+		Value: dividend / divisor,
+	}
+}
+
+// integerValue : the exact value of an INTEGER or a BOOLEAN, which counts as 0 or 1; false for anything else
+func integerValue(obj Object) (int64, bool) {
+	switch obj := obj.(type) {
+	case *Integer:
+		return obj.Value, true
+	case *Boolean:
+		if obj.Value {
+			return 1, true
+		}
+
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// exactIntegers : the values of the arguments as they are, as doubles lose the digits of integers beyond 2^53;
+// missing values are left out, the summaries dealing with them before
+func exactIntegers(arguments []Object) []int64 {
+	values := []int64{}
+
+	for _, argument := range arguments {
+		elements, _, _ := numericElements("", argument)
+
+		for _, element := range elements {
+			if value, ok := integerValue(element); ok {
+				values = append(values, value)
+			}
+		}
+	}
+
+	return values
+}
+
+// addIntegers : false when the sum does not fit in an integer
+func addIntegers(a int64, b int64) (int64, bool) {
+	sum := a + b
+
+	return sum, (sum > a) == (b > 0)
+}
+
+// integerOverflow : NA, with a warning, just like R when integers add up beyond what an integer holds
+func integerOverflow(context CallContext, message string) Object {
+	if err := context.Signal(&Condition{Class: "warning", Message: message}); nil != err {
+		return err
+	}
+
+	return NA
+}
+
+// sumBuiltin : `sum(..., na.rm)`; integers add up to an integer, NA when it overflows
+func sumBuiltin(context CallContext, parameters ...Object) Object {
+	values, integer, missing, err := summaryDotsParameters("sum", parameters)
+
+	switch {
+	case nil != err:
+		return err
+	case missing:
+		return NA
+	case integer:
+		var sum int64
+
+		for _, value := range exactIntegers(parameters[0].(*List).Elements) {
+			var fits bool
+
+			if sum, fits = addIntegers(sum, value); !fits {
+				return integerOverflow(context, "integer overflow - use sum(as.numeric(.))")
+			}
+		}
+
+		return &Integer{
+			Value: sum,
+		}
+	case !allFinite(values):
+		sum := 0.0
+
+		for _, value := range values {
+			sum += value
+		}
+
+		return &Double{
+			Value: sum,
+		}
+	}
+
+	return &Double{
+		Value: toDouble(longDoubleSum(values)),
+	}
+}
+
+// prodBuiltin : `prod(..., na.rm)`, always a double
+func prodBuiltin(context CallContext, parameters ...Object) Object {
+	values, _, missing, err := summaryDotsParameters("prod", parameters)
+
+	switch {
+	case nil != err:
+		return err
+	case missing:
+		return NA
+	case !allFinite(values):
+		product := 1.0
+
+		for _, value := range values {
+			product *= value
+		}
+
+		return &Double{
+			Value: product,
+		}
+	}
+
+	product := longDouble(1)
+
+	for _, value := range values {
+		product.Mul(product, longDouble(value))
+	}
+
+	return &Double{
+		Value: toDouble(product),
+	}
+}
+
+// extremes : the smallest and the largest values, NaN winning over any other; Inf and -Inf when there are none
+func extremes(values []float64) (float64, float64) {
+	minimum, maximum := math.Inf(1), math.Inf(-1)
+
+	for _, value := range values {
+		if math.IsNaN(value) {
+			return value, value
+		}
+
+		minimum, maximum = math.Min(minimum, value), math.Max(maximum, value)
+	}
+
+	return minimum, maximum
+}
+
+// summaryExtremes : the smallest and the largest values of the arguments, integers staying exact integers unless
+// there were no values at all
+func summaryExtremes(arguments []Object, values []float64, integer bool) (Object, Object) {
+	if integer && 0 != len(values) {
+		integers := exactIntegers(arguments)
+		minimum, maximum := integers[0], integers[0]
+
+		for _, value := range integers {
+			if value < minimum {
+				minimum = value
+			}
+
+			if value > maximum {
+				maximum = value
+			}
+		}
+
+		return &Integer{Value: minimum}, &Integer{Value: maximum}
+	}
+
+	minimum, maximum := extremes(values)
+
+	return &Double{Value: minimum}, &Double{Value: maximum}
+}
+
+// minBuiltin : `min(..., na.rm)`
+func minBuiltin(context CallContext, parameters ...Object) Object {
+	values, integer, missing, err := summaryDotsParameters("min", parameters)
+
+	switch {
+	case nil != err:
+		return err
+	case missing:
+		return NA
+	}
+
+	minimum, _ := summaryExtremes(parameters[0].(*List).Elements, values, integer)
+
+	return minimum
+}
+
+// maxBuiltin : `max(..., na.rm)`
+func maxBuiltin(context CallContext, parameters ...Object) Object {
+	values, integer, missing, err := summaryDotsParameters("max", parameters)
+
+	switch {
+	case nil != err:
+		return err
+	case missing:
+		return NA
+	}
+
+	_, maximum := summaryExtremes(parameters[0].(*List).Elements, values, integer)
+
+	return maximum
+}
+
+// rangeBuiltin : `range(..., na.rm)` gives both the minimum and the maximum
+func rangeBuiltin(context CallContext, parameters ...Object) Object {
+	values, integer, missing, err := summaryDotsParameters("range", parameters)
+
+	switch {
+	case nil != err:
+		return err
+	case missing:
+		return &Array{
+			Elements: []Object{NA, NA},
+		}
+	}
+
+	minimum, maximum := summaryExtremes(parameters[0].(*List).Elements, values, integer)
+
+	return &Array{
+		Elements: []Object{minimum, maximum},
+	}
+}
+
+// meanOf : R's mean, which corrects the long double sum of doubles by a second pass over the deviations
+func meanOf(values []float64, integer bool) float64 {
+	if 0 == len(values) {
+		return math.NaN()
+	}
+
+	if !allFinite(values) {
+		sum := 0.0
+
+		for _, value := range values {
+			sum += value
+		}
+
+		return sum / float64(len(values))
+	}
+
+	count := longDouble(float64(len(values)))
+	mean := longDoubleSum(values)
+	mean.Quo(mean, count)
+
+	if integer {
+		return toDouble(mean)
+	}
+
+	deviations := longDouble(0)
+
+	for _, value := range values {
+		deviations.Add(deviations, longDouble(0).Sub(longDouble(value), mean))
+	}
+
+	mean.Add(mean, deviations.Quo(deviations, count))
+
+	return toDouble(mean)
+}
+
+// meanBuiltin : `mean(x, na.rm)`
+func meanBuiltin(context CallContext, parameters ...Object) Object {
+	values, integer, missing, err := summaryParameters("mean", parameters)
+
+	switch {
+	case nil != err:
+		return err
+	case missing:
+		return NA
+	}
+
+	return &Double{
+		Value: meanOf(values, integer),
+	}
+}
+
+// sorted :
+func sorted(values []float64) []float64 {
+	result := append([]float64{}, values...)
+	sort.Float64s(result)
+
+	return result
+}
+
+// medianBuiltin : `median(x, na.rm)`; the middle integer stays an integer, two of them are averaged as a double
+func medianBuiltin(context CallContext, parameters ...Object) Object {
+	values, integer, missing, err := summaryParameters("median", parameters)
+
+	switch {
+	case nil != err:
+		return err
+	case missing || 0 == len(values):
+		return NA
+	}
+
+	for _, value := range values {
+		if math.IsNaN(value) {
+			return NA
+		}
+	}
+
+	values = sorted(values)
+	half := len(values) / 2
+
+	if 1 == len(values)%2 {
+		if integer {
+			integers := exactIntegers(parameters[:1])
+			sort.Slice(integers, func(i, j int) bool { return integers[i] < integers[j] })
+
+			return &Integer{
+				Value: integers[half],
+			}
+		}
+
+		return &Double{
+			Value: values[half],
+		}
+	}
+
+	return &Double{
+		Value: meanOf(values[half-1:half+1], integer),
+	}
+}
+
+// centre : the mean R's `var` and `cor` work with, always corrected by the second pass
+func centre(values []float64) float64 {
+	return meanOf(values, false)
+}
+
+// covariance : the long double sum of the products of the deviations, divided by n - 1
+func covariance(x, y []float64) *big.Float {
+	xMean, yMean := centre(x), centre(y)
+	sum := longDouble(0)
+
+	for index := range x {
+		sum.Add(sum, longDouble((x[index]-xMean)*(y[index]-yMean)))
+	}
+
+	return sum.Quo(sum, longDouble(float64(len(x)-1)))
+}
+
+// variance :
+func variance(values []float64) Object {
+	if 2 > len(values) {
+		return NA
+	}
+
+	if !allFinite(values) {
+		return &Double{
+			Value: math.NaN(),
+		}
+	}
+
+	return &Double{
+		Value: toDouble(covariance(values, values)),
+	}
+}
+
+// varBuiltin : `var(x, na.rm)`, the sample variance
+func varBuiltin(context CallContext, parameters ...Object) Object {
+	values, _, missing, err := summaryParameters("var", parameters)
+
+	switch {
+	case nil != err:
+		return err
+	case missing:
+		return NA
+	}
+
+	return variance(values)
+}
+
+// sdBuiltin : `sd(x, na.rm)`, the square root of `var`
+func sdBuiltin(context CallContext, parameters ...Object) Object {
+	values, _, missing, err := summaryParameters("sd", parameters)
+
+	switch {
+	case nil != err:
+		return err
+	case missing:
+		return NA
+	}
+
+	result := variance(values)
+
+	if double, ok := result.(*Double); ok {
+		double.Value = math.Sqrt(double.Value)
+	}
+
+	return result
+}
+
+// quantileBuiltin : `quantile(x, probs, na.rm)` with R's default type 7, probs being 0, .25, .5, .75 and 1 unless given
+func quantileBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 > len(parameters) || 3 < len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1 to 3", len(parameters))
+	}
+
+	probabilities := []float64{0, 0.25, 0.5, 0.75, 1}
+
+	if given(parameters, 1) {
+		values, _, missing, err := numericValues("quantile", parameters[1], false)
+
+		if nil != err {
+			return err
+		}
+
+		probabilities = values
+
+		for _, probability := range probabilities {
+			if missing || math.IsNaN(probability) || 0 > probability || 1 < probability {
+				return newError("probs to `quantile` must be between 0 and 1")
+			}
+		}
+	}
+
+	flags := map[string]bool{}
+
+	if 3 == len(parameters) {
+		var err *Error
+
+		if flags, err = logicalFlags("quantile", parameters[2:], "na.rm"); nil != err {
+			return err
+		}
+	}
+
+	values, _, missing, err := numericValues("quantile", parameters[0], flags["na.rm"])
+
+	if nil != err {
+		return err
+	}
+
+	for _, value := range values {
+		missing = missing || math.IsNaN(value)
+	}
+
+	if missing {
+		return newError("missing values and NaN's not allowed in `quantile` if na.rm is FALSE")
+	}
+
+	values = sorted(values)
+	results := make([]Object, len(probabilities))
+
+	for position, probability := range probabilities {
+		if 0 == len(values) {
+			results[position] = NA
+
+			continue
+		}
+
+		// R's 1-based index, as h is computed from it
+		index := 1 + float64(len(values)-1)*probability
+		low, high := math.Floor(index), math.Ceil(index)
+		quantile := values[int(low)-1]
+
+		if index > low && values[int(high)-1] != quantile {
+			h := index - low
+			quantile = (1-h)*quantile + h*values[int(high)-1]
+		}
+
+		results[position] = &Double{
+			Value: quantile,
+		}
+	}
+
+	return &Array{
+		Elements: results,
+	}
+}
+
+// cumsumBuiltin : `cumsum(x)`; once a value is missing, so are the following sums
+func cumsumBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1", len(parameters))
+	}
+
+	elements, _, err := numericElements("cumsum", parameters[0])
+
+	if nil != err {
+		return err
+	}
+
+	integer := true
+
+	for _, element := range elements {
+		if DOUBLE_OBJECT == element.Type() {
+			integer = false
+		}
+	}
+
+	results := make([]Object, len(elements))
+	sum := longDouble(0)
+	plain := 0.0
+	finite := true
+	var integerSum int64
+
+	for index, element := range elements {
+		if NA == element || (0 < index && NA == results[index-1]) {
+			results[index] = NA
+
+			continue
+		}
+
+		if integer {
+			value, _ := integerValue(element)
+			var fits bool
+
+			if integerSum, fits = addIntegers(integerSum, value); !fits {
+				if warned := integerOverflow(context, "integer overflow in 'cumsum'; use 'cumsum(as.numeric(.))'"); NA != warned {
+					return warned
+				}
+
+				for ; index < len(elements); index++ {
+					results[index] = NA
+				}
+
+				break
+			}
+
+			results[index] = &Integer{
+				Value: integerSum,
+			}
+
+			continue
+		}
+
+		value := asDouble(element)
+
+		// once a sum is no longer finite, IEEE arithmetic takes over
+		if finite && (math.IsNaN(value) || math.IsInf(value, 0)) {
+			finite, plain = false, toDouble(sum)
+		}
+
+		if finite {
+			sum.Add(sum, longDouble(value))
+			results[index] = &Double{
+				Value: toDouble(sum),
+			}
+		} else {
+			plain += value
+			results[index] = &Double{
+				Value: plain,
+			}
+		}
+	}
+
+	return &Array{
+		Elements: results,
+	}
+}
+
+// corBuiltin : `cor(x, y, use)`, Pearson's correlation; use is either "everything", where a missing value makes it
+// missing, or "complete.obs", where the pairs holding one are left out
+func corBuiltin(context CallContext, parameters ...Object) Object {
+	if 2 > len(parameters) || 3 < len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=2 or 3", len(parameters))
+	}
+
+	complete := false
+
+	if 3 == len(parameters) {
+		use, ok := parameters[2].(*String)
+
+		if !ok || ("everything" != use.Value && "complete.obs" != use.Value) {
+			return newError("use to `cor` must be \"everything\" or \"complete.obs\", got %s", parameters[2].Inspect())
+		}
+
+		complete = "complete.obs" == use.Value
+	}
+
+	xElements, _, err := numericElements("cor", parameters[0])
+
+	if nil != err {
+		return err
+	}
+
+	yElements, _, err := numericElements("cor", parameters[1])
+
+	if nil != err {
+		return err
+	}
+
+	if len(xElements) != len(yElements) {
+		return newError("x and y given to `cor` must have the same length, got %d and %d", len(xElements), len(yElements))
+	}
+
+	x, y := []float64{}, []float64{}
+
+	for index := range xElements {
+		if NA == xElements[index] || NA == yElements[index] {
+			if complete {
+				continue
+			}
+
+			return NA
+		}
+
+		x, y = append(x, asDouble(xElements[index])), append(y, asDouble(yElements[index]))
+	}
+
+	if 2 > len(x) {
+		return NA
+	}
+
+	if !allFinite(x) || !allFinite(y) {
+		return &Double{
+			Value: math.NaN(),
+		}
+	}
+
+	xVariance, yVariance := covariance(x, x), covariance(y, y)
+
+	// a constant vector has no correlation, R warns about its standard deviation being zero
+	if 0 == xVariance.Sign() || 0 == yVariance.Sign() {
+		return NA
+	}
+
+	deviations := longDouble(0).Mul(longDouble(0).Sqrt(xVariance), longDouble(0).Sqrt(yVariance))
+	correlation := covariance(x, y)
+	correlation.Quo(correlation, deviations)
+
+	return &Double{
+		Value: math.Max(-1, math.Min(1, toDouble(correlation))),
+	}
+}
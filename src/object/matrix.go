@@ -0,0 +1,890 @@
+package object
+
+import (
+	"fmt"
+	"math"
+)
+
+// notAvailableBits : R stores NA inside doubles as a NaN carrying 1954 as its payload, so matrices can hold it too
+const notAvailableBits = 0x7FF00000000007A2
+
+// smallestNormal : below it, LAPACK divides by the pivot instead of multiplying by its reciprocal
+const smallestNormal = 0x1p-1022
+
+// epsilon : R's .Machine$double.eps
+const epsilon = 0x1p-52
+
+// notAvailableDouble :
+var notAvailableDouble = math.Float64frombits(notAvailableBits)
+
+// isNotAvailableDouble : arithmetic keeps the payload of the NaN, so NA stays NA through the operations
+func isNotAvailableDouble(value float64) bool {
+	return math.IsNaN(value) && 1954 == uint32(math.Float64bits(value))
+}
+
+// Matrix : dense and stored column by column, just like R, so the element [row, column] lives at row + column*Rows
+type Matrix struct {
+	Rows    int
+	Columns int
+	Values  []float64
+}
+
+// Type :
+func (m *Matrix) Type() ObjectType {
+	return MATRIX_OBJECT
+}
+
+// Inspect : prints the matrix the way R does, only with 0-based labels
+func (m *Matrix) Inspect() string {
+	cells := make([][]string, m.Columns+1)
+	cells[0] = make([]string, m.Rows+1)
+
+	for row := 0; row < m.Rows; row++ {
+		cells[0][row+1] = fmt.Sprintf("[%d,]", row)
+	}
+
+	for column := 0; column < m.Columns; column++ {
+		cells[column+1] = make([]string, m.Rows+1)
+		cells[column+1][0] = fmt.Sprintf("[,%d]", column)
+
+		for row := 0; row < m.Rows; row++ {
+			cells[column+1][row+1] = m.Element(row, column).Inspect()
+		}
+	}
+
+	return formatTable(cells)
+}
+
+// InitializeMatrix :
+func InitializeMatrix(rows, columns int) *Matrix {
+	return &Matrix{
+		Rows:    rows,
+		Columns: columns,
+		Values:  make([]float64, rows*columns),
+	}
+}
+
+// Dimensions : as R reports them in its errors
+func (m *Matrix) Dimensions() string {
+	return fmt.Sprintf("%dx%d", m.Rows, m.Columns)
+}
+
+// at :
+func (m *Matrix) at(row, column int) float64 {
+	return m.Values[row+column*m.Rows]
+}
+
+// set :
+func (m *Matrix) set(row, column int, value float64) {
+	m.Values[row+column*m.Rows] = value
+}
+
+// doubleObject : NA goes back to being NA once it leaves the matrix
+func doubleObject(value float64) Object {
+	if isNotAvailableDouble(value) {
+		return NA
+	}
+
+	return &Double{
+		Value: value,
+	}
+}
+
+// Element :
+func (m *Matrix) Element(row, column int) Object {
+	return doubleObject(m.at(row, column))
+}
+
+// vector : the elements at the given positions of the storage
+func (m *Matrix) vector(positions []int) *Array {
+	elements := make([]Object, len(positions))
+
+	for index, position := range positions {
+		elements[index] = doubleObject(m.Values[position])
+	}
+
+	return &Array{
+		Elements: elements,
+	}
+}
+
+// Elements : the matrix as the vector it is stored as
+func (m *Matrix) Elements() []Object {
+	elements := make([]Object, len(m.Values))
+
+	for index, value := range m.Values {
+		elements[index] = doubleObject(value)
+	}
+
+	return elements
+}
+
+// Row :
+func (m *Matrix) Row(row int) *Array {
+	positions := make([]int, m.Columns)
+
+	for column := range positions {
+		positions[column] = row + column*m.Rows
+	}
+
+	return m.vector(positions)
+}
+
+// Column :
+func (m *Matrix) Column(column int) *Array {
+	positions := make([]int, m.Rows)
+
+	for row := range positions {
+		positions[row] = row + column*m.Rows
+	}
+
+	return m.vector(positions)
+}
+
+// Transpose :
+func (m *Matrix) Transpose() *Matrix {
+	transposed := InitializeMatrix(m.Columns, m.Rows)
+
+	for row := 0; row < m.Rows; row++ {
+		for column := 0; column < m.Columns; column++ {
+			transposed.set(column, row, m.at(row, column))
+		}
+	}
+
+	return transposed
+}
+
+// Index : `m[i]` looks the matrix up as the vector it is stored as, NULL when it is out of range
+func (m *Matrix) Index(index Object) Object {
+	position, ok := index.(*Integer)
+
+	if !ok {
+		return newError("matrix index must be INTEGER, got %s", index.Type())
+	}
+
+	if position.Value < 0 || position.Value >= int64(len(m.Values)) {
+		return nil
+	}
+
+	return doubleObject(m.Values[position.Value])
+}
+
+// selectPositions : NULL selects every row or column, booleans work as a mask and integers as positions
+func selectPositions(dimension string, selection Object, length int) ([]int, *Error) {
+	selected := []int{}
+
+	switch selection := selection.(type) {
+	case *Null:
+		for position := 0; position < length; position++ {
+			selected = append(selected, position)
+		}
+	case *Integer:
+		if selection.Value < 0 || selection.Value >= int64(length) {
+			return nil, newError("%s index out of range: %d", dimension, selection.Value)
+		}
+
+		selected = append(selected, int(selection.Value))
+	case *Array:
+		if isMask(selection) {
+			if len(selection.Elements) != length {
+				return nil, newError("logical %s index has length %d, want=%d", dimension, len(selection.Elements), length)
+			}
+
+			for position, element := range selection.Elements {
+				if keep, ok := element.(*Boolean); ok && keep.Value {
+					selected = append(selected, position)
+				}
+			}
+
+			return selected, nil
+		}
+
+		for _, element := range selection.Elements {
+			positions, err := selectPositions(dimension, element, length)
+
+			if nil != err {
+				return nil, err
+			}
+
+			selected = append(selected, positions...)
+		}
+	default:
+		return nil, newError("%s index must be INTEGER, ARRAY or NULL, got %s", dimension, selection.Type())
+	}
+
+	return selected, nil
+}
+
+// Slice : `m[rows, columns]`, a single row or column being returned as a vector and a single element as a scalar,
+// just like R drops the dimensions it does not need
+func (m *Matrix) Slice(rows, columns Object) Object {
+	selectedRows, err := selectPositions("row", rows, m.Rows)
+
+	if nil != err {
+		return err
+	}
+
+	selectedColumns, err := selectPositions("column", columns, m.Columns)
+
+	if nil != err {
+		return err
+	}
+
+	result := InitializeMatrix(len(selectedRows), len(selectedColumns))
+	positions := make([]int, 0, len(result.Values))
+
+	for column, selectedColumn := range selectedColumns {
+		for row, selectedRow := range selectedRows {
+			result.set(row, column, m.at(selectedRow, selectedColumn))
+			positions = append(positions, selectedRow+selectedColumn*m.Rows)
+		}
+	}
+
+	_, singleRow := rows.(*Integer)
+	_, singleColumn := columns.(*Integer)
+
+	switch {
+	case singleRow && singleColumn:
+		return m.Element(selectedRows[0], selectedColumns[0])
+	case singleRow || singleColumn:
+		return m.vector(positions)
+	}
+
+	return result
+}
+
+// matrixValue : the numbers a matrix can hold, BOOLEAN counting as 0 or 1
+func matrixValue(obj Object) (float64, bool) {
+	switch obj.(type) {
+	case *Integer, *Double, *Boolean:
+		return asDouble(obj), true
+	case *NotAvailable:
+		return notAvailableDouble, true
+	}
+
+	return 0, false
+}
+
+// matrixData : the values of a matrix, a vector or a single number, the way they are stored in a matrix
+func matrixData(name string, function string, x Object) ([]float64, *Error) {
+	switch x := x.(type) {
+	case *Matrix:
+		return append([]float64{}, x.Values...), nil
+	case *Array:
+		values := make([]float64, len(x.Elements))
+
+		for index, element := range x.Elements {
+			value, ok := matrixValue(element)
+
+			if !ok {
+				return nil, newError("%s to `%s` must be numeric, got %s inside an ARRAY", name, function, element.Type())
+			}
+
+			values[index] = value
+		}
+
+		return values, nil
+	}
+
+	value, ok := matrixValue(x)
+
+	if !ok {
+		return nil, newError("%s to `%s` must be numeric, got %s", name, function, x.Type())
+	}
+
+	return []float64{value}, nil
+}
+
+// asMatrix : vectors become a single column, as R does whenever a matrix is expected
+func asMatrix(name string, function string, x Object) (*Matrix, *Error) {
+	if matrix, ok := x.(*Matrix); ok {
+		return matrix, nil
+	}
+
+	values, err := matrixData(name, function, x)
+
+	if nil != err {
+		return nil, err
+	}
+
+	return &Matrix{
+		Rows:    len(values),
+		Columns: 1,
+		Values:  values,
+	}, nil
+}
+
+// operandValues : the other side of an element-wise operation, recycled when it is a shorter vector
+func operandValues(operand Object, matrix *Matrix) ([]float64, *Error) {
+	if other, ok := operand.(*Matrix); ok {
+		return other.Values, nil
+	}
+
+	values, err := matrixData("operands", "", operand)
+
+	if nil != err {
+		return nil, newError("operands of a MATRIX must be numeric, got %s", operand.Type())
+	}
+
+	if 0 == len(values) || len(values) > len(matrix.Values) || 0 != len(matrix.Values)%len(values) {
+		return nil, newError("vector of length %d does not fit a %s matrix", len(values), matrix.Dimensions())
+	}
+
+	return values, nil
+}
+
+// elementWise : both matrices must have the very same dimensions, vectors and numbers being recycled over the matrix
+func elementWise(operator string, left, right Object) (Object, *Error) {
+	leftMatrix, leftIsMatrix := left.(*Matrix)
+	rightMatrix, rightIsMatrix := right.(*Matrix)
+
+	if leftIsMatrix && rightIsMatrix && (leftMatrix.Rows != rightMatrix.Rows || leftMatrix.Columns != rightMatrix.Columns) {
+		return nil, newError("non-conformable arrays: %s %s %s", leftMatrix.Dimensions(), operator, rightMatrix.Dimensions())
+	}
+
+	shape := leftMatrix
+
+	if !leftIsMatrix {
+		shape = rightMatrix
+	}
+
+	leftValues, err := operandValues(left, shape)
+
+	if nil != err {
+		return nil, err
+	}
+
+	rightValues, err := operandValues(right, shape)
+
+	if nil != err {
+		return nil, err
+	}
+
+	result := InitializeMatrix(shape.Rows, shape.Columns)
+
+	for index := range result.Values {
+		leftValue := leftValues[index%len(leftValues)]
+		rightValue := rightValues[index%len(rightValues)]
+
+		switch operator {
+		case "+":
+			result.Values[index] = leftValue + rightValue
+		case "-":
+			result.Values[index] = leftValue - rightValue
+		case "*":
+			result.Values[index] = leftValue * rightValue
+		case "/":
+			result.Values[index] = leftValue / rightValue
+		}
+	}
+
+	return result, nil
+}
+
+// productOperand : a vector is a row on the left side of `%*%` and a column on the right side
+func productOperand(operand Object, left bool) (*Matrix, *Error) {
+	matrix, err := asMatrix("operands", "%*%", operand)
+
+	if nil != err {
+		return nil, err
+	}
+
+	if _, ok := operand.(*Matrix); !ok && left {
+		return matrix.Transpose(), nil
+	}
+
+	return matrix, nil
+}
+
+// matrixProduct : every element is summed in the order the reference BLAS does it
+func matrixProduct(left, right Object) (Object, *Error) {
+	a, err := productOperand(left, true)
+
+	if nil != err {
+		return nil, err
+	}
+
+	b, err := productOperand(right, false)
+
+	if nil != err {
+		return nil, err
+	}
+
+	if a.Columns != b.Rows {
+		return nil, newError("non-conformable arguments: %s %%*%% %s", a.Dimensions(), b.Dimensions())
+	}
+
+	result := InitializeMatrix(a.Rows, b.Columns)
+
+	for column := 0; column < b.Columns; column++ {
+		for inner := 0; inner < a.Columns; inner++ {
+			factor := b.at(inner, column)
+
+			for row := 0; row < a.Rows; row++ {
+				result.Values[row+column*a.Rows] += factor * a.at(row, inner)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// MatrixOperation : the arithmetic shared by the evaluator and the virtual machine whenever a matrix is involved
+func MatrixOperation(operator string, left, right Object) (Object, *Error) {
+	switch operator {
+	case "%*%":
+		return matrixProduct(left, right)
+	case "+", "-", "*", "/":
+		return elementWise(operator, left, right)
+	}
+
+	return nil, newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+}
+
+// Negate : `-m`
+func (m *Matrix) Negate() *Matrix {
+	negated := InitializeMatrix(m.Rows, m.Columns)
+
+	for index, value := range m.Values {
+		negated.Values[index] = -value
+	}
+
+	return negated
+}
+
+// dimensionParameter : the number of rows or columns, which must be a whole non negative number
+func dimensionParameter(name string, function string, parameter Object) (int, *Error) {
+	switch parameter := parameter.(type) {
+	case *Integer:
+		if 0 <= parameter.Value {
+			return int(parameter.Value), nil
+		}
+	case *Double:
+		if 0 <= parameter.Value && parameter.Value == math.Trunc(parameter.Value) && !math.IsInf(parameter.Value, 0) {
+			return int(parameter.Value), nil
+		}
+	}
+
+	return 0, newError("%s to `%s` must be a non negative integer, got %s", name, function, parameter.Inspect())
+}
+
+// matrixBuiltin : `matrix(data, nrow, ncol, byrow)` fills the matrix column by column, or row by row with byrow,
+// recycling data when it is shorter; with no data at all, the matrix is full of NA
+func matrixBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 > len(parameters) || 4 < len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1 to 4", len(parameters))
+	}
+
+	values, err := matrixData("data", "matrix", parameters[0])
+
+	if nil != err {
+		return err
+	}
+
+	rows, columns := len(values), 1
+
+	if given(parameters, 1) {
+		rows, err = dimensionParameter("nrow", "matrix", parameters[1])
+
+		if nil != err {
+			return err
+		}
+
+		columns = 0
+
+		if 0 < rows {
+			columns = (len(values) + rows - 1) / rows
+		}
+	}
+
+	if given(parameters, 2) {
+		columns, err = dimensionParameter("ncol", "matrix", parameters[2])
+
+		if nil != err {
+			return err
+		}
+
+		// with only ncol given, the rows are as many as it takes to hold data
+		if !given(parameters, 1) {
+			rows = 0
+
+			if 0 < columns {
+				rows = (len(values) + columns - 1) / columns
+			}
+		}
+	}
+
+	byRow := false
+
+	if 3 < len(parameters) {
+		flags, err := logicalFlags("matrix", parameters[3:], "byrow")
+
+		if nil != err {
+			return err
+		}
+
+		byRow = flags["byrow"]
+	}
+
+	size := rows * columns
+
+	if 0 == len(values) {
+		values = []float64{notAvailableDouble}
+	} else if len(values) > size || 0 != size%len(values) {
+		return newError("data length %d differs from the size of the matrix, %dx%d", len(values), rows, columns)
+	}
+
+	matrix := InitializeMatrix(rows, columns)
+
+	for index := range matrix.Values {
+		if byRow {
+			matrix.set(index/columns, index%columns, values[index%len(values)])
+		} else {
+			matrix.Values[index] = values[index%len(values)]
+		}
+	}
+
+	return matrix
+}
+
+// dimBuiltin : `dim(x)` gives the number of rows and columns of a matrix or a data frame, NULL for anything else
+func dimBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1", len(parameters))
+	}
+
+	rows, columns := 0, 0
+
+	switch x := parameters[0].(type) {
+	case *Matrix:
+		rows, columns = x.Rows, x.Columns
+	case *DataFrame:
+		rows, columns = x.NumberOfRows(), len(x.Columns)
+	default:
+		return nil
+	}
+
+	return &Array{
+		Elements: []Object{
+			&Integer{
+				Value: int64(rows),
+			},
+			&Integer{
+				Value: int64(columns),
+			},
+		},
+	}
+}
+
+// tBuiltin : `t(x)`, a vector becoming a single row
+func tBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1", len(parameters))
+	}
+
+	matrix, err := asMatrix("x", "t", parameters[0])
+
+	if nil != err {
+		return err
+	}
+
+	return matrix.Transpose()
+}
+
+// diagBuiltin : `diag(x)` gives the diagonal of a matrix, the identity matrix of size x for a number, or the matrix
+// having the vector x as its diagonal
+func diagBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1", len(parameters))
+	}
+
+	switch x := parameters[0].(type) {
+	case *Matrix:
+		positions := []int{}
+
+		for index := 0; index < x.Rows && index < x.Columns; index++ {
+			positions = append(positions, index+index*x.Rows)
+		}
+
+		return x.vector(positions)
+	case *Integer, *Double:
+		size, err := dimensionParameter("x", "diag", x)
+
+		if nil != err {
+			return err
+		}
+
+		return identity(size)
+	}
+
+	values, err := matrixData("x", "diag", parameters[0])
+
+	if nil != err {
+		return err
+	}
+
+	diagonal := InitializeMatrix(len(values), len(values))
+
+	for index, value := range values {
+		diagonal.set(index, index, value)
+	}
+
+	return diagonal
+}
+
+// decompose : the LU factorization with partial pivoting of a square matrix, as LAPACK's dgetf2 computes it, both
+// factors sharing a single matrix and the row swapped at every step kept in pivots
+func (m *Matrix) decompose() (*Matrix, []int, *Error) {
+	size := m.Rows
+	lu := &Matrix{
+		Rows:    size,
+		Columns: size,
+		Values:  append([]float64{}, m.Values...),
+	}
+	pivots := make([]int, size)
+
+	for step := 0; step < size; step++ {
+		pivot := step
+
+		for row := step + 1; row < size; row++ {
+			if math.Abs(lu.at(row, step)) > math.Abs(lu.at(pivot, step)) {
+				pivot = row
+			}
+		}
+
+		pivots[step] = pivot
+
+		if 0 == lu.at(pivot, step) {
+			return nil, nil, newError("system is exactly singular: U[%d,%d] = 0", step, step)
+		}
+
+		if pivot != step {
+			for column := 0; column < size; column++ {
+				value := lu.at(step, column)
+				lu.set(step, column, lu.at(pivot, column))
+				lu.set(pivot, column, value)
+			}
+		}
+
+		divisor := lu.at(step, step)
+
+		for row := step + 1; row < size; row++ {
+			if math.Abs(divisor) >= smallestNormal {
+				lu.set(row, step, lu.at(row, step)*(1/divisor))
+			} else {
+				lu.set(row, step, lu.at(row, step)/divisor)
+			}
+		}
+
+		for column := step + 1; column < size; column++ {
+			factor := lu.at(step, column)
+
+			if 0 == factor {
+				continue
+			}
+
+			for row := step + 1; row < size; row++ {
+				lu.set(row, column, lu.at(row, column)-lu.at(row, step)*factor)
+			}
+		}
+	}
+
+	return lu, pivots, nil
+}
+
+// solveFactored : solves the system for every column of b, given the factorization of its matrix
+func (lu *Matrix) solveFactored(pivots []int, b *Matrix) *Matrix {
+	x := &Matrix{
+		Rows:    b.Rows,
+		Columns: b.Columns,
+		Values:  append([]float64{}, b.Values...),
+	}
+
+	for column := 0; column < x.Columns; column++ {
+		for row, pivot := range pivots {
+			value := x.at(row, column)
+			x.set(row, column, x.at(pivot, column))
+			x.set(pivot, column, value)
+		}
+
+		for step := 0; step < lu.Rows; step++ {
+			if value := x.at(step, column); 0 != value {
+				for row := step + 1; row < lu.Rows; row++ {
+					x.set(row, column, x.at(row, column)-value*lu.at(row, step))
+				}
+			}
+		}
+
+		for step := lu.Rows - 1; 0 <= step; step-- {
+			if 0 == x.at(step, column) {
+				continue
+			}
+
+			x.set(step, column, x.at(step, column)/lu.at(step, step))
+			value := x.at(step, column)
+
+			for row := 0; row < step; row++ {
+				x.set(row, column, x.at(row, column)-value*lu.at(row, step))
+			}
+		}
+	}
+
+	return x
+}
+
+// norm : the 1-norm, the largest sum of the absolute values of a column
+func (m *Matrix) norm() float64 {
+	norm := 0.0
+
+	for column := 0; column < m.Columns; column++ {
+		sum := 0.0
+
+		for row := 0; row < m.Rows; row++ {
+			sum += math.Abs(m.at(row, column))
+		}
+
+		norm = math.Max(norm, sum)
+	}
+
+	return norm
+}
+
+// identity :
+func identity(size int) *Matrix {
+	matrix := InitializeMatrix(size, size)
+
+	for index := 0; index < size; index++ {
+		matrix.set(index, index, 1)
+	}
+
+	return matrix
+}
+
+// solveBuiltin : `solve(a, b)` solves a %*% x = b, giving the inverse of a when b is left out; just like R, the system
+// is refused when a is too close to being singular, its reciprocal condition number being below the machine epsilon
+func solveBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 > len(parameters) || 2 < len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1 or 2", len(parameters))
+	}
+
+	a, err := asMatrix("a", "solve", parameters[0])
+
+	if nil != err {
+		return err
+	}
+
+	if a.Rows != a.Columns {
+		return newError("a to `solve` must be square, got %s", a.Dimensions())
+	}
+
+	lu, pivots, err := a.decompose()
+
+	if nil != err {
+		return err
+	}
+
+	inverse := lu.solveFactored(pivots, identity(a.Rows))
+
+	if condition := 1 / (a.norm() * inverse.norm()); condition < epsilon {
+		return newError("system is computationally singular: reciprocal condition number = %.6g", condition)
+	}
+
+	if 1 == len(parameters) {
+		return inverse
+	}
+
+	b, err := asMatrix("b", "solve", parameters[1])
+
+	if nil != err {
+		return err
+	}
+
+	if b.Rows != a.Rows {
+		return newError("b to `solve` must have %d rows, got %s", a.Rows, b.Dimensions())
+	}
+
+	x := lu.solveFactored(pivots, b)
+
+	if _, ok := parameters[1].(*Matrix); !ok {
+		return x.Column(0)
+	}
+
+	return x
+}
+
+// simplify : just like R, results of the same length are put together, as a vector when they are single numbers
+// and as the columns of a matrix otherwise; anything else is kept as it is
+func simplify(results []Object) Object {
+	length := -1
+	values := []float64{}
+
+	for _, result := range results {
+		data, err := matrixData("result", "apply", result)
+
+		if nil != err || (-1 != length && len(data) != length) {
+			return &Array{
+				Elements: results,
+			}
+		}
+
+		length = len(data)
+		values = append(values, data...)
+	}
+
+	if 1 >= length {
+		return &Array{
+			Elements: results,
+		}
+	}
+
+	return &Matrix{
+		Rows:    length,
+		Columns: len(results),
+		Values:  values,
+	}
+}
+
+// applyBuiltin : `apply(m, margin, f)` calls f with every row, when margin is 1, or every column, when it is 2
+func applyBuiltin(context CallContext, parameters ...Object) Object {
+	if 3 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=3", len(parameters))
+	}
+
+	matrix, ok := parameters[0].(*Matrix)
+
+	if !ok {
+		return newError("x to `apply` must be MATRIX, got %s", parameters[0].Type())
+	}
+
+	margin, ok := parameters[1].(*Integer)
+
+	if !ok || (1 != margin.Value && 2 != margin.Value) {
+		return newError("margin to `apply` must be 1, for rows, or 2, for columns, got %s", parameters[1].Inspect())
+	}
+
+	slices := []*Array{}
+
+	if 1 == margin.Value {
+		for row := 0; row < matrix.Rows; row++ {
+			slices = append(slices, matrix.Row(row))
+		}
+	} else {
+		for column := 0; column < matrix.Columns; column++ {
+			slices = append(slices, matrix.Column(column))
+		}
+	}
+
+	results := make([]Object, len(slices))
+
+	for index, slice := range slices {
+		result, err := context.Call(parameters[2], slice)
+
+		if nil != err {
+			return err
+		}
+
+		results[index] = result
+	}
+
+	return simplify(results)
+}
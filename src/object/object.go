@@ -3,6 +3,9 @@ package object
 import (
 	"bytes"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
 	"strings"
 
 	"../ast"
@@ -12,13 +15,38 @@ import (
 // ObjectType :
 type ObjectType string
 
+// Position : where a call is in the source code, the zero value meaning it is unknown
+type Position struct {
+	Line   int
+	Column int
+}
+
+// CallContext : handed by the evaluator and the virtual machine to every builtin they call
+type CallContext interface {
+	// Call : runs a function -- closure, function or builtin -- given to the builtin, failing with the error it raised
+	Call(function Object, arguments ...Object) (Object, *Error)
+	// CallNamed : just like Call, names being those given to the arguments, nil when they are all given by position
+	CallNamed(function Object, names []string, arguments []Object) (Object, *Error)
+	// Position : where the builtin was called from
+	Position() Position
+	// Function : the function bound to name where the builtin was called from, nil when there is none
+	Function(name string) Object
+	// Random : the generator of the running evaluator or virtual machine
+	Random() *Random
+	// Signal : hands the condition to the handlers established by tryCatch and withCallingHandlers, giving the error
+	// ending the builtin when the condition is an error or a tryCatch handles it, nil when the builtin goes on
+	Signal(condition *Condition) *Error
+}
+
 // BuiltinFunction :
-type BuiltinFunction func(arguments ...Object) Object
+type BuiltinFunction func(context CallContext, arguments ...Object) Object
 
 const (
 	INTEGER_OBJECT           = "INTEGER"
+	DOUBLE_OBJECT            = "DOUBLE"
 	BOOLEAN_OBJECT           = "BOOLEAN"
 	NULL_OBJECT              = "NULL"
+	NOT_AVAILABLE_OBJECT     = "NA"
 	RETURN_VALUE_OBJECT      = "RETURN_VALUE"
 	ERROR_OBJECT             = "ERROR"
 	FUNCTION_OBJECT          = "FUNCTION"
@@ -28,6 +56,11 @@ const (
 	COMPILED_FUNCTION_OBJECT = "COMPILED_FUNCTION_OBJECT"
 	CLOSURE_OBJECT           = "CLOSURE_OBJECT"
 	POINT_FREE_OBJECT        = "POINT_FREE_OBJECT"
+	HASH_OBJECT              = "HASH"
+	DATA_FRAME_OBJECT        = "DATA_FRAME"
+	MATRIX_OBJECT            = "MATRIX"
+	LIST_OBJECT              = "LIST"
+	CONDITION_OBJECT         = "CONDITION"
 )
 
 // Object :
@@ -41,6 +74,11 @@ type Integer struct {
 	Value int64
 }
 
+// Double :
+type Double struct {
+	Value float64
+}
+
 // Boolean :
 type Boolean struct {
 	Value bool
@@ -49,19 +87,30 @@ type Boolean struct {
 // Null :
 type Null struct{}
 
+// NotAvailable : R's NA, a missing value that, unlike NULL, still takes a place inside a vector
+type NotAvailable struct{}
+
+// NA : there is no need for more than a single missing value
+var NA = &NotAvailable{}
+
 // ReturnValue :
 type ReturnValue struct {
 	Value Object
 }
 
-// Error :
+// Error : Condition is the condition the error was signalled with, by stop, or by warning and message when a
+// tryCatch handles them -- the evaluation then unwinds to it just as it does for errors
 type Error struct {
-	Message string
+	Message   string
+	Position  Position
+	Condition *Condition
 }
 
 // Function :
 type Function struct {
-	Parameters  []*ast.Identifier
+	Parameters []*ast.Identifier
+	// Defaults holds the expression giving the default value of every parameter, nil for those without one
+	Defaults    []ast.Expression
 	Body        *ast.BlockStatement
 	Environment *Environment
 	Name        string
@@ -72,9 +121,13 @@ type String struct {
 	Value string
 }
 
-// Builtin :
+// Builtin : Parameters, when given, are the names of the parameters, in order, so it can take named arguments;
+// Signature, when given, is what a declaration of the builtin would tell after its name, as `<T>(xs: T[]): T`, for
+// the checker to check its calls against
 type Builtin struct {
-	Fn BuiltinFunction
+	Fn         BuiltinFunction
+	Parameters []string
+	Signature  string
 }
 
 // Array :
@@ -87,6 +140,17 @@ type CompiledFunction struct {
 	Instructions       code.Instructions
 	NumberOfLocals     int
 	NumberOfParameters int
+	// the names of the parameters, for the named arguments, and whether each one has a default value, computed by
+	// the function itself whenever the argument is left out; a Variadic function takes `...`, whose arguments are
+	// packed into a LIST held by its parameter
+	Parameters []string
+	Defaults   []bool
+	Variadic   bool
+	// where each call instruction comes from, indexed by its position in Instructions
+	Positions map[int]Position
+	// the indices of the locals and of the free variables by their names, so do.call finds a function by its name
+	Locals        map[string]int
+	FreeVariables map[string]int
 }
 
 // Closure :
@@ -100,6 +164,34 @@ type PointFree struct {
 	Functions []Object
 }
 
+// HashKey : identifies a value inside a Hash; equal values share the same key
+type HashKey struct {
+	Type  ObjectType
+	Value uint64
+}
+
+// Hashable : objects that can be used as keys of a Hash
+type Hashable interface {
+	HashKey() HashKey
+}
+
+// HashPair : keeps the original key so it can be retrieved by `keys`
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+// Hash : Order keeps the insertion order of the keys, so `keys` and `values` are deterministic
+type Hash struct {
+	Pairs map[HashKey]HashPair
+	Order []HashKey
+}
+
+// String :
+func (p Position) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
+}
+
 // Inspect :
 func (i *Integer) Inspect() string {
 	return fmt.Sprintf("%d", i.Value)
@@ -110,6 +202,32 @@ func (i *Integer) Type() ObjectType {
 	return INTEGER_OBJECT
 }
 
+// HashKey :
+func (i *Integer) HashKey() HashKey {
+	return HashKey{
+		Type:  i.Type(),
+		Value: uint64(i.Value),
+	}
+}
+
+// Inspect : just like R, only seven significant digits are shown
+func (d *Double) Inspect() string {
+	return strconv.FormatFloat(d.Value, 'g', 7, 64)
+}
+
+// Type :
+func (d *Double) Type() ObjectType {
+	return DOUBLE_OBJECT
+}
+
+// HashKey :
+func (d *Double) HashKey() HashKey {
+	return HashKey{
+		Type:  d.Type(),
+		Value: math.Float64bits(d.Value),
+	}
+}
+
 // Inspect :
 func (b *Boolean) Inspect() string {
 	if b.Value {
@@ -124,6 +242,20 @@ func (b *Boolean) Type() ObjectType {
 	return BOOLEAN_OBJECT
 }
 
+// HashKey :
+func (b *Boolean) HashKey() HashKey {
+	var value uint64
+
+	if b.Value {
+		value = 1
+	}
+
+	return HashKey{
+		Type:  b.Type(),
+		Value: value,
+	}
+}
+
 // Inspect :
 func (n *Null) Inspect() string {
 	return "NULL"
@@ -134,6 +266,16 @@ func (n *Null) Type() ObjectType {
 	return NULL_OBJECT
 }
 
+// Inspect :
+func (na *NotAvailable) Inspect() string {
+	return "NA"
+}
+
+// Type :
+func (na *NotAvailable) Type() ObjectType {
+	return NOT_AVAILABLE_OBJECT
+}
+
 // Type :
 func (rv *ReturnValue) Type() ObjectType {
 	return RETURN_VALUE_OBJECT
@@ -149,9 +291,13 @@ func (e *Error) Type() ObjectType {
 	return ERROR_OBJECT
 }
 
-// Inspect :
+// Inspect : the position is shown when the error is known to come from a call
 func (e *Error) Inspect() string {
-	return "[ERROR]: " + e.Message
+	if 0 == e.Position.Line {
+		return "[ERROR]: " + e.Message
+	}
+
+	return fmt.Sprintf("[ERROR]: %s, at %s", e.Message, e.Position)
 }
 
 // Type :
@@ -164,8 +310,12 @@ func (f *Function) Inspect() string {
 	var out bytes.Buffer
 	parameters := []string{}
 
-	for _, parameter := range f.Parameters {
-		parameters = append(parameters, parameter.String())
+	for index, parameter := range f.Parameters {
+		if index < len(f.Defaults) && nil != f.Defaults[index] {
+			parameters = append(parameters, parameter.String()+" = "+f.Defaults[index].String())
+		} else {
+			parameters = append(parameters, parameter.String())
+		}
 	}
 
 	if "" != f.Name {
@@ -194,6 +344,17 @@ func (s *String) Inspect() string {
 	return s.Value
 }
 
+// HashKey :
+func (s *String) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(s.Value))
+
+	return HashKey{
+		Type:  s.Type(),
+		Value: h.Sum64(),
+	}
+}
+
 // Type :
 func (b *Builtin) Type() ObjectType {
 	return BUILTIN_OBJECT
@@ -255,3 +416,71 @@ func (pf *PointFree) Type() ObjectType {
 func (pf *PointFree) Inspect() string {
 	return fmt.Sprintf("Point Free[%p]", pf)
 }
+
+// Type :
+func (h *Hash) Type() ObjectType {
+	return HASH_OBJECT
+}
+
+// Inspect :
+func (h *Hash) Inspect() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+
+	for _, key := range h.Order {
+		pair := h.Pairs[key]
+		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// Get :
+func (h *Hash) Get(key Hashable) (Object, bool) {
+	pair, ok := h.Pairs[key.HashKey()]
+
+	if !ok {
+		return nil, false
+	}
+
+	return pair.Value, true
+}
+
+// Set : only meant to be used while building a new Hash, the language itself never mutates one
+func (h *Hash) Set(key Object, value Object) {
+	hashKey := key.(Hashable).HashKey()
+
+	if _, ok := h.Pairs[hashKey]; !ok {
+		h.Order = append(h.Order, hashKey)
+	}
+
+	h.Pairs[hashKey] = HashPair{
+		Key:   key,
+		Value: value,
+	}
+}
+
+// Copy :
+func (h *Hash) Copy() *Hash {
+	hash := InitializeHash()
+
+	for _, key := range h.Order {
+		pair := h.Pairs[key]
+		hash.Set(pair.Key, pair.Value)
+	}
+
+	return hash
+}
+
+// InitializeHash :
+func InitializeHash() *Hash {
+	return &Hash{
+		Pairs: make(map[HashKey]HashPair),
+		Order: []HashKey{},
+	}
+}
@@ -0,0 +1,672 @@
+package object
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	mersenneN         = 624
+	mersenneM         = 397
+	mersenneUpperMask = 0x80000000
+	mersenneLowerMask = 0x7fffffff
+	mersenneMatrixA   = 0x9908b0df
+	// 2^-32, to go from an unsigned integer to [0, 1)
+	mersenneScale = 2.3283064365386963e-10
+	// 1 / (2^32 - 1), keeping uniforms away from 0 and 1
+	uniformFixup = 2.328306437080797e-10
+	// 2^27, as a single uniform is not precise enough for the inversion of the normal distribution
+	inversionBig = 134217728
+)
+
+// Random : R's default generator, Mersenne-Twister for uniforms and Inversion for normals, so a seed gives the very same
+// numbers GNU R does; every engine keeps its own, so concurrent runs do not interfere
+type Random struct {
+	state  [mersenneN]uint32
+	index  int
+	seeded bool
+}
+
+// InitializeRandom : just like R, an unseeded generator is seeded from the clock when first used
+func InitializeRandom() *Random {
+	return &Random{}
+}
+
+// Seed : R's `set.seed`, scrambling the seed through a linear congruential generator to fill the state
+func (r *Random) Seed(seed uint32) {
+	for index := 0; index < 50; index++ {
+		seed = 69069*seed + 1
+	}
+
+	for index := 0; index < mersenneN+1; index++ {
+		seed = 69069*seed + 1
+
+		// the first value is where R keeps the index, which is reset right after
+		if 0 < index {
+			r.state[index-1] = seed
+		}
+	}
+
+	r.index = mersenneN
+	r.seeded = true
+}
+
+// next : Mersenne-Twister's tempered output
+func (r *Random) next() uint32 {
+	if !r.seeded {
+		r.Seed(uint32(time.Now().UnixNano()))
+	}
+
+	magnitudes := [2]uint32{0, mersenneMatrixA}
+
+	if r.index >= mersenneN {
+		var y uint32
+		kk := 0
+
+		for ; kk < mersenneN-mersenneM; kk++ {
+			y = (r.state[kk] & mersenneUpperMask) | (r.state[kk+1] & mersenneLowerMask)
+			r.state[kk] = r.state[kk+mersenneM] ^ (y >> 1) ^ magnitudes[y&1]
+		}
+
+		for ; kk < mersenneN-1; kk++ {
+			y = (r.state[kk] & mersenneUpperMask) | (r.state[kk+1] & mersenneLowerMask)
+			r.state[kk] = r.state[kk+mersenneM-mersenneN] ^ (y >> 1) ^ magnitudes[y&1]
+		}
+
+		y = (r.state[mersenneN-1] & mersenneUpperMask) | (r.state[0] & mersenneLowerMask)
+		r.state[mersenneN-1] = r.state[mersenneM-1] ^ (y >> 1) ^ magnitudes[y&1]
+		r.index = 0
+	}
+
+	y := r.state[r.index]
+	r.index++
+
+	y ^= y >> 11
+	y ^= (y << 7) & 0x9d2c5680
+	y ^= (y << 15) & 0xefc60000
+	y ^= y >> 18
+
+	return y
+}
+
+// Uniform : R's unif_rand, always strictly between 0 and 1
+func (r *Random) Uniform() float64 {
+	value := float64(r.next()) * mersenneScale
+
+	switch {
+	case 0 >= value:
+		return 0.5 * uniformFixup
+	case 0 >= 1-value:
+		return 1 - 0.5*uniformFixup
+	}
+
+	return value
+}
+
+// Normal : R's norm_rand with the Inversion method
+func (r *Random) Normal() float64 {
+	u := r.Uniform()
+	u = float64(int(inversionBig*u)) + r.Uniform()
+
+	return qnorm(u / inversionBig)
+}
+
+// bits : a random integer with the given number of bits, built out of 16 bits at a time
+func (r *Random) bits(count int) float64 {
+	var value int64
+
+	for n := 0; n <= count; n += 16 {
+		value = 65536*value + int64(math.Floor(r.Uniform()*65536))
+	}
+
+	return float64(value & (int64(1)<<uint(count) - 1))
+}
+
+// Index : R's R_unif_index with the default "Rejection" sample kind, an integer in [0, n)
+func (r *Random) Index(n float64) float64 {
+	if 0 >= n {
+		return 0
+	}
+
+	count := int(math.Ceil(math.Log2(n)))
+
+	for {
+		if value := r.bits(count); value < n {
+			return value
+		}
+	}
+}
+
+// qnorm : the quantile of the standard normal distribution, Wichura's AS241 as in R
+func qnorm(p float64) float64 {
+	q := p - 0.5
+
+	if math.Abs(q) <= 0.425 {
+		r := 0.180625 - q*q
+
+		return q * (((((((r*2509.0809287301226727+
+			33430.575583588128105)*r+67265.770927008700853)*r+
+			45921.953931549871457)*r+13731.693765509461125)*r+
+			1971.5909503065514427)*r+133.14166789178437745)*r +
+			3.387132872796366608) /
+			(((((((r*5226.495278852545925+
+				28729.085735721942674)*r+39307.89580009271061)*r+
+				21213.794301586595867)*r+5394.1960214247511077)*r+
+				687.1870074920579083)*r+42.313330701600911252)*r + 1)
+	}
+
+	r := p
+
+	if 0 <= q {
+		r = 1 - p
+	}
+
+	r = math.Sqrt(-math.Log(r))
+
+	var value float64
+
+	if r <= 5 {
+		r -= 1.6
+		value = (((((((r*7.7454501427834140764e-4+
+			0.0227238449892691845833)*r+0.24178072517745061177)*
+			r+1.27045825245236838258)*r+
+			3.64784832476320460504)*r+5.7694972214606914055)*
+			r+4.6303378461565452959)*r +
+			1.42343711074968357734) /
+			(((((((r*
+				1.05075007164441684324e-9+5.475938084995344946e-4)*
+				r+0.0151986665636164571966)*r+
+				0.14810397642748007459)*r+0.68976733498510000455)*
+				r+1.6763848301838038494)*r+
+				2.05319162663775882187)*r + 1)
+	} else {
+		r -= 5
+		value = (((((((r*2.01033439929228813265e-7+
+			2.71155556874348757815e-5)*r+
+			0.0012426609473880784386)*r+0.026532189526576123093)*
+			r+0.29656057182850489123)*r+
+			1.7848265399172913358)*r+5.4637849111641143699)*
+			r + 6.6579046435011037772) /
+			(((((((r*
+				2.04426310338993978564e-15+1.4215117583164458887e-7)*
+				r+1.8463183175100546818e-5)*r+
+				7.868691311456132591e-4)*r+0.0148753612908506148525)*
+				r+0.13692988092273580531)*r+
+				0.59983220655588793769)*r + 1)
+	}
+
+	if 0 > q {
+		return -value
+	}
+
+	return value
+}
+
+// Binomial : R's rbinom, inverting the distribution function when n * p is below 30 and using Kachitvichyanukul and
+// Schmeiser's BTPE algorithm otherwise; n must be a non negative integer and p a probability
+func (r *Random) Binomial(n int, pp float64) int {
+	if 0 == n || 0 == pp {
+		return 0
+	}
+
+	if 1 == pp {
+		return n
+	}
+
+	p := math.Min(pp, 1-pp)
+	q := 1 - p
+	np := float64(n) * p
+	ratio := p / q
+	g := ratio * float64(n+1)
+	ix := 0
+
+	if np < 30 {
+		qn := powerDI(q, n)
+
+	small:
+		for {
+			ix = 0
+			f := qn
+			u := r.Uniform()
+
+			for {
+				if u < f {
+					break small
+				}
+
+				if ix > 110 {
+					break
+				}
+
+				u -= f
+				ix++
+				f *= g/float64(ix) - ratio
+			}
+		}
+	} else {
+		ix = r.btpe(initializeBTPE(n, p))
+	}
+
+	if pp > 0.5 {
+		return n - ix
+	}
+
+	return ix
+}
+
+// stirlingTail : the correction terms of de Moivre's formula
+func stirlingTail(value float64) float64 {
+	square := value * value
+
+	return (13860 - (462-(132-(99-140/square)/square)/square)/square) / value / 166320
+}
+
+// btpeSetup : what the BTPE algorithm works out of n and p, before drawing any value
+type btpeSetup struct {
+	n, m                                    int
+	p, q, g, ratio, fm, npq                 float64
+	p1, p2, p3, p4, xm, xl, xr, c, xll, xlr float64
+}
+
+// initializeBTPE : p is the smallest of the probability and its complement
+func initializeBTPE(n int, p float64) btpeSetup {
+	s := btpeSetup{
+		n: n,
+		p: p,
+		q: 1 - p,
+	}
+
+	np := float64(n) * p
+	s.ratio = p / s.q
+	s.g = s.ratio * float64(n+1)
+	s.fm = np + p
+	s.m = int(s.fm)
+	s.npq = np * s.q
+	s.p1 = float64(int(2.195*math.Sqrt(s.npq)-4.6*s.q)) + 0.5
+	s.xm = float64(s.m) + 0.5
+	s.xl = s.xm - s.p1
+	s.xr = s.xm + s.p1
+	s.c = 0.134 + 20.5/(15.3+float64(s.m))
+	al := (s.fm - s.xl) / (s.fm - s.xl*p)
+	s.xll = al * (1 + 0.5*al)
+	al = (s.xr - s.fm) / (s.xr * s.q)
+	s.xlr = al * (1 + 0.5*al)
+	s.p2 = s.p1 * (1 + s.c + s.c)
+	s.p3 = s.p2 + s.c/s.xll
+	s.p4 = s.p3 + s.c/s.xlr
+
+	return s
+}
+
+// btpe : the rejection part of the BTPE algorithm, for n * p of at least 30
+func (r *Random) btpe(s btpeSetup) int {
+	n, m, p, q, g, ratio, fm, npq := s.n, s.m, s.p, s.q, s.g, s.ratio, s.fm, s.npq
+	p1, p2, p3, p4, xm, xl, xr, c, xll, xlr := s.p1, s.p2, s.p3, s.p4, s.xm, s.xl, s.xr, s.c, s.xll, s.xlr
+
+	for {
+		u := r.Uniform() * p4
+		v := r.Uniform()
+		var ix int
+
+		// triangular region
+		if u <= p1 {
+			return int(xm - p1*v + u)
+		}
+
+		if u <= p2 {
+			// parallelogram region
+			x := xl + (u-p1)/c
+			v = v*c + 1 - math.Abs(xm-x)/p1
+
+			if v > 1 || v <= 0 {
+				continue
+			}
+
+			ix = int(x)
+		} else if u > p3 {
+			// right tail
+			ix = int(xr - math.Log(v)/xlr)
+
+			if ix > n {
+				continue
+			}
+
+			v = v * (u - p3) * xlr
+		} else {
+			// left tail
+			ix = int(xl + math.Log(v)/xll)
+
+			if ix < 0 {
+				continue
+			}
+
+			v = v * (u - p2) * xll
+		}
+
+		k := ix - m
+
+		if 0 > k {
+			k = -k
+		}
+
+		if k <= 20 || float64(k) >= npq/2-1 {
+			// explicit evaluation
+			f := 1.0
+
+			if m < ix {
+				for i := m + 1; i <= ix; i++ {
+					f *= g/float64(i) - ratio
+				}
+			} else if m > ix {
+				for i := ix + 1; i <= m; i++ {
+					f /= g/float64(i) - ratio
+				}
+			}
+
+			if v <= f {
+				return ix
+			}
+
+			continue
+		}
+
+		// squeezing using upper and lower bounds on log(f(x))
+		kf := float64(k)
+		amaxp := (kf / npq) * ((kf*(kf/3+0.625)+0.1666666666666)/npq + 0.5)
+		ynorm := -kf * kf / (2 * npq)
+		alv := math.Log(v)
+
+		if alv < ynorm-amaxp {
+			return ix
+		}
+
+		if alv <= ynorm+amaxp {
+			x1 := float64(ix + 1)
+			f1 := fm + 1
+			z := float64(n+1) - fm
+			w := float64(n-ix) + 1
+
+			bound := xm*math.Log(f1/x1) + (float64(n-m)+0.5)*math.Log(z/w) + float64(ix-m)*math.Log(w*p/(x1*q)) +
+				stirlingTail(f1) + stirlingTail(z) + stirlingTail(x1) + stirlingTail(w)
+
+			if alv <= bound {
+				return ix
+			}
+		}
+	}
+}
+
+// countParameter : how many values to draw; just like R, a vector asks for as many values as it holds
+func countParameter(function string, parameter Object) (int, *Error) {
+	switch parameter := parameter.(type) {
+	case *Array:
+		return len(parameter.Elements), nil
+	case *Integer:
+		if 0 <= parameter.Value {
+			return int(parameter.Value), nil
+		}
+	case *Double:
+		if 0 <= parameter.Value && !math.IsInf(parameter.Value, 0) {
+			return int(parameter.Value), nil
+		}
+	}
+
+	return 0, newError("n to `%s` must be a non negative number, got %s", function, parameter.Inspect())
+}
+
+// distributionParameters : n followed by the optional parameters of the distribution, which default to defaults
+func distributionParameters(function string, parameters []Object, names []string, defaults []float64) (int, []float64, *Error) {
+	if 1 > len(parameters) || 1+len(names) < len(parameters) {
+		return 0, nil, newError("wrong number of parameters, got=%d, want=1 to %d", len(parameters), 1+len(names))
+	}
+
+	count, err := countParameter(function, parameters[0])
+
+	if nil != err {
+		return 0, nil, err
+	}
+
+	values := append([]float64{}, defaults...)
+
+	for index, parameter := range parameters[1:] {
+		if !given(parameters, index+1) {
+			continue
+		}
+
+		if values[index], err = numericParameter(names[index], function, parameter); nil != err {
+			return 0, nil, err
+		}
+	}
+
+	return count, values, nil
+}
+
+// doubles :
+func doubles(count int, draw func() float64) Object {
+	elements := make([]Object, count)
+
+	for index := range elements {
+		elements[index] = &Double{
+			Value: draw(),
+		}
+	}
+
+	return &Array{
+		Elements: elements,
+	}
+}
+
+// setSeedBuiltin : `set.seed(seed)`
+func setSeedBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1", len(parameters))
+	}
+
+	seed, err := numericParameter("seed", "set.seed", parameters[0])
+
+	if nil != err {
+		return err
+	}
+
+	// R takes the seed as a signed integer, reading its bits as unsigned
+	context.Random().Seed(uint32(int32(seed)))
+
+	return nil
+}
+
+// runifBuiltin : `runif(n, min, max)`, between 0 and 1 unless given
+func runifBuiltin(context CallContext, parameters ...Object) Object {
+	count, bounds, err := distributionParameters("runif", parameters, []string{"min", "max"}, []float64{0, 1})
+
+	if nil != err {
+		return err
+	}
+
+	minimum, maximum := bounds[0], bounds[1]
+
+	if maximum < minimum || math.IsInf(minimum, 0) || math.IsInf(maximum, 0) {
+		return newError("invalid bounds to `runif`, min must not be greater than max and both must be finite")
+	}
+
+	random := context.Random()
+
+	return doubles(count, func() float64 {
+		if minimum == maximum {
+			return minimum
+		}
+
+		return minimum + (maximum-minimum)*random.Uniform()
+	})
+}
+
+// rnormBuiltin : `rnorm(n, mean, sd)`, the standard normal distribution unless given
+func rnormBuiltin(context CallContext, parameters ...Object) Object {
+	count, moments, err := distributionParameters("rnorm", parameters, []string{"mean", "sd"}, []float64{0, 1})
+
+	if nil != err {
+		return err
+	}
+
+	mean, sd := moments[0], moments[1]
+
+	if 0 > sd {
+		return newError("sd to `rnorm` must not be negative, got %s", parameters[2].Inspect())
+	}
+
+	random := context.Random()
+
+	return doubles(count, func() float64 {
+		if 0 == sd || math.IsInf(mean, 0) {
+			return mean
+		}
+
+		return mean + sd*random.Normal()
+	})
+}
+
+// rbinomBuiltin : `rbinom(n, size, prob)`
+func rbinomBuiltin(context CallContext, parameters ...Object) Object {
+	if 3 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=3", len(parameters))
+	}
+
+	count, values, err := distributionParameters("rbinom", parameters, []string{"size", "prob"}, []float64{0, 0})
+
+	if nil != err {
+		return err
+	}
+
+	size, probability := values[0], values[1]
+
+	if 0 > size || size != math.Floor(size) || math.MaxInt32 <= size {
+		return newError("size to `rbinom` must be a non negative integer, got %s", parameters[1].Inspect())
+	}
+
+	if math.IsNaN(probability) || 0 > probability || 1 < probability {
+		return newError("prob to `rbinom` must be between 0 and 1, got %s", parameters[2].Inspect())
+	}
+
+	random := context.Random()
+	elements := make([]Object, count)
+
+	for index := range elements {
+		elements[index] = &Integer{
+			Value: int64(random.Binomial(int(size), probability)),
+		}
+	}
+
+	return &Array{
+		Elements: elements,
+	}
+}
+
+// sampleIndexes : R's sample.int, giving 0-based indexes; without replacement, indexes are drawn from the ones left,
+// unless the population is so large R rejects the ones already drawn instead
+func sampleIndexes(random *Random, n int, size int, replace bool) []int {
+	indexes := make([]int, size)
+
+	switch {
+	case replace || 2 > size:
+		for index := range indexes {
+			indexes[index] = int(random.Index(float64(n)))
+		}
+	case 1e7 < n && size <= n/2:
+		drawn := map[int]bool{}
+
+		for index := 0; index < size; {
+			value := int(random.Index(float64(n)))
+
+			if !drawn[value] {
+				drawn[value] = true
+				indexes[index] = value
+				index++
+			}
+		}
+	default:
+		left := make([]int, n)
+
+		for index := range left {
+			left[index] = index
+		}
+
+		for index := range indexes {
+			position := int(random.Index(float64(n)))
+			indexes[index] = left[position]
+			n--
+			left[position] = left[n]
+		}
+	}
+
+	return indexes
+}
+
+// sampleBuiltin : `sample(x, size, replace)`; just like R, a single number n samples from 1 to n
+func sampleBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 > len(parameters) || 3 < len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1 to 3", len(parameters))
+	}
+
+	var population []Object
+
+	switch x := parameters[0].(type) {
+	case *Array:
+		population = x.Elements
+	case *Integer, *Double:
+		n := int(asDouble(x))
+
+		if 1 > asDouble(x) || math.IsInf(asDouble(x), 0) {
+			population = []Object{x}
+
+			break
+		}
+
+		population = make([]Object, 0, n)
+
+		for value := 1; value <= n; value++ {
+			population = append(population, &Integer{
+				Value: int64(value),
+			})
+		}
+	default:
+		return newError("x to `sample` must be a vector or a number, got %s", x.Type())
+	}
+
+	size := len(population)
+
+	if given(parameters, 1) {
+		var err *Error
+
+		if size, err = countParameter("sample", parameters[1]); nil != err {
+			return err
+		}
+	}
+
+	flags := map[string]bool{}
+
+	if 3 == len(parameters) {
+		var err *Error
+
+		if flags, err = logicalFlags("sample", parameters[2:], "replace"); nil != err {
+			return err
+		}
+	}
+
+	if !flags["replace"] && size > len(population) {
+		return newError("cannot take a sample larger than the population when replace is FALSE")
+	}
+
+	if 0 == len(population) && 0 < size {
+		return newError("cannot sample from an empty population")
+	}
+
+	indexes := sampleIndexes(context.Random(), len(population), size, flags["replace"])
+	elements := make([]Object, size)
+
+	for index, position := range indexes {
+		elements[index] = population[position]
+	}
+
+	return &Array{
+		Elements: elements,
+	}
+}
@@ -0,0 +1,378 @@
+package object
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+)
+
+// logicalFlags : the optional logical parameters, given in the very same order R takes them
+func logicalFlags(function string, parameters []Object, names ...string) (map[string]bool, *Error) {
+	if len(parameters) > len(names) {
+		return nil, newError("too many parameters to `%s`, got %d flags, want at most %d", function, len(parameters), len(names))
+	}
+
+	flags := map[string]bool{}
+
+	for index, parameter := range parameters {
+		if !given(parameters, index) {
+			continue
+		}
+
+		flag, ok := parameter.(*Boolean)
+
+		if !ok {
+			return nil, newError("%s to `%s` must be BOOLEAN, got %s", names[index], function, parameter.Type())
+		}
+
+		flags[names[index]] = flag.Value
+	}
+
+	return flags, nil
+}
+
+// compilePattern : Go's RE2 syntax is used both for R's default extended expressions and for `perl = TRUE`, so
+// lookarounds and backreferences are not supported; `fixed = TRUE` matches the pattern literally
+func compilePattern(function string, parameter Object, flags map[string]bool) (*regexp.Regexp, *Error) {
+	pattern, ok := parameter.(*String)
+
+	if !ok {
+		return nil, newError("pattern to `%s` must be STRING, got %s", function, parameter.Type())
+	}
+
+	expression := pattern.Value
+
+	if flags["fixed"] {
+		expression = regexp.QuoteMeta(expression)
+	}
+
+	if flags["ignore.case"] {
+		expression = "(?i)" + expression
+	}
+
+	compiled, err := regexp.Compile(expression)
+
+	if nil != err {
+		reason := err.Error()
+
+		if syntaxError, ok := err.(*syntax.Error); ok {
+			reason = string(syntaxError.Code)
+		}
+
+		return nil, newError("invalid regular expression '%s', reason '%s'", pattern.Value, reason)
+	}
+
+	return compiled, nil
+}
+
+// replacementTemplate : R refers to groups as \1, while Go expects ${1}
+func replacementTemplate(replacement string, fixed bool) string {
+	if fixed {
+		return strings.Replace(replacement, "$", "$$", -1)
+	}
+
+	var out strings.Builder
+
+	for index := 0; index < len(replacement); index++ {
+		char := replacement[index]
+
+		switch {
+		case '\\' == char && index+1 < len(replacement) && '0' <= replacement[index+1] && replacement[index+1] <= '9':
+			out.WriteString("${" + string(replacement[index+1]) + "}")
+			index++
+		case '\\' == char && index+1 < len(replacement):
+			out.WriteByte(replacement[index+1])
+			index++
+		case '$' == char:
+			out.WriteString("$$")
+		default:
+			out.WriteByte(char)
+		}
+	}
+
+	return out.String()
+}
+
+// textParameter : x may be a single string or a vector of them
+func textParameter(function string, parameter Object) ([]Object, bool, *Error) {
+	vectors, _, vector, err := vectorParameters(function, []Object{parameter})
+
+	if nil != err {
+		return nil, false, err
+	}
+
+	return vectors[0], vector, nil
+}
+
+// grepBuiltin : `grep(pattern, x, ignore.case, perl, value, fixed)` gives the 0-based indexes of the matching elements,
+// or the elements themselves when value is set
+func grepBuiltin(context CallContext, parameters ...Object) Object {
+	if 2 > len(parameters) {
+		return newError("wrong number of parameters, got=%d, want at least 2", len(parameters))
+	}
+
+	flags, err := logicalFlags("grep", parameters[2:], "ignore.case", "perl", "value", "fixed")
+
+	if nil != err {
+		return err
+	}
+
+	re, err := compilePattern("grep", parameters[0], flags)
+
+	if nil != err {
+		return err
+	}
+
+	elements, _, err := textParameter("grep", parameters[1])
+
+	if nil != err {
+		return err
+	}
+
+	results := []Object{}
+
+	for index, element := range elements {
+		text, ok := asCharacter(element)
+
+		if !ok || !re.MatchString(text) {
+			continue
+		}
+
+		if flags["value"] {
+			results = append(results, element)
+		} else {
+			results = append(results, &Integer{
+				Value: int64(index),
+			})
+		}
+	}
+
+	return &Array{
+		Elements: results,
+	}
+}
+
+// greplBuiltin : `grepl(pattern, x, ignore.case, perl, fixed)`
+func greplBuiltin(context CallContext, parameters ...Object) Object {
+	if 2 > len(parameters) {
+		return newError("wrong number of parameters, got=%d, want at least 2", len(parameters))
+	}
+
+	flags, err := logicalFlags("grepl", parameters[2:], "ignore.case", "perl", "fixed")
+
+	if nil != err {
+		return err
+	}
+
+	re, err := compilePattern("grepl", parameters[0], flags)
+
+	if nil != err {
+		return err
+	}
+
+	elements, vector, err := textParameter("grepl", parameters[1])
+
+	if nil != err {
+		return err
+	}
+
+	results := make([]Object, len(elements))
+
+	for index, element := range elements {
+		text, ok := asCharacter(element)
+
+		// just like R, missing values never match
+		results[index] = &Boolean{
+			Value: ok && re.MatchString(text),
+		}
+	}
+
+	return vectorResult(results, vector)
+}
+
+// substitute : sub and gsub only differ on how many matches are replaced
+func substitute(function string, all bool, parameters []Object) Object {
+	if 3 > len(parameters) {
+		return newError("wrong number of parameters, got=%d, want at least 3", len(parameters))
+	}
+
+	flags, err := logicalFlags(function, parameters[3:], "ignore.case", "perl", "fixed")
+
+	if nil != err {
+		return err
+	}
+
+	re, err := compilePattern(function, parameters[0], flags)
+
+	if nil != err {
+		return err
+	}
+
+	replacement, ok := parameters[1].(*String)
+
+	if !ok {
+		return newError("replacement to `%s` must be STRING, got %s", function, parameters[1].Type())
+	}
+
+	template := replacementTemplate(replacement.Value, flags["fixed"])
+	elements, vector, err := textParameter(function, parameters[2])
+
+	if nil != err {
+		return err
+	}
+
+	results := make([]Object, len(elements))
+
+	for index, element := range elements {
+		text, ok := asCharacter(element)
+
+		if !ok {
+			results[index] = NA
+
+			continue
+		}
+
+		if all {
+			text = re.ReplaceAllString(text, template)
+		} else if match := re.FindStringSubmatchIndex(text); nil != match {
+			text = text[:match[0]] + string(re.ExpandString(nil, template, text, match)) + text[match[1]:]
+		}
+
+		results[index] = &String{
+			Value: text,
+		}
+	}
+
+	return vectorResult(results, vector)
+}
+
+// subBuiltin : `sub(pattern, replacement, x, ignore.case, perl, fixed)` replaces the first match
+func subBuiltin(context CallContext, parameters ...Object) Object {
+	return substitute("sub", false, parameters)
+}
+
+// gsubBuiltin : `gsub(pattern, replacement, x, ignore.case, perl, fixed)` replaces every match
+func gsubBuiltin(context CallContext, parameters ...Object) Object {
+	return substitute("gsub", true, parameters)
+}
+
+// regexprBuiltin : `regexpr(pattern, text, ignore.case, perl, fixed)` gives a data frame with the 0-based start and the
+// length, in characters, of the first match of each element; -1 when there is none, just like R
+func regexprBuiltin(context CallContext, parameters ...Object) Object {
+	if 2 > len(parameters) {
+		return newError("wrong number of parameters, got=%d, want at least 2", len(parameters))
+	}
+
+	flags, err := logicalFlags("regexpr", parameters[2:], "ignore.case", "perl", "fixed")
+
+	if nil != err {
+		return err
+	}
+
+	re, err := compilePattern("regexpr", parameters[0], flags)
+
+	if nil != err {
+		return err
+	}
+
+	elements, _, err := textParameter("regexpr", parameters[1])
+
+	if nil != err {
+		return err
+	}
+
+	starts := make([]Object, len(elements))
+	lengths := make([]Object, len(elements))
+
+	for index, element := range elements {
+		text, ok := asCharacter(element)
+
+		if !ok {
+			starts[index], lengths[index] = NA, NA
+
+			continue
+		}
+
+		start, length := -1, -1
+
+		if match := re.FindStringIndex(text); nil != match {
+			start = utf8.RuneCountInString(text[:match[0]])
+			length = utf8.RuneCountInString(text[match[0]:match[1]])
+		}
+
+		starts[index] = &Integer{
+			Value: int64(start),
+		}
+		lengths[index] = &Integer{
+			Value: int64(length),
+		}
+	}
+
+	frame, err := InitializeDataFrame([]string{"start", "length"}, []*Array{
+		{
+			Elements: starts,
+		},
+		{
+			Elements: lengths,
+		},
+	})
+
+	if nil != err {
+		return err
+	}
+
+	return frame
+}
+
+// regmatchesBuiltin : `regmatches(x, m)` extracts the matches found by `regexpr`, leaving out the elements without one
+func regmatchesBuiltin(context CallContext, parameters ...Object) Object {
+	if 2 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=2", len(parameters))
+	}
+
+	elements, _, err := textParameter("regmatches", parameters[0])
+
+	if nil != err {
+		return err
+	}
+
+	frame, ok := parameters[1].(*DataFrame)
+
+	if !ok {
+		return newError("m to `regmatches` must be the result of `regexpr`, got %s", parameters[1].Type())
+	}
+
+	starts, _ := frame.Index(&String{
+		Value: "start",
+	}).(*Array)
+	lengths, _ := frame.Index(&String{
+		Value: "length",
+	}).(*Array)
+
+	if nil == starts || nil == lengths || len(starts.Elements) != len(elements) {
+		return newError("m to `regmatches` must be the result of `regexpr` over x")
+	}
+
+	results := []Object{}
+
+	for index, element := range elements {
+		text, ok := asCharacter(element)
+		start, found := starts.Elements[index].(*Integer)
+
+		if !ok || !found || -1 == start.Value {
+			continue
+		}
+
+		runes := []rune(text)
+		length := lengths.Elements[index].(*Integer).Value
+
+		results = append(results, &String{
+			Value: string(runes[start.Value : start.Value+length]),
+		})
+	}
+
+	return &Array{
+		Elements: results,
+	}
+}
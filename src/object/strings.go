@@ -0,0 +1,629 @@
+package object
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// asCharacter : R's `as.character`, doubles keeping fifteen significant digits; false for missing values
+func asCharacter(obj Object) (string, bool) {
+	switch obj := obj.(type) {
+	case *String:
+		return obj.Value, true
+	case *Double:
+		return strconv.FormatFloat(obj.Value, 'g', 15, 64), true
+	case *Integer, *Boolean:
+		return obj.Inspect(), true
+	default:
+		return "", false
+	}
+}
+
+// isAtomic : the values a character function accepts, vectors of them included
+func isAtomic(obj Object) bool {
+	switch obj.Type() {
+	case STRING_OBJECT, INTEGER_OBJECT, DOUBLE_OBJECT, BOOLEAN_OBJECT, NOT_AVAILABLE_OBJECT:
+		return true
+	}
+
+	return false
+}
+
+// vectorParameters : recycles every parameter to the longest one, telling whether any of them was a vector
+func vectorParameters(function string, parameters []Object) ([][]Object, int, bool, *Error) {
+	vectors := make([][]Object, len(parameters))
+	length := 0
+	vector := false
+
+	for index, parameter := range parameters {
+		switch parameter := parameter.(type) {
+		case *Array:
+			for _, element := range parameter.Elements {
+				if !isAtomic(element) {
+					return nil, 0, false, newError("parameters to `%s` must be atomic vectors, got %s inside an ARRAY", function, element.Type())
+				}
+			}
+
+			vectors[index] = parameter.Elements
+			vector = true
+		default:
+			if !isAtomic(parameter) {
+				return nil, 0, false, newError("parameters to `%s` must be atomic vectors, got %s", function, parameter.Type())
+			}
+
+			vectors[index] = []Object{parameter}
+		}
+
+		if len(vectors[index]) > length {
+			length = len(vectors[index])
+		}
+	}
+
+	for _, elements := range vectors {
+		if 0 == len(elements) {
+			length = 0
+		}
+	}
+
+	return vectors, length, vector, nil
+}
+
+// vectorResult : a vector when any parameter was one, a scalar otherwise
+func vectorResult(results []Object, vector bool) Object {
+	if vector || 1 != len(results) {
+		return &Array{
+			Elements: results,
+		}
+	}
+
+	return results[0]
+}
+
+// mapCharacter : applies fn to every element of x, missing values staying missing
+func mapCharacter(function string, parameters []Object, want int, fn func(values []string) Object) Object {
+	if want != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=%d", len(parameters), want)
+	}
+
+	vectors, length, vector, err := vectorParameters(function, parameters)
+
+	if nil != err {
+		return err
+	}
+
+	results := make([]Object, length)
+
+	for position := range results {
+		values := make([]string, len(vectors))
+		missing := false
+
+		for index, elements := range vectors {
+			value, ok := asCharacter(elements[position%len(elements)])
+
+			values[index] = value
+			missing = missing || !ok
+		}
+
+		if missing {
+			results[position] = NA
+		} else {
+			results[position] = fn(values)
+		}
+	}
+
+	return vectorResult(results, vector)
+}
+
+// pasteStrings : just like R, missing values are pasted as "NA" and zero length vectors are left out
+func pasteStrings(function string, separator string, parameters []Object) Object {
+	present := []Object{}
+
+	for _, parameter := range parameters {
+		if array, ok := parameter.(*Array); !ok || 0 != len(array.Elements) {
+			present = append(present, parameter)
+		}
+	}
+
+	vectors, length, vector, err := vectorParameters(function, present)
+
+	if nil != err {
+		return err
+	}
+
+	results := make([]Object, length)
+
+	for position := range results {
+		values := make([]string, len(vectors))
+
+		for index, elements := range vectors {
+			if value, ok := asCharacter(elements[position%len(elements)]); ok {
+				values[index] = value
+			} else {
+				values[index] = "NA"
+			}
+		}
+
+		results[position] = &String{
+			Value: strings.Join(values, separator),
+		}
+	}
+
+	if 0 == length && !vector {
+		return &String{
+			Value: "",
+		}
+	}
+
+	return vectorResult(results, vector)
+}
+
+// pasteBuiltin : `paste(..., sep = " ", collapse = NULL)`
+func pasteBuiltin(context CallContext, parameters ...Object) Object {
+	separator := " "
+
+	if given(parameters, 1) {
+		value, ok := parameters[1].(*String)
+
+		if !ok {
+			return newError("sep to `paste` must be STRING, got %s", parameters[1].Type())
+		}
+
+		separator = value.Value
+	}
+
+	return collapseStrings("paste", pasteStrings("paste", separator, parameters[0].(*List).Elements), parameters, 2)
+}
+
+// paste0Builtin : `paste0(..., collapse = NULL)`
+func paste0Builtin(context CallContext, parameters ...Object) Object {
+	return collapseStrings("paste0", pasteStrings("paste0", "", parameters[0].(*List).Elements), parameters, 1)
+}
+
+// collapseStrings : the strings pasted together into a single one, separated by the parameter at index when it is
+// given
+func collapseStrings(function string, pasted Object, parameters []Object, index int) Object {
+	if _, failed := pasted.(*Error); failed || !given(parameters, index) {
+		return pasted
+	}
+
+	collapse, ok := parameters[index].(*String)
+
+	if !ok {
+		return newError("collapse to `%s` must be STRING, got %s", function, parameters[index].Type())
+	}
+
+	array, ok := pasted.(*Array)
+
+	if !ok {
+		return pasted
+	}
+
+	values := make([]string, len(array.Elements))
+
+	for index, element := range array.Elements {
+		values[index] = element.(*String).Value
+	}
+
+	return &String{
+		Value: strings.Join(values, collapse.Value),
+	}
+}
+
+// formatSpecification : `%[flags][width][.precision]verb`, the only ones R's `sprintf` and Go share
+var formatSpecification = regexp.MustCompile(`^%([-+ 0#]*)([0-9]*)(\.[0-9]*)?([disfeEgGxXo])`)
+
+// formatValue : converts value to what verb expects, just like R does
+func formatValue(specification string, flags, width, precision, verb string, value Object) (string, *Error) {
+	if NA == value {
+		return fmt.Sprintf("%"+strings.Replace(flags, "0", "", -1)+width+"s", "NA"), nil
+	}
+
+	switch verb {
+	case "d", "i", "x", "X", "o":
+		var integer int64
+
+		switch value := value.(type) {
+		case *Integer:
+			integer = value.Value
+		case *Double:
+			if value.Value != math.Trunc(value.Value) {
+				return "", newError("invalid format '%s'; use format %%f, %%e or %%g for numeric objects", specification)
+			}
+
+			integer = int64(value.Value)
+		case *Boolean:
+			if value.Value {
+				integer = 1
+			}
+		default:
+			return "", newError("invalid format '%s'; use format %%s for character objects", specification)
+		}
+
+		if "i" == verb {
+			verb = "d"
+		}
+
+		return fmt.Sprintf("%"+flags+width+precision+verb, integer), nil
+	case "s":
+		text, _ := asCharacter(value)
+
+		return fmt.Sprintf("%"+flags+width+precision+verb, text), nil
+	default:
+		switch value := value.(type) {
+		case *Integer:
+			return fmt.Sprintf("%"+flags+width+precision+verb, float64(value.Value)), nil
+		case *Double:
+			return fmt.Sprintf("%"+flags+width+precision+verb, value.Value), nil
+		default:
+			return "", newError("invalid format '%s'; use format %%s for character objects", specification)
+		}
+	}
+}
+
+// sprintfString : formats a single set of values
+func sprintfString(format string, values []Object) (string, *Error) {
+	var out strings.Builder
+
+	next := 0
+
+	for 0 < len(format) {
+		position := strings.IndexByte(format, '%')
+
+		if -1 == position {
+			out.WriteString(format)
+
+			break
+		}
+
+		out.WriteString(format[:position])
+		format = format[position:]
+
+		if strings.HasPrefix(format, "%%") {
+			out.WriteString("%")
+			format = format[2:]
+
+			continue
+		}
+
+		parts := formatSpecification.FindStringSubmatch(format)
+
+		if nil == parts {
+			return "", newError("unrecognised format specification '%s'", format)
+		}
+
+		if next >= len(values) {
+			return "", newError("too few arguments")
+		}
+
+		formatted, err := formatValue(parts[0], parts[1], parts[2], parts[3], parts[4], values[next])
+
+		if nil != err {
+			return "", err
+		}
+
+		out.WriteString(formatted)
+		format = format[len(parts[0]):]
+		next++
+	}
+
+	return out.String(), nil
+}
+
+// sprintfBuiltin : `sprintf(fmt, ...)`, vectorized over the format and the values
+func sprintfBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 > len(parameters) {
+		return newError("wrong number of parameters, got=%d, want at least 1", len(parameters))
+	}
+
+	vectors, length, vector, err := vectorParameters("sprintf", parameters)
+
+	if nil != err {
+		return err
+	}
+
+	results := make([]Object, length)
+
+	for position := range results {
+		format, ok := vectors[0][position%len(vectors[0])].(*String)
+
+		if !ok {
+			return newError("format to `sprintf` must be STRING, got %s", vectors[0][position%len(vectors[0])].Type())
+		}
+
+		values := make([]Object, len(vectors)-1)
+
+		for index, elements := range vectors[1:] {
+			values[index] = elements[position%len(elements)]
+		}
+
+		formatted, err := sprintfString(format.Value, values)
+
+		if nil != err {
+			return err
+		}
+
+		results[position] = &String{
+			Value: formatted,
+		}
+	}
+
+	return vectorResult(results, vector)
+}
+
+// formatDoubles : every double gets as many decimals as the one needing the most, within seven significant digits
+func formatDoubles(elements []Object) []string {
+	decimals := 0
+	scientific := false
+
+	for _, element := range elements {
+		if double, ok := element.(*Double); ok {
+			text := strconv.FormatFloat(double.Value, 'g', 7, 64)
+
+			if strings.ContainsAny(text, "eIN") {
+				scientific = true
+			} else if point := strings.IndexByte(text, '.'); -1 != point && len(text)-point-1 > decimals {
+				decimals = len(text) - point - 1
+			}
+		}
+	}
+
+	texts := make([]string, len(elements))
+
+	for index, element := range elements {
+		switch element := element.(type) {
+		case *Double:
+			if scientific {
+				texts[index] = strconv.FormatFloat(element.Value, 'g', 7, 64)
+			} else {
+				texts[index] = strconv.FormatFloat(element.Value, 'f', decimals, 64)
+			}
+		case *Integer:
+			texts[index] = strconv.FormatFloat(float64(element.Value), 'f', decimals, 64)
+		default:
+			texts[index] = element.Inspect()
+		}
+	}
+
+	return texts
+}
+
+// formatBuiltin : `format(x)`, giving every element of x the same width -- numbers are right justified, strings left justified
+func formatBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1", len(parameters))
+	}
+
+	vectors, _, vector, err := vectorParameters("format", parameters)
+
+	if nil != err {
+		return err
+	}
+
+	elements := vectors[0]
+	texts := formatDoubles(elements)
+	width := 0
+	left := false
+
+	for index, text := range texts {
+		if STRING_OBJECT == elements[index].Type() {
+			left = true
+		}
+
+		if count := utf8.RuneCountInString(text); count > width {
+			width = count
+		}
+	}
+
+	results := make([]Object, len(texts))
+
+	for index, text := range texts {
+		padding := strings.Repeat(" ", width-utf8.RuneCountInString(text))
+
+		if left {
+			text += padding
+		} else {
+			text = padding + text
+		}
+
+		results[index] = &String{
+			Value: text,
+		}
+	}
+
+	return vectorResult(results, vector)
+}
+
+// ncharBuiltin : `nchar(x)`, counting characters instead of bytes
+func ncharBuiltin(context CallContext, parameters ...Object) Object {
+	return mapCharacter("nchar", parameters, 1, func(values []string) Object {
+		return &Integer{
+			Value: int64(utf8.RuneCountInString(values[0])),
+		}
+	})
+}
+
+// substrBuiltin : `substr(x, start, stop)`, where start and stop are 0-based -- just like indexes -- and stop is included
+func substrBuiltin(context CallContext, parameters ...Object) Object {
+	if 3 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=3", len(parameters))
+	}
+
+	for _, parameter := range parameters[1:] {
+		if !isIntegerVector(parameter) {
+			return newError("start and stop to `substr` must be INTEGER, got %s", parameter.Type())
+		}
+	}
+
+	return mapCharacter("substr", parameters, 3, func(values []string) Object {
+		runes := []rune(values[0])
+		start, _ := strconv.Atoi(values[1])
+		stop, _ := strconv.Atoi(values[2])
+
+		if 0 > start {
+			start = 0
+		}
+
+		if stop >= len(runes) {
+			stop = len(runes) - 1
+		}
+
+		if start > stop {
+			return &String{
+				Value: "",
+			}
+		}
+
+		return &String{
+			Value: string(runes[start : stop+1]),
+		}
+	})
+}
+
+// isIntegerVector :
+func isIntegerVector(obj Object) bool {
+	array, ok := obj.(*Array)
+
+	if !ok {
+		return INTEGER_OBJECT == obj.Type() || NA == obj
+	}
+
+	for _, element := range array.Elements {
+		if INTEGER_OBJECT != element.Type() && NA != element {
+			return false
+		}
+	}
+
+	return true
+}
+
+// splitString : an empty split gives every character apart, just like in R
+func splitString(text string, split *regexp.Regexp) []Object {
+	var parts []string
+
+	if nil == split {
+		parts = strings.Split(text, "")
+	} else {
+		parts = split.Split(text, -1)
+	}
+
+	// a trailing match does not produce an empty string in R
+	if 0 < len(parts) && "" == parts[len(parts)-1] {
+		parts = parts[:len(parts)-1]
+	}
+
+	elements := make([]Object, len(parts))
+
+	for index, part := range parts {
+		elements[index] = &String{
+			Value: part,
+		}
+	}
+
+	return elements
+}
+
+// strsplitBuiltin : `strsplit(x, split, fixed, perl)` gives a vector for a single string and a list of vectors for a
+// vector of them
+func strsplitBuiltin(context CallContext, parameters ...Object) Object {
+	if 2 > len(parameters) {
+		return newError("wrong number of parameters, got=%d, want at least 2", len(parameters))
+	}
+
+	flags, err := logicalFlags("strsplit", parameters[2:], "fixed", "perl")
+
+	if nil != err {
+		return err
+	}
+
+	var split *regexp.Regexp
+
+	if pattern, ok := parameters[1].(*String); !ok || "" != pattern.Value {
+		if split, err = compilePattern("strsplit", parameters[1], flags); nil != err {
+			return err
+		}
+	}
+
+	return mapCharacter("strsplit", parameters[:1], 1, func(values []string) Object {
+		return &Array{
+			Elements: splitString(values[0], split),
+		}
+	})
+}
+
+// toupperBuiltin : `toupper(x)`
+func toupperBuiltin(context CallContext, parameters ...Object) Object {
+	return mapCharacter("toupper", parameters, 1, func(values []string) Object {
+		return &String{
+			Value: strings.ToUpper(values[0]),
+		}
+	})
+}
+
+// tolowerBuiltin : `tolower(x)`
+func tolowerBuiltin(context CallContext, parameters ...Object) Object {
+	return mapCharacter("tolower", parameters, 1, func(values []string) Object {
+		return &String{
+			Value: strings.ToLower(values[0]),
+		}
+	})
+}
+
+// trimwsBuiltin : `trimws(x, which)`, which being "both" -- the default --, "left" or "right"
+func trimwsBuiltin(context CallContext, parameters ...Object) Object {
+	if 1 > len(parameters) || 2 < len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=1 or 2", len(parameters))
+	}
+
+	which := "both"
+
+	if 2 == len(parameters) {
+		side, ok := parameters[1].(*String)
+
+		if !ok || ("both" != side.Value && "left" != side.Value && "right" != side.Value) {
+			return newError("which to `trimws` must be \"both\", \"left\" or \"right\", got %s", parameters[1].Inspect())
+		}
+
+		which = side.Value
+		parameters = parameters[:1]
+	}
+
+	const whitespace = " \t\r\n"
+
+	return mapCharacter("trimws", parameters, 1, func(values []string) Object {
+		text := values[0]
+
+		if "right" != which {
+			text = strings.TrimLeft(text, whitespace)
+		}
+
+		if "left" != which {
+			text = strings.TrimRight(text, whitespace)
+		}
+
+		return &String{
+			Value: text,
+		}
+	})
+}
+
+// startsWithBuiltin : `startsWith(x, prefix)`
+func startsWithBuiltin(context CallContext, parameters ...Object) Object {
+	return mapCharacter("startsWith", parameters, 2, func(values []string) Object {
+		return &Boolean{
+			Value: strings.HasPrefix(values[0], values[1]),
+		}
+	})
+}
+
+// endsWithBuiltin : `endsWith(x, suffix)`
+func endsWithBuiltin(context CallContext, parameters ...Object) Object {
+	return mapCharacter("endsWith", parameters, 2, func(values []string) Object {
+		return &Boolean{
+			Value: strings.HasSuffix(values[0], values[1]),
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package object
+
+import (
+	"strings"
+)
+
+// Variant : a value of a type made of alternatives, as `Some(1)` is one of Option; Tag tells which alternative it is
+// and Values holds its fields, named after Names
+type Variant struct {
+	Family string
+	Tag    string
+	Names  []string
+	Values []Object
+}
+
+// Type : the type the variant is one of, `OPTION` or `RESULT`
+func (v *Variant) Type() ObjectType {
+	return ObjectType(strings.ToUpper(v.Family))
+}
+
+// Inspect : as the variant would be made, `Some(1)`
+func (v *Variant) Inspect() string {
+	values := make([]string, len(v.Values))
+
+	for index, value := range v.Values {
+		values[index] = value.Inspect()
+	}
+
+	return v.Tag + "(" + strings.Join(values, ", ") + ")"
+}
+
+// Field : the value of the field, when the variant has one named so
+func (v *Variant) Field(name string) (Object, bool) {
+	for index, field := range v.Names {
+		if field == name {
+			return v.Values[index], true
+		}
+	}
+
+	return nil, false
+}
+
+// succeeded : the alternatives of Option and Result holding a value the helpers go on with
+var succeeded = map[string]bool{
+	"Some": true,
+	"Ok":   true,
+}
+
+// constructorBuiltin : `Some(x)`, `None()`, `Ok(x)` and `Err(e)`, whose fields are named after names
+func constructorBuiltin(family string, tag string, names ...string) BuiltinFunction {
+	return func(context CallContext, parameters ...Object) Object {
+		if len(names) != len(parameters) {
+			return newError("wrong number of parameters to `%s`, got=%d, want=%d", tag, len(parameters), len(names))
+		}
+
+		return &Variant{
+			Family: family,
+			Tag:    tag,
+			Names:  names,
+			Values: append([]Object{}, parameters...),
+		}
+	}
+}
+
+// optionParameter : the Option or Result given to a helper
+func optionParameter(function string, parameter Object) (*Variant, *Error) {
+	variant, ok := parameter.(*Variant)
+
+	if !ok || ("Option" != variant.Family && "Result" != variant.Family) {
+		return nil, newError("parameter to `%s` must be OPTION or RESULT, got %s", function, parameter.Type())
+	}
+
+	return variant, nil
+}
+
+// mapBuiltin : `map(o, f)`, `Some(f(x))` for `Some(x)` and `Ok(f(x))` for `Ok(x)`, None and Err being left as they are
+func mapBuiltin(context CallContext, parameters ...Object) Object {
+	if 2 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=2", len(parameters))
+	}
+
+	variant, err := optionParameter("map", parameters[0])
+
+	if nil != err {
+		return err
+	}
+
+	if err := functionParameter("map", parameters[1]); nil != err {
+		return err
+	}
+
+	if !succeeded[variant.Tag] {
+		return variant
+	}
+
+	result, err := context.Call(parameters[1], variant.Values[0])
+
+	if nil != err {
+		return err
+	}
+
+	return &Variant{
+		Family: variant.Family,
+		Tag:    variant.Tag,
+		Names:  variant.Names,
+		Values: []Object{result},
+	}
+}
+
+// andThenBuiltin : `and_then(o, f)`, what `f(x)` gives for `Some(x)` or `Ok(x)`, which must be of the same type;
+// None and Err are left as they are
+func andThenBuiltin(context CallContext, parameters ...Object) Object {
+	if 2 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=2", len(parameters))
+	}
+
+	variant, err := optionParameter("and_then", parameters[0])
+
+	if nil != err {
+		return err
+	}
+
+	if err := functionParameter("and_then", parameters[1]); nil != err {
+		return err
+	}
+
+	if !succeeded[variant.Tag] {
+		return variant
+	}
+
+	result, err := context.Call(parameters[1], variant.Values[0])
+
+	if nil != err {
+		return err
+	}
+
+	if chained, ok := result.(*Variant); !ok || chained.Family != variant.Family {
+		return newError("function given to `and_then` must give %s, got %s", variant.Type(), result.Type())
+	}
+
+	return result
+}
+
+// unwrapOrBuiltin : `unwrap_or(o, default)`, the value held by `Some(x)` or `Ok(x)`, default for None and Err
+func unwrapOrBuiltin(context CallContext, parameters ...Object) Object {
+	if 2 != len(parameters) {
+		return newError("wrong number of parameters, got=%d, want=2", len(parameters))
+	}
+
+	variant, err := optionParameter("unwrap_or", parameters[0])
+
+	if nil != err {
+		return err
+	}
+
+	if !succeeded[variant.Tag] {
+		return parameters[1]
+	}
+
+	return variant.Values[0]
+}
+
+// NewConstructor : the builtin making the alternative tag of a type declared with `type`, whose fields are named
+// after names, so they can be given by name as well
+func NewConstructor(family string, tag string, names []string) *Builtin {
+	return &Builtin{
+		Fn:         constructorBuiltin(family, tag, names...),
+		Parameters: append([]string{}, names...),
+	}
+}
+
+// Destructure : the fields of value and whether it is the alternative tag at all; count is the number of fields the
+// pattern names, -1 when it names none
+func Destructure(value Object, tag string, count int) ([]Object, bool, *Error) {
+	variant, ok := value.(*Variant)
+
+	if !ok || variant.Tag != tag {
+		return nil, false, nil
+	}
+
+	if -1 != count && count != len(variant.Values) {
+		return nil, false, newError("pattern %s takes %d fields, got=%d", tag, len(variant.Values), count)
+	}
+
+	return variant.Values, true, nil
+}
@@ -16,6 +16,7 @@ const (
 	LESSGREATER     // > or <
 	SUM             // +
 	PRODUCT         // *
+	SPECIAL         // %any% or |>
 	PREFIX          // -X or !X
 	CALL            // myFunction(X)
 	INDEX           // myArray[?]
@@ -30,8 +31,12 @@ var precedences = map[token.TokenType]int{
 	token.MINUS:            SUM,
 	token.SLASH:            PRODUCT,
 	token.ASTERISK:         PRODUCT,
+	token.MATRIX_PRODUCT:   SPECIAL,
+	token.SPECIAL:          SPECIAL,
+	token.PIPE:             SPECIAL,
 	token.LEFT_PARENTHESIS: CALL,
 	token.LEFT_BRACKET:     INDEX,
+	token.DOLLAR:           INDEX,
 }
 
 // Parser :
@@ -45,6 +50,9 @@ type Parser struct {
 
 	prefixParserFunction map[token.TokenType]prefixParserFunction
 	infixParserFunction  map[token.TokenType]infixParserFunction
+
+	// r tells the source is R, see InitializeRParser
+	r bool
 }
 
 type (
@@ -121,14 +129,30 @@ func (p *Parser) expectCurrent(t token.TokenType) bool {
 	return false
 }
 
-// parseExpressionStatement :
-func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
+// parseExpressionStatement : `value -> name` binds the constant just as `name <- value` does
+func (p *Parser) parseExpressionStatement() ast.Statement {
 	statement := &ast.ExpressionStatement{
 		Token: p.currentToken,
 	}
 
 	statement.Expression = p.parseExpression(LOWEST)
 
+	switch {
+	case p.peekTokenIs(token.RIGHT_ASSIGN):
+		p.nextToken()
+
+		return p.parseRightAssignment(statement.Expression)
+	case p.peekTokenIs(token.ASSIGN), p.peekTokenIs(token.EQUAL):
+		p.nextToken()
+		p.bindingError()
+
+		if p.peekTokenIs(token.SEMICOLON) {
+			p.nextToken()
+		}
+
+		return nil
+	}
+
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
@@ -151,7 +175,9 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 		Value: p.currentToken.Literal,
 	}
 
-	if !p.expectPeek(token.ASSIGN) {
+	if p.peekTokenIs(token.EQUAL) {
+		p.nextToken()
+	} else if !p.expectPeek(token.ASSIGN) {
 		return nil
 	}
 
@@ -163,6 +189,8 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 		functionLiteral.Name = statement.Name.Value
 	}
 
+	p.chainedAssignment()
+
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
@@ -170,7 +198,7 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 	return statement
 }
 
-// parseConstStatement :
+// parseConstStatement : `name <- value`, or `name = value` as R allows at the level of statements
 func (p *Parser) parseConstStatement() *ast.ConstStatement {
 	constant := token.Token{
 		Type:    token.CONST,
@@ -180,11 +208,11 @@ func (p *Parser) parseConstStatement() *ast.ConstStatement {
 		Token: constant,
 	}
 	statement.Name = &ast.Identifier{
-		Token: constant,
+		Token: p.currentToken,
 		Value: p.currentToken.Literal,
 	}
 
-	if !p.peekTokenIs(token.ASSIGN) {
+	if !p.peekTokenIs(token.ASSIGN) && !p.peekTokenIs(token.EQUAL) {
 		return nil
 	}
 
@@ -197,6 +225,170 @@ func (p *Parser) parseConstStatement() *ast.ConstStatement {
 		functionLiteral.Name = statement.Name.Value
 	}
 
+	p.chainedAssignment()
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return statement
+}
+
+// parseRightAssignment : `value -> name`, the parser being on the arrow
+func (p *Parser) parseRightAssignment(value ast.Expression) ast.Statement {
+	arrow := p.currentToken
+
+	p.nextToken()
+
+	name, ok := p.parseExpression(LOWEST).(*ast.Identifier)
+
+	if !ok {
+		message := fmt.Sprintf("only a name can be bound by `->` at line %d, column %d", arrow.Line, arrow.Column)
+		p.errors = append(p.errors, message)
+
+		if p.peekTokenIs(token.SEMICOLON) {
+			p.nextToken()
+		}
+
+		return nil
+	}
+
+	statement := &ast.ConstStatement{
+		Token: token.Token{
+			Type:    token.CONST,
+			Literal: "CONST",
+		},
+		Name:  name,
+		Value: value,
+	}
+
+	if functionLiteral, ok := value.(*ast.FunctionLiteral); ok {
+		functionLiteral.Name = name.Value
+	}
+
+	p.chainedAssignment()
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return statement
+}
+
+// bindingError : reports a binding to anything but a name, as in `f(x) = 1`, the parser being on the operator; the
+// value is read all the same, so it is not reported as well
+func (p *Parser) bindingError() {
+	operator := p.currentToken
+	message := fmt.Sprintf("only a name can be bound by `%s` at line %d, column %d", operator.Literal, operator.Line, operator.Column)
+	p.errors = append(p.errors, message)
+
+	p.nextToken()
+	p.parseExpression(LOWEST)
+}
+
+// chainedAssignment : reports another binding right after the value of one, as in `x = y = 1` or `x <- 1 -> y`,
+// which is easily misread; the rest of the chain is read all the same, so it is not reported as well
+func (p *Parser) chainedAssignment() {
+	if !p.peekTokenIs(token.ASSIGN) && !p.peekTokenIs(token.EQUAL) && !p.peekTokenIs(token.RIGHT_ASSIGN) {
+		return
+	}
+
+	message := fmt.Sprintf("chained assignments are ambiguous, bind one name at a time at line %d, column %d", p.peekToken.Line, p.peekToken.Column)
+	p.errors = append(p.errors, message)
+
+	for p.peekTokenIs(token.ASSIGN) || p.peekTokenIs(token.EQUAL) || p.peekTokenIs(token.RIGHT_ASSIGN) {
+		p.nextToken()
+		p.nextToken()
+		p.parseExpression(LOWEST)
+	}
+}
+
+// parseExportStatement : `export let x <- 1` or `export x <- 1`, bindings other modules can import
+func (p *Parser) parseExportStatement() ast.Statement {
+	export := p.currentToken
+
+	p.nextToken()
+
+	switch p.currentToken.Type {
+	case token.LET:
+		if statement := p.parseLetStatement(); nil != statement {
+			statement.Exported = true
+
+			return statement
+		}
+
+		return nil
+	case token.IDENTIFIER:
+		if p.isTypeStatement() {
+			if statement := p.parseTypeStatement(); nil != statement {
+				statement.Exported = true
+
+				return statement
+			}
+
+			return nil
+		}
+
+		if statement := p.parseConstStatement(); nil != statement {
+			statement.Exported = true
+
+			return statement
+		}
+	}
+
+	message := fmt.Sprintf("only bindings can be exported at line %d, column %d", export.Line, export.Column)
+	p.errors = append(p.errors, message)
+
+	return nil
+}
+
+// parseImportStatement : `import { a, b } from "./utils.tr"`
+func (p *Parser) parseImportStatement() ast.Statement {
+	statement := &ast.ImportStatement{
+		Token: p.currentToken,
+	}
+
+	if !p.expectPeek(token.LEFT_BRACE) || !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+
+	statement.Names = append(statement.Names, &ast.Identifier{
+		Token: p.currentToken,
+		Value: p.currentToken.Literal,
+	})
+
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
+
+		statement.Names = append(statement.Names, &ast.Identifier{
+			Token: p.currentToken,
+			Value: p.currentToken.Literal,
+		})
+	}
+
+	if !p.expectPeek(token.RIGHT_BRACE) {
+		return nil
+	}
+
+	if !p.peekTokenIs(token.IDENTIFIER) || "from" != p.peekToken.Literal {
+		message := fmt.Sprintf("expected `from` after the imported names at line %d, column %d", p.peekToken.Line, p.peekToken.Column)
+		p.errors = append(p.errors, message)
+
+		return nil
+	}
+
+	p.nextToken()
+
+	if !p.expectPeek(token.STRING) {
+		return nil
+	}
+
+	statement.Path = p.currentToken.Literal
+
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
@@ -204,6 +396,171 @@ func (p *Parser) parseConstStatement() *ast.ConstStatement {
 	return statement
 }
 
+// parseDeclareStatement : `declare function mean(x: numeric[], na.rm: logical = FALSE): double`, the signature
+// of a function written in R
+func (p *Parser) parseDeclareStatement() ast.Statement {
+	statement := &ast.DeclareStatement{
+		Token: p.currentToken,
+	}
+
+	if !p.peekTokenIs(token.FUNCTION) {
+		message := fmt.Sprintf("only functions can be declared at line %d, column %d", p.peekToken.Line, p.peekToken.Column)
+		p.errors = append(p.errors, message)
+
+		return nil
+	}
+
+	p.nextToken()
+
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+
+	statement.Name = &ast.Identifier{
+		Token: p.currentToken,
+		Value: p.currentToken.Literal,
+	}
+
+	if p.peekTokenIs(token.LESS_THAN) {
+		if statement.TypeParameters = p.parseTypeParameterList(); nil == statement.TypeParameters {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.LEFT_PARENTHESIS) {
+		return nil
+	}
+
+	statement.Parameters, statement.Types, statement.Defaults = p.parseFunctionParameters(true)
+
+	if nil == statement.Parameters {
+		return nil
+	}
+
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+
+		if statement.Result = p.parseType(); nil == statement.Result {
+			return nil
+		}
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return statement
+}
+
+// isTypeStatement : `type` is only a keyword when a name follows it, so it can still name arguments, as in R
+func (p *Parser) isTypeStatement() bool {
+	return "type" == p.currentToken.Literal && p.peekTokenIs(token.IDENTIFIER)
+}
+
+// parseTypeStatement : `type Shape = Circle(r: double) | Rect(w: double, h: double)`, or an alias, as in
+// `type Numbers = double[]`; a name alone, neither followed by fields nor by another alternative, is an alias, so a
+// type with a single alternative gives it parentheses, as in `type Unit = Unit()`
+func (p *Parser) parseTypeStatement() *ast.TypeStatement {
+	statement := &ast.TypeStatement{
+		Token: p.currentToken,
+	}
+
+	p.nextToken()
+
+	statement.Name = &ast.Identifier{
+		Token: p.currentToken,
+		Value: p.currentToken.Literal,
+	}
+
+	if p.peekTokenIs(token.LESS_THAN) {
+		if statement.TypeParameters = p.parseTypeParameterList(); nil == statement.TypeParameters {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.EQUAL) {
+		return nil
+	}
+
+	annotation := p.parseType()
+
+	if nil == annotation {
+		return nil
+	}
+
+	if annotation.Vector || nil != annotation.Parameters || !p.peekTokenIs(token.LEFT_PARENTHESIS) && !p.peekTokenIs(token.BAR) {
+		statement.Alias = annotation
+
+		if p.peekTokenIs(token.SEMICOLON) {
+			p.nextToken()
+		}
+
+		return statement
+	}
+
+	for {
+		constructor := p.parseConstructor()
+
+		if nil == constructor {
+			return nil
+		}
+
+		statement.Constructors = append(statement.Constructors, constructor)
+
+		if !p.peekTokenIs(token.BAR) {
+			break
+		}
+
+		p.nextToken()
+
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return statement
+}
+
+// parseConstructor : `Circle(r: double)`, or `Empty` for an alternative without fields, the parser being on its name
+func (p *Parser) parseConstructor() *ast.Constructor {
+	constructor := &ast.Constructor{
+		Token: p.currentToken,
+		Name: &ast.Identifier{
+			Token: p.currentToken,
+			Value: p.currentToken.Literal,
+		},
+	}
+
+	if !p.peekTokenIs(token.LEFT_PARENTHESIS) {
+		return constructor
+	}
+
+	p.nextToken()
+
+	fields, types, defaults := p.parseFunctionParameters(true)
+
+	if nil == fields {
+		return nil
+	}
+
+	for index, field := range fields {
+		if "..." == field.Value || nil != defaults[index] {
+			message := fmt.Sprintf("fields of %s can not be `...` nor have a default value at line %d, column %d", constructor.Name, field.Token.Line, field.Token.Column)
+			p.errors = append(p.errors, message)
+
+			return nil
+		}
+	}
+
+	constructor.Fields, constructor.Types = fields, types
+
+	return constructor
+}
+
 // parseReturnStatement :
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	statement := &ast.ReturnStatement{
@@ -274,8 +631,16 @@ func (p *Parser) parseIdentifier() ast.Expression {
 
 // parseStatement :
 func (p *Parser) parseStatement() ast.Statement {
+	if p.r {
+		return p.parseRStatement()
+	}
+
 	switch p.currentToken.Type {
 	case token.IDENTIFIER:
+		if p.isTypeStatement() {
+			return p.parseTypeStatement()
+		}
+
 		constant := p.parseConstStatement()
 
 		if nil != constant {
@@ -285,6 +650,12 @@ func (p *Parser) parseStatement() ast.Statement {
 		return p.parseExpressionStatement()
 	case token.LET:
 		return p.parseLetStatement()
+	case token.EXPORT:
+		return p.parseExportStatement()
+	case token.IMPORT:
+		return p.parseImportStatement()
+	case token.DECLARE:
+		return p.parseDeclareStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
 	default:
@@ -312,8 +683,47 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	return literal
 }
 
+// parseDoubleLiteral :
+func (p *Parser) parseDoubleLiteral() ast.Expression {
+	literal := &ast.DoubleLiteral{
+		Token: p.currentToken,
+	}
+
+	value, err := strconv.ParseFloat(p.currentToken.Literal, 64)
+
+	if nil != err {
+		message := fmt.Sprintf("could not parse '%q' as double", p.currentToken.Literal)
+		p.errors = append(p.errors, message)
+
+		return nil
+	}
+
+	literal.Value = value
+
+	return literal
+}
+
+// reportedByLexer : illegal tokens coming from a problem found by the lexer hold its message
+func (p *Parser) reportedByLexer(t token.Token) bool {
+	if token.ILLEGAL != t.Type {
+		return false
+	}
+
+	for _, message := range p.l.Errors() {
+		if message == t.Literal {
+			return true
+		}
+	}
+
+	return false
+}
+
 // noPrefixParserFnError :
 func (p *Parser) noPrefixParserFnError(t token.TokenType) {
+	if p.reportedByLexer(p.currentToken) {
+		return
+	}
+
 	message := fmt.Sprintf("no prefix parse function for '%s' was found", t)
 	p.errors = append(p.errors, message)
 }
@@ -330,7 +740,7 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 
 	leftExpression := prefix()
 
-	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
+	for !p.peekTokenIs(token.SEMICOLON) && !(p.r && p.peekToken.Newline) && precedence < p.peekPrecedence() {
 		infix := p.infixParserFunction[p.peekToken.Type]
 
 		if nil == infix {
@@ -385,6 +795,60 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expression
 }
 
+// parseSpecialExpression : `x %op% y` is just a call to the function named `%op%`
+func (p *Parser) parseSpecialExpression(left ast.Expression) ast.Expression {
+	expression := &ast.CallExpression{
+		Token: p.currentToken,
+		Function: &ast.Identifier{
+			Token: p.currentToken,
+			Value: p.currentToken.Literal,
+		},
+	}
+
+	precedence := p.currentPrecedence()
+
+	p.nextToken()
+
+	expression.Parameters = []ast.Expression{left, p.parseExpression(precedence)}
+
+	return expression
+}
+
+// parsePipeExpression : `x |> f(y)` is rewritten into `f(x, y)`, so the pipe costs nothing at runtime; just like
+// magrittr, `%>%` also takes a bare function, as in `x %>% f`
+func (p *Parser) parsePipeExpression(left ast.Expression) ast.Expression {
+	pipe := p.currentToken
+	precedence := p.currentPrecedence()
+
+	p.nextToken()
+
+	right := p.parseExpression(precedence)
+
+	switch right := right.(type) {
+	case *ast.CallExpression:
+		return &ast.CallExpression{
+			Token:      right.Token,
+			Function:   right.Function,
+			Parameters: append([]ast.Expression{left}, right.Parameters...),
+		}
+	case *ast.Identifier, *ast.FunctionLiteral:
+		if "%>%" == pipe.Literal {
+			return &ast.CallExpression{
+				Token:      pipe,
+				Function:   right,
+				Parameters: []ast.Expression{left},
+			}
+		}
+	}
+
+	if nil != right {
+		message := fmt.Sprintf("the pipe operator requires a function call as its right side at line %d, column %d", pipe.Line, pipe.Column)
+		p.errors = append(p.errors, message)
+	}
+
+	return nil
+}
+
 // parseBoolean :
 func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{
@@ -393,6 +857,13 @@ func (p *Parser) parseBoolean() ast.Expression {
 	}
 }
 
+// parseNotAvailable :
+func (p *Parser) parseNotAvailable() ast.Expression {
+	return &ast.NotAvailable{
+		Token: p.currentToken,
+	}
+}
+
 // parseAnonymousFunctionLiteral :
 func (p *Parser) parseAnonymousFunctionLiteral() ast.Expression {
 	function := token.Token{
@@ -406,19 +877,20 @@ func (p *Parser) parseAnonymousFunctionLiteral() ast.Expression {
 	p.backToken()
 	p.previousToken = function
 
-	literal.Parameters = p.parseFunctionParameters()
+	literal.Parameters, _, literal.Defaults = p.parseFunctionParameters(false)
 	literal.Body = p.parseBlockStatement()
 
 	return literal
 }
 
-// parseGroupedExpression :
+// parseGroupedExpression : R source has no anonymous functions written as `(x) x + 1`
 func (p *Parser) parseGroupedExpression() ast.Expression {
 	p.nextToken()
 
-	if p.peekTokenIs(token.COMMA) ||
+	if !p.r && (p.peekTokenIs(token.COMMA) ||
+		p.currentTokenIs(token.ELLIPSIS) ||
 		p.currentTokenIs(token.RIGHT_PARENTHESIS) ||
-		p.peekTokenIs(token.RIGHT_PARENTHESIS) {
+		p.peekTokenIs(token.RIGHT_PARENTHESIS)) {
 		return p.parseAnonymousFunctionLiteral()
 	}
 
@@ -495,6 +967,15 @@ func (p *Parser) parseConditionalExpression() ast.Expression {
 
 	expression.Condition = p.parseExpression(LOWEST)
 
+	if p.peekTokenIs(token.EQUAL) {
+		message := fmt.Sprintf("`=` does not compare, `==` does, at line %d, column %d", p.peekToken.Line, p.peekToken.Column)
+		p.errors = append(p.errors, message)
+
+		p.nextToken()
+		p.nextToken()
+		p.parseExpression(LOWEST)
+	}
+
 	if !p.expectPeek(token.RIGHT_PARENTHESIS) {
 		return nil
 	}
@@ -510,40 +991,286 @@ func (p *Parser) parseConditionalExpression() ast.Expression {
 	return expression
 }
 
-// parseFunctionParameters :
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-	identifiers := []*ast.Identifier{}
+// parseMatchExpression : `match (s) { Circle(r) -> r * r, Rect(w, h) -> { w * h }, _ -> 0 }`
+func (p *Parser) parseMatchExpression() ast.Expression {
+	expression := &ast.MatchExpression{
+		Token: p.currentToken,
+	}
 
-	if p.peekTokenIs(token.RIGHT_PARENTHESIS) {
+	if !p.expectPeek(token.LEFT_PARENTHESIS) {
+		return nil
+	}
+
+	p.nextToken()
+
+	expression.Subject = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RIGHT_PARENTHESIS) || !p.expectPeek(token.LEFT_BRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RIGHT_BRACE) {
+		arm := p.parseMatchArm()
+
+		if nil == arm {
+			return nil
+		}
+
+		expression.Arms = append(expression.Arms, arm)
+
+		// arms are separated by commas, or only by the lines they are on
+		if p.peekTokenIs(token.COMMA) {
+			p.nextToken()
+		}
+	}
+
+	if !p.expectPeek(token.RIGHT_BRACE) {
+		return nil
+	}
+
+	return expression
+}
+
+// parseMatchArm : `Rect(w, _) -> w`, the pattern naming the fields it binds
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+
+	arm := &ast.MatchArm{
+		Token: p.currentToken,
+		Constructor: &ast.Identifier{
+			Token: p.currentToken,
+			Value: p.currentToken.Literal,
+		},
+	}
+
+	if p.peekTokenIs(token.LEFT_PARENTHESIS) {
 		p.nextToken()
+		arm.Fields = []*ast.Identifier{}
+
+		for !p.peekTokenIs(token.RIGHT_PARENTHESIS) {
+			if !p.expectPeek(token.IDENTIFIER) {
+				return nil
+			}
+
+			arm.Fields = append(arm.Fields, &ast.Identifier{
+				Token: p.currentToken,
+				Value: p.currentToken.Literal,
+			})
+
+			if !p.peekTokenIs(token.COMMA) {
+				break
+			}
 
-		return identifiers
+			p.nextToken()
+		}
+
+		if !p.expectPeek(token.RIGHT_PARENTHESIS) {
+			return nil
+		}
 	}
 
-	p.nextToken()
+	if !p.expectPeek(token.RIGHT_ASSIGN) {
+		return nil
+	}
+
+	arm.Body = p.parseBlockStatement()
 
+	return arm
+}
+
+// parseFunctionParameter : `x`, or `n = 10` when it has a default value; a declaration may also tell its type, as
+// in `n: integer = 10`
+func (p *Parser) parseFunctionParameter(typed bool) (*ast.Identifier, *ast.Type, ast.Expression) {
 	identifier := &ast.Identifier{
 		Token: p.currentToken,
 		Value: p.currentToken.Literal,
 	}
-	identifiers = append(identifiers, identifier)
 
-	for p.peekTokenIs(token.COMMA) {
+	var annotation *ast.Type
+
+	if typed && p.peekTokenIs(token.COLON) {
 		p.nextToken()
+
+		if annotation = p.parseType(); nil == annotation {
+			return nil, nil, nil
+		}
+	}
+
+	if !p.peekTokenIs(token.EQUAL) {
+		return identifier, annotation, nil
+	}
+
+	if "..." == identifier.Value {
+		message := fmt.Sprintf("`...` can not have a default value at line %d, column %d", p.peekToken.Line, p.peekToken.Column)
+		p.errors = append(p.errors, message)
+	}
+
+	p.nextToken()
+	p.nextToken()
+
+	return identifier, annotation, p.parseExpression(LOWEST)
+}
+
+// parseFunctionParameters : the parameters along with their types, when typed, and default values, nil for those
+// without one
+func (p *Parser) parseFunctionParameters(typed bool) ([]*ast.Identifier, []*ast.Type, []ast.Expression) {
+	identifiers := []*ast.Identifier{}
+	types := []*ast.Type{}
+	defaults := []ast.Expression{}
+	seen := map[string]bool{}
+
+	if p.peekTokenIs(token.RIGHT_PARENTHESIS) {
 		p.nextToken()
 
-		identifier := &ast.Identifier{
-			Token: p.currentToken,
-			Value: p.currentToken.Literal,
+		return identifiers, types, defaults
+	}
+
+	for {
+		p.nextToken()
+
+		identifier, annotation, value := p.parseFunctionParameter(typed)
+
+		if nil == identifier {
+			return nil, nil, nil
+		}
+
+		// a name given twice could not tell which argument it stands for, just as in R
+		if seen[identifier.Value] {
+			message := fmt.Sprintf("repeated formal argument '%s' at line %d, column %d", identifier.Value, identifier.Token.Line, identifier.Token.Column)
+			p.errors = append(p.errors, message)
 		}
+
+		seen[identifier.Value] = true
 		identifiers = append(identifiers, identifier)
+		types = append(types, annotation)
+		defaults = append(defaults, value)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+
+		p.nextToken()
 	}
 
 	if !p.expectPeek(token.RIGHT_PARENTHESIS) {
+		return nil, nil, nil
+	}
+
+	return identifiers, types, defaults
+}
+
+// parseType : `numeric`, or `numeric[]` for a vector; `function` names a type as well, and generic types are given
+// theirs between brackets, as in `Option[double]`
+func (p *Parser) parseType() *ast.Type {
+	if !p.peekTokenIs(token.FUNCTION) && !p.expectPeek(token.IDENTIFIER) {
 		return nil
 	}
 
-	return identifiers
+	if p.peekTokenIs(token.FUNCTION) {
+		p.nextToken()
+	}
+
+	annotation := &ast.Type{
+		Token: p.currentToken,
+		Name:  p.currentToken.Literal,
+	}
+
+	if !p.peekTokenIs(token.LEFT_BRACKET) {
+		return annotation
+	}
+
+	p.nextToken()
+
+	if p.peekTokenIs(token.IDENTIFIER) || p.peekTokenIs(token.FUNCTION) {
+		if annotation.Parameters = p.parseTypeParameters(); nil == annotation.Parameters {
+			return nil
+		}
+
+		if !p.peekTokenIs(token.LEFT_BRACKET) {
+			return annotation
+		}
+
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RIGHT_BRACKET) {
+		return nil
+	}
+
+	annotation.Vector = true
+
+	return annotation
+}
+
+// parseTypeParameterList : `<T, U: numeric>`, the type parameters of a generic function or type, each one bounded
+// by the type following it, if any
+func (p *Parser) parseTypeParameterList() []*ast.TypeParameter {
+	parameters := []*ast.TypeParameter{}
+
+	p.nextToken()
+
+	for {
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
+
+		parameter := &ast.TypeParameter{
+			Token: p.currentToken,
+			Name:  p.currentToken.Literal,
+		}
+
+		if p.peekTokenIs(token.COLON) {
+			p.nextToken()
+
+			if parameter.Bound = p.parseType(); nil == parameter.Bound {
+				return nil
+			}
+		}
+
+		parameters = append(parameters, parameter)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.GREATER_THAN) {
+		return nil
+	}
+
+	return parameters
+}
+
+// parseTypeParameters : the types given to a generic type, `double, character` in `Result[double, character]`, the
+// parser being on the bracket opening them
+func (p *Parser) parseTypeParameters() []*ast.Type {
+	parameters := []*ast.Type{}
+
+	for {
+		parameter := p.parseType()
+
+		if nil == parameter {
+			return nil
+		}
+
+		parameters = append(parameters, parameter)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RIGHT_BRACKET) {
+		return nil
+	}
+
+	return parameters
 }
 
 // parseFunctionLiteral :
@@ -552,16 +1279,61 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		Token: p.currentToken,
 	}
 
+	if !p.r && p.peekTokenIs(token.LESS_THAN) {
+		if literal.TypeParameters = p.parseTypeParameterList(); nil == literal.TypeParameters {
+			return nil
+		}
+	}
+
 	if !p.expectPeek(token.LEFT_PARENTHESIS) {
 		return nil
 	}
 
-	literal.Parameters = p.parseFunctionParameters()
+	literal.Parameters, literal.Types, literal.Defaults = p.parseFunctionParameters(!p.r)
+
+	if !p.r && p.peekTokenIs(token.COLON) {
+		p.nextToken()
+
+		if literal.Result = p.parseType(); nil == literal.Result {
+			return nil
+		}
+	}
+
 	literal.Body = p.parseBlockStatement()
 
 	return literal
 }
 
+// parseArgument : `name = value` names the argument, the name being either an identifier or a string, as in R
+func (p *Parser) parseArgument() ast.Expression {
+	if !(p.currentTokenIs(token.IDENTIFIER) || p.currentTokenIs(token.STRING)) || !p.peekTokenIs(token.EQUAL) {
+		value := p.parseExpression(LOWEST)
+
+		if p.peekTokenIs(token.EQUAL) {
+			message := fmt.Sprintf("only a name or a string can name an argument at line %d, column %d", p.peekToken.Line, p.peekToken.Column)
+			p.errors = append(p.errors, message)
+
+			p.nextToken()
+			p.nextToken()
+			p.parseExpression(LOWEST)
+		}
+
+		return value
+	}
+
+	argument := &ast.NamedArgument{
+		Token: p.currentToken,
+		Name:  p.currentToken.Literal,
+	}
+
+	p.nextToken()
+	p.nextToken()
+
+	argument.Value = p.parseExpression(LOWEST)
+
+	return argument
+}
+
 // parseCallArguments :
 func (p *Parser) parseCallArguments() []ast.Expression {
 	arguments := []ast.Expression{}
@@ -574,13 +1346,13 @@ func (p *Parser) parseCallArguments() []ast.Expression {
 
 	p.nextToken()
 
-	arguments = append(arguments, p.parseExpression(LOWEST))
+	arguments = append(arguments, p.parseArgument())
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
 		p.nextToken()
 
-		arguments = append(arguments, p.parseExpression(LOWEST))
+		arguments = append(arguments, p.parseArgument())
 	}
 
 	if !p.expectPeek(token.RIGHT_PARENTHESIS) {
@@ -624,7 +1396,7 @@ func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 		Token:    p.currentToken,
 		Function: function,
 	}
-	expression.Parameters = p.parseExpressionList(token.RIGHT_PARENTHESIS)
+	expression.Parameters = p.parseCallArguments()
 
 	return expression
 }
@@ -655,8 +1427,20 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 		Left:  left,
 	}
 
-	p.nextToken()
-	expression.Index = p.parseExpression(LOWEST)
+	if !p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		expression.Index = p.parseExpression(LOWEST)
+	}
+
+	if p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		expression.Matrix = true
+
+		if !p.peekTokenIs(token.RIGHT_BRACKET) {
+			p.nextToken()
+			expression.Column = p.parseExpression(LOWEST)
+		}
+	}
 
 	if !p.expectPeek(token.RIGHT_BRACKET) {
 		return nil
@@ -665,6 +1449,25 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	return expression
 }
 
+// parseDollarExpression : `frame$column` is just sugar to `frame["column"]`
+func (p *Parser) parseDollarExpression(left ast.Expression) ast.Expression {
+	expression := &ast.IndexExpression{
+		Token: p.currentToken,
+		Left:  left,
+	}
+
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+
+	expression.Index = &ast.StringLiteral{
+		Token: p.currentToken,
+		Value: p.currentToken.Literal,
+	}
+
+	return expression
+}
+
 // peekErrors :
 func (p *Parser) peekErrors(t token.TokenType) {
 	message := fmt.Sprintf("Expected next token to be %s, got '%s' instead", t, p.peekToken.Type)
@@ -677,9 +1480,9 @@ func (p *Parser) currentErrors(t token.TokenType) {
 	p.errors = append(p.errors, message)
 }
 
-// Errors :
+// Errors : the problems found by the lexer come first, as they are usually the cause of the others
 func (p *Parser) Errors() []string {
-	return p.errors
+	return append(append([]string{}, p.l.Errors()...), p.errors...)
 }
 
 // ParseProgram :
@@ -715,13 +1518,17 @@ func InitializeParser(l *lexer.Lexer) *Parser {
 	p.infixParserFunction = make(map[token.TokenType]infixParserFunction)
 
 	p.registerPrefix(token.IDENTIFIER, p.parseIdentifier)
+	p.registerPrefix(token.ELLIPSIS, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.DOUBLE, p.parseDoubleLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
+	p.registerPrefix(token.NA, p.parseNotAvailable)
 	p.registerPrefix(token.LEFT_PARENTHESIS, p.parseGroupedExpression)
 	p.registerPrefix(token.IF, p.parseConditionalExpression)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
 	p.registerPrefix(token.LEFT_BRACKET, p.parseArrayLiteral)
@@ -731,12 +1538,16 @@ func InitializeParser(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
 	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
+	p.registerInfix(token.MATRIX_PRODUCT, p.parseInfixExpression)
+	p.registerInfix(token.SPECIAL, p.parseSpecialExpression)
+	p.registerInfix(token.PIPE, p.parsePipeExpression)
 	p.registerInfix(token.DOUBLE_EQUAL, p.parseInfixExpression)
 	p.registerInfix(token.DIFFERENT, p.parseInfixExpression)
 	p.registerInfix(token.LESS_THAN, p.parseInfixExpression)
 	p.registerInfix(token.GREATER_THAN, p.parseInfixExpression)
 	p.registerInfix(token.LEFT_PARENTHESIS, p.parseCallExpression)
 	p.registerInfix(token.LEFT_BRACKET, p.parseIndexExpression)
+	p.registerInfix(token.DOLLAR, p.parseDollarExpression)
 
 	return p
 }
@@ -589,6 +589,30 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"a * b * c",
 			"((a * b) * c)",
 		},
+		{
+			"a * b %*% c",
+			"(a * (b %*% c))",
+		},
+		{
+			"-a %*% b + c",
+			"(((-a) %*% b) + c)",
+		},
+		{
+			"a %+% b * c",
+			"(%+%(a, b) * c)",
+		},
+		{
+			"x |> f(y)",
+			"f(x, y)",
+		},
+		{
+			"x + 1 |> f() |> g(2)",
+			"(x + g(f(1), 2))",
+		},
+		{
+			"x %>% f %>% g(y)",
+			"g(f(x), y)",
+		},
 		{
 			"a * b / c",
 			"((a * b) / c)",
@@ -1004,6 +1028,294 @@ func TestFunctionParametersParsing(t *testing.T) {
 	}
 }
 
+func TestDefaultsAndNamedArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"function(x, n = 10) { x }",
+			"function(x, n = 10) x",
+		},
+		{
+			"(x, n = length(x) - 1) { n }",
+			"function(x, n = (length(x) - 1)) n",
+		},
+		{
+			"f(n = 3, x = y)",
+			"f(n = 3, x = y)",
+		},
+		{
+			"f(x, na.rm = TRUE, \"use\" = 1 + 2)",
+			"f(x, na.rm = TRUE, use = (1 + 2))",
+		},
+		{
+			"x |> f(n = 1)",
+			"f(x, n = 1)",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.InitializeLexer(tt.input)
+		p := InitializeParser(l)
+		program := p.ParseProgram()
+
+		checkParserErrors(t, p)
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestVariadicFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"function(x, ...) { list(...) }",
+			"function(x, ...) list(...)",
+		},
+		{
+			"(...) { ..1 }",
+			"function(...) ..1",
+		},
+		{
+			"function(..., sep = \" \") { paste(..., sep = sep) }",
+			"function(..., sep =  ) paste(..., sep = sep)",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.InitializeLexer(tt.input)
+		p := InitializeParser(l)
+		program := p.ParseProgram()
+
+		checkParserErrors(t, p)
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	p := InitializeParser(lexer.InitializeLexer("function(... = 1) { 1 }"))
+	p.ParseProgram()
+
+	if errors := p.Errors(); 1 != len(errors) || "`...` can not have a default value at line 1, column 14" != errors[0] {
+		t.Fatalf("wrong errors, got=%v", errors)
+	}
+
+	p = InitializeParser(lexer.InitializeLexer("function(x, x) { x }; function(..., ...) { 1 }"))
+	p.ParseProgram()
+
+	expected := []string{
+		"repeated formal argument 'x' at line 1, column 13",
+		"repeated formal argument '...' at line 1, column 37",
+	}
+
+	if errors := p.Errors(); fmt.Sprint(expected) != fmt.Sprint(errors) {
+		t.Fatalf("wrong errors, got=%q", errors)
+	}
+}
+
+func TestModules(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"export let double <- function(x) { x * 2 }",
+			"export let double <- function<double>(x) (x * 2)",
+		},
+		{
+			"export scale <- 10",
+			"export CONST scale <- 10",
+		},
+		{
+			`import { double, scale } from "./utils.tr"`,
+			`import { double, scale } from "./utils.tr"`,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.InitializeLexer(tt.input)
+		p := InitializeParser(l)
+		program := p.ParseProgram()
+
+		checkParserErrors(t, p)
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"export 1 + 2",
+			"only bindings can be exported at line 1, column 1",
+		},
+		{
+			`import { a } "./utils.tr"`,
+			"expected `from` after the imported names at line 1, column 14",
+		},
+	}
+
+	for _, tt := range errors {
+		p := InitializeParser(lexer.InitializeLexer(tt.input))
+		p.ParseProgram()
+
+		if errors := p.Errors(); 0 == len(errors) || tt.expected != errors[0] {
+			t.Errorf("wrong errors for %q, got=%v", tt.input, errors)
+		}
+	}
+}
+
+func TestDeclarations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"declare function mean(x: numeric[], na.rm: logical = FALSE): double",
+			"declare function mean(x: numeric[], na.rm: logical = FALSE): double",
+		},
+		{
+			"declare function paste(..., sep: character = \" \"); declare function Sys.time()",
+			"declare function paste(..., sep: character =  )declare function Sys.time()",
+		},
+		{
+			"declare function lapply(X: list, FUN: function, ...): list",
+			"declare function lapply(X: list, FUN: function, ...): list",
+		},
+		{
+			"declare function parse(x: Option[character][]): Result[double,Option[integer]]",
+			"declare function parse(x: Option[character][]): Result[double, Option[integer]]",
+		},
+		{
+			"declare function sum<T: numeric>(x: T[], na.rm: logical = FALSE): T",
+			"declare function sum<T: numeric>(x: T[], na.rm: logical = FALSE): T",
+		},
+		{
+			"let first <- function<T>(xs: T[], n: integer = 1): T { xs[n] }",
+			"let first <- function<first><T>(xs: T[], n: integer = 1): T (xs[n])",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.InitializeLexer(tt.input)
+		p := InitializeParser(l)
+		program := p.ParseProgram()
+
+		checkParserErrors(t, p)
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"declare let x <- 1",
+			"only functions can be declared at line 1, column 9",
+		},
+		{
+			"declare function f(x: numeric[2])",
+			"Expected next token to be ], got 'INT' instead",
+		},
+		{
+			"declare function f(x: Option[double)",
+			"Expected next token to be ], got ')' instead",
+		},
+		{
+			"declare function f(...: any = 1)",
+			"`...` can not have a default value at line 1, column 29",
+		},
+		{
+			"function<T(x: T) { x }",
+			"Expected next token to be >, got '(' instead",
+		},
+	}
+
+	for _, tt := range errors {
+		p := InitializeParser(lexer.InitializeLexer(tt.input))
+		p.ParseProgram()
+
+		if errors := p.Errors(); 0 == len(errors) || tt.expected != errors[0] {
+			t.Errorf("wrong errors for %q, got=%v", tt.input, errors)
+		}
+	}
+}
+
+func TestAlgebraicTypes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"type Shape = Circle(r: double) | Rect(w: double, h: double) | Empty; export type Color = Red() | Green",
+			"type Shape = Circle(r: double) | Rect(w: double, h: double) | Emptyexport type Color = Red() | Green",
+		},
+		{
+			"match (s) {\n  Circle(r) -> r * r,\n  Rect(w, _) -> { w }\n  _ -> 0\n}",
+			"match (s) { Circle(r) -> (r * r), Rect(w, _) -> w, _ -> 0 }",
+		},
+		{
+			"let type <- 1; f(type = type)",
+			"let type <- 1f(type = type)",
+		},
+		{
+			"type Numbers = double[]; type Named<T> = Result[T, character]; type Tree<T: numeric> = Leaf(value: T) | Node(left: Tree[T], right: Tree[T])",
+			"type Numbers = double[]type Named<T> = Result[T, character]type Tree<T: numeric> = Leaf(value: T) | Node(left: Tree[T], right: Tree[T])",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.InitializeLexer(tt.input)
+		p := InitializeParser(l)
+		program := p.ParseProgram()
+
+		checkParserErrors(t, p)
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"type Shape = Circle(r = 1)",
+			"fields of Circle can not be `...` nor have a default value at line 1, column 21",
+		},
+		{
+			"type Shape <- Circle(r)",
+			"Expected next token to be =, got '<-' instead",
+		},
+		{
+			"match (s) { Circle(r) => r }",
+			"Expected next token to be ->, got '=' instead",
+		},
+	}
+
+	for _, tt := range errors {
+		p := InitializeParser(lexer.InitializeLexer(tt.input))
+		p.ParseProgram()
+
+		if errors := p.Errors(); 0 == len(errors) || tt.expected != errors[0] {
+			t.Errorf("wrong errors for %q, got=%v", tt.input, errors)
+		}
+	}
+}
+
 // TestCallExporessionParsing :
 func TestCallExporessionParsing(t *testing.T) {
 	input := "add(1, 2 * 3, 4 + 5)"
@@ -1131,6 +1443,74 @@ func TestParsingIndexExpressions(t *testing.T) {
 	}
 }
 
+// TestDoubleLiteralExpression :
+func TestDoubleLiteralExpression(t *testing.T) {
+	input := "3.25;"
+
+	l := lexer.InitializeLexer(input)
+	p := InitializeParser(l)
+	program := p.ParseProgram()
+
+	checkParserErrors(t, p)
+
+	statement, ok := program.Statements[0].(*ast.ExpressionStatement)
+
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.ExpressionStatement, got=%T", program.Statements[0])
+	}
+
+	literal, ok := statement.Expression.(*ast.DoubleLiteral)
+
+	if !ok {
+		t.Fatalf("expression is not *ast.DoubleLiteral, got=%T", statement.Expression)
+	}
+
+	if 3.25 != literal.Value {
+		t.Errorf("literal.Value not %f, got=%f", 3.25, literal.Value)
+	}
+}
+
+// TestParsingMatrixIndexExpressions :
+func TestParsingMatrixIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`frame[1, "x"]`,
+			`(frame[1, x])`,
+		},
+		{
+			`frame[, "x"]`,
+			`(frame[, x])`,
+		},
+		{
+			`frame[[0, 1], ]`,
+			`(frame[[0, 1], ])`,
+		},
+		{
+			`frame$x`,
+			`(frame[x])`,
+		},
+		{
+			`frame$x[0] + 1`,
+			`(((frame[x])[0]) + 1)`,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.InitializeLexer(tt.input)
+		p := InitializeParser(l)
+		program := p.ParseProgram()
+
+		checkParserErrors(t, p)
+
+		if tt.expected != program.String() {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
 // TestFunctionLiteralWithName :
 func TestFunctionLiteralWithName(t *testing.T) {
 	tests := []struct {
@@ -1322,3 +1702,191 @@ func TestPointFreeNotationPartialApplication(t *testing.T) {
 	// 	return
 	// }
 }
+
+func TestPipeErrors(t *testing.T) {
+	l := lexer.InitializeLexer(`x |> f`)
+	p := InitializeParser(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+
+	if 1 != len(errors) || "the pipe operator requires a function call as its right side at line 1, column 3" != errors[0] {
+		t.Fatalf("wrong errors, got=%v", errors)
+	}
+}
+
+func TestLexerErrors(t *testing.T) {
+	l := lexer.InitializeLexer(`x <- "unterminated`)
+	p := InitializeParser(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+
+	if 1 != len(errors) {
+		t.Fatalf("wrong number of errors, got=%v", errors)
+	}
+
+	if "unterminated string at line 1, column 6" != errors[0] {
+		t.Fatalf("wrong error, got=%q", errors[0])
+	}
+}
+
+func TestRSource(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"square <- function(x) x * x # the square\nscale = function(xs, by = 2) {\n  xs * by\n}",
+			"let square <- function<square>(x) (x * x)let scale <- function<scale>(xs, by = 2) (xs * by)",
+		},
+		{
+			"c(1, 2) -> v\n\"w\" <- c(a = 1)\nv[[1]] + v[i]\nm[1, j + 1]\nd$col",
+			"let v <- [1, 2]let w <- list(a = 1)((v[0]) + (v[(i - 1)]))(m[0, ((j + 1) - 1)])(d[col])",
+		},
+		{
+			"ages <- c(ana = 31, 45, bob = NA)\nages[\"bob\"]",
+			"let ages <- list(ana = 31, 45, bob = NA)(ages[bob])",
+		},
+		{
+			"f <- function(x) {\n  if (x == 0) {\n    return(1)\n  } else\n    x\n}\nf(x = 1,\n  2)",
+			"let f <- function<f>(x) if(x == 0) return 1else xf(x = 1, 2)",
+		},
+		{
+			"y <- (x)\n-y",
+			"let y <- x(-y)",
+		},
+		{
+			"x <- NULL\nc(Inf, -Inf, T, F)",
+			"let x <- NULL[Inf, (-Inf), T, F]",
+		},
+	}
+
+	for _, tt := range tests {
+		p := InitializeRParser(lexer.InitializeRLexer(tt.input))
+		program := p.ParseProgram()
+
+		checkParserErrors(t, p)
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			"for (i in x) {\n  print(i)\n}\nwhile (TRUE) next\nrepeat break",
+			[]string{
+				"`for` loops are not supported at line 1, column 1",
+				"`while` loops are not supported at line 4, column 1",
+				"`repeat` loops are not supported at line 5, column 1",
+			},
+		},
+		{
+			"x <<- 1\ny <- x ^ 2 && TRUE",
+			[]string{
+				"`<<-` is not supported at line 1, column 3",
+				"`^` is not supported at line 2, column 8",
+				"`&&` is not supported at line 2, column 12",
+			},
+		},
+		{
+			"assign(\"x\", 1)\nnames(x) <- \"a\"\na <- b <- 1\nx[-1]\nx[]",
+			[]string{
+				"`assign` is not supported at line 1, column 1",
+				"assignments to anything but a name are not supported at line 2, column 10",
+				"chained assignments are not supported at line 3, column 8",
+				"negative or zero indices are not supported at line 4, column 3",
+				"empty indices are not supported at line 5, column 2",
+			},
+		},
+		{
+			"x %% 2\ny <- x %/% 2\nx %in% y",
+			[]string{
+				"`%%` is not supported at line 1, column 3",
+				"`%/%` is not supported at line 2, column 8",
+				"`%in%` is not supported at line 3, column 3",
+			},
+		},
+	}
+
+	for _, tt := range errors {
+		p := InitializeRParser(lexer.InitializeRLexer(tt.input))
+		p.ParseProgram()
+
+		if errors := p.Errors(); fmt.Sprint(errors) != fmt.Sprint(tt.expected) {
+			t.Errorf("wrong errors for %q, got=%q", tt.input, errors)
+		}
+	}
+}
+
+func TestAssignments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"x = 1; let y = 2; 3 -> z",
+			"CONST x <- 1let y <- 2CONST z <- 3",
+		},
+		{
+			"function(a) { a * 2 } -> double; export scale = 10",
+			"CONST double <- function<double>(a) (a * 2)export CONST scale <- 10",
+		},
+		{
+			"f(n = 1, \"m\" = x == 2)",
+			"f(n = 1, m = (x == 2))",
+		},
+	}
+
+	for _, tt := range tests {
+		p := InitializeParser(lexer.InitializeLexer(tt.input))
+		program := p.ParseProgram()
+
+		checkParserErrors(t, p)
+
+		if actual := program.String(); actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			"f(x) = 1; x[1] <- 2; 1 -> f(x); g(1)",
+			[]string{
+				"only a name can be bound by `=` at line 1, column 6",
+				"only a name can be bound by `<-` at line 1, column 16",
+				"only a name can be bound by `->` at line 1, column 24",
+			},
+		},
+		{
+			"x = y = 1; let a <- 1 -> b",
+			[]string{
+				"chained assignments are ambiguous, bind one name at a time at line 1, column 7",
+				"chained assignments are ambiguous, bind one name at a time at line 1, column 23",
+			},
+		},
+		{
+			"if (x = 1) { 2 }; f(g(x) = 1)",
+			[]string{
+				"`=` does not compare, `==` does, at line 1, column 7",
+				"only a name or a string can name an argument at line 1, column 26",
+			},
+		},
+	}
+
+	for _, tt := range errors {
+		p := InitializeParser(lexer.InitializeLexer(tt.input))
+		p.ParseProgram()
+
+		if errors := p.Errors(); fmt.Sprint(errors) != fmt.Sprint(tt.expected) {
+			t.Errorf("wrong errors for %q, got=%q", tt.input, errors)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"../ast"
+	"../lexer"
+	"../token"
+)
+
+// unsupportedStatements : the R statements TypeR has no counterpart for, along with how they are reported
+var unsupportedStatements = map[string]string{
+	"for":    "`for` loops are",
+	"while":  "`while` loops are",
+	"repeat": "`repeat` loops are",
+	"break":  "`break` is",
+	"next":   "`next` is",
+}
+
+// unsupportedFunctions : the R functions binding names in ways TypeR can not follow
+var unsupportedFunctions = map[string]bool{
+	"assign": true,
+}
+
+// unsupportedOperators : the R operators TypeR has no counterpart for
+var unsupportedOperators = map[string]bool{
+	"%%":   true,
+	"%/%":  true,
+	"%in%": true,
+}
+
+// unsupported : reports the construct starting at the token
+func (p *Parser) unsupported(what string, at token.Token) {
+	message := fmt.Sprintf("%s not supported at line %d, column %d", what, at.Line, at.Column)
+	p.errors = append(p.errors, message)
+}
+
+// skipRStatement : leaves the parser on the last token of the statement it is in, so an unsupported one can be
+// reported once and the rest of the source still be read
+func (p *Parser) skipRStatement() {
+	depth := 0
+
+	for !p.currentTokenIs(token.EOF) {
+		switch p.currentToken.Type {
+		case token.LEFT_PARENTHESIS, token.LEFT_BRACKET, token.LEFT_BRACE:
+			depth++
+		case token.RIGHT_PARENTHESIS, token.RIGHT_BRACKET, token.RIGHT_BRACE:
+			depth--
+		}
+
+		if 0 >= depth && (p.peekToken.Newline || p.peekTokenIs(token.SEMICOLON) || p.peekTokenIs(token.RIGHT_BRACE) || p.peekTokenIs(token.EOF)) {
+			return
+		}
+
+		p.nextToken()
+	}
+}
+
+// parseRStatement : an R statement, every assignment being a `let` as R lets names be bound again
+func (p *Parser) parseRStatement() ast.Statement {
+	if p.currentTokenIs(token.RETURN) {
+		return p.parseReturnStatement()
+	}
+
+	if what, ok := unsupportedStatements[p.currentToken.Literal]; ok && p.currentTokenIs(token.IDENTIFIER) {
+		p.unsupported(what, p.currentToken)
+		p.skipRStatement()
+
+		return nil
+	}
+
+	statement := &ast.ExpressionStatement{
+		Token: p.currentToken,
+	}
+
+	statement.Expression = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.ASSIGN) || p.peekTokenIs(token.EQUAL) || p.peekTokenIs(token.RIGHT_ASSIGN) {
+		p.nextToken()
+
+		return p.parseRAssignment(statement.Expression)
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return statement
+}
+
+// parseRAssignment : `name <- value`, `name = value` or `value -> name`, the parser being on the operator; names
+// may be quoted, as in `"name" <- value`
+func (p *Parser) parseRAssignment(left ast.Expression) ast.Statement {
+	operator := p.currentToken
+
+	p.nextToken()
+
+	right := p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.ASSIGN) || p.peekTokenIs(token.EQUAL) || p.peekTokenIs(token.RIGHT_ASSIGN) {
+		p.unsupported("chained assignments are", p.peekToken)
+		p.skipRStatement()
+
+		return nil
+	}
+
+	target, value := left, right
+
+	if token.RIGHT_ASSIGN == operator.Type {
+		target, value = right, left
+	}
+
+	statement := &ast.LetStatement{
+		Token: token.Token{
+			Type:    token.LET,
+			Literal: "let",
+			Line:    operator.Line,
+			Column:  operator.Column,
+		},
+		Value: value,
+	}
+
+	switch target := target.(type) {
+	case *ast.Identifier:
+		statement.Name = target
+	case *ast.StringLiteral:
+		statement.Name = &ast.Identifier{
+			Token: target.Token,
+			Value: target.Value,
+		}
+	default:
+		p.unsupported("assignments to anything but a name are", operator)
+
+		return nil
+	}
+
+	if functionLiteral, ok := statement.Value.(*ast.FunctionLiteral); ok {
+		functionLiteral.Name = statement.Name.Value
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return statement
+}
+
+// parseRIdentifier : the names R keeps for its constants are read as the literals they stand for
+func (p *Parser) parseRIdentifier() ast.Expression {
+	switch p.currentToken.Literal {
+	case "NULL":
+		return &ast.Null{
+			Token: p.currentToken,
+		}
+	case "Inf":
+		return &ast.DoubleLiteral{
+			Token: p.currentToken,
+			Value: math.Inf(1),
+		}
+	case "T", "F":
+		return &ast.Boolean{
+			Token: p.currentToken,
+			Value: "T" == p.currentToken.Literal,
+		}
+	}
+
+	return p.parseIdentifier()
+}
+
+// parseRSpecialExpression : `x %op% y`, the operators TypeR has no counterpart for being reported
+func (p *Parser) parseRSpecialExpression(left ast.Expression) ast.Expression {
+	if unsupportedOperators[p.currentToken.Literal] {
+		p.unsupported(fmt.Sprintf("`%s` is", p.currentToken.Literal), p.currentToken)
+	}
+
+	return p.parseSpecialExpression(left)
+}
+
+// parseRCallExpression : `c(1, 2, 3)` is a vector, while `c(a = 1, b = 2)` is a list, as TypeR vectors have no
+// names; it can be indexed by both the names and the positions of its elements
+func (p *Parser) parseRCallExpression(function ast.Expression) ast.Expression {
+	identifier, named := function.(*ast.Identifier)
+
+	if named && unsupportedFunctions[identifier.Value] {
+		p.unsupported(fmt.Sprintf("`%s` is", identifier.Value), identifier.Token)
+	}
+
+	call := p.parseCallExpression(function).(*ast.CallExpression)
+
+	if !named || "c" != identifier.Value || nil == call.Parameters {
+		return call
+	}
+
+	for _, parameter := range call.Parameters {
+		if _, ok := parameter.(*ast.NamedArgument); ok {
+			call.Function = &ast.Identifier{
+				Token: token.Token{
+					Type:    token.IDENTIFIER,
+					Literal: "list",
+					Line:    identifier.Token.Line,
+					Column:  identifier.Token.Column,
+				},
+				Value: "list",
+			}
+
+			return call
+		}
+	}
+
+	return &ast.ArrayLiteral{
+		Token: token.Token{
+			Type:    token.LEFT_BRACKET,
+			Literal: "[",
+			Line:    identifier.Token.Line,
+			Column:  identifier.Token.Column,
+		},
+		Elements: call.Parameters,
+	}
+}
+
+// parseRIndexExpression : `x[i]`, `x[[i]]` or `m[i, j]`, whose indices start at 1 where TypeR's start at 0
+func (p *Parser) parseRIndexExpression(left ast.Expression) ast.Expression {
+	bracket := p.currentToken
+	double := p.peekTokenIs(token.LEFT_BRACKET)
+
+	if double {
+		p.nextToken()
+	}
+
+	if p.peekTokenIs(token.RIGHT_BRACKET) {
+		p.unsupported("empty indices are", bracket)
+		p.skipRStatement()
+
+		return nil
+	}
+
+	expression, ok := p.parseIndexExpression(left).(*ast.IndexExpression)
+
+	if !ok || double && !p.expectPeek(token.RIGHT_BRACKET) {
+		return nil
+	}
+
+	expression.Token = bracket
+	expression.Index = p.shiftIndex(expression.Index)
+	expression.Column = p.shiftIndex(expression.Column)
+
+	return expression
+}
+
+// shiftIndex : the R index as TypeR takes it, `1` becoming `0` and `i` becoming `i - 1`; names and the empty
+// positions of matrices are left as they are
+func (p *Parser) shiftIndex(index ast.Expression) ast.Expression {
+	switch index := index.(type) {
+	case nil, *ast.StringLiteral:
+		return index
+	case *ast.IntegerLiteral:
+		if 1 > index.Value {
+			p.unsupported("negative or zero indices are", index.Token)
+
+			return index
+		}
+
+		tok := index.Token
+		tok.Literal = strconv.FormatInt(index.Value-1, 10)
+
+		return &ast.IntegerLiteral{
+			Token: tok,
+			Value: index.Value - 1,
+		}
+	case *ast.PrefixExpression:
+		if "-" == index.Operator {
+			p.unsupported("negative or zero indices are", index.Token)
+
+			return index
+		}
+	}
+
+	return &ast.InfixExpression{
+		Token: token.Token{
+			Type:    token.MINUS,
+			Literal: "-",
+		},
+		Operator: "-",
+		Left:     index,
+		Right: &ast.IntegerLiteral{
+			Token: token.Token{
+				Type:    token.INT,
+				Literal: "1",
+			},
+			Value: 1,
+		},
+	}
+}
+
+// InitializeRParser : a parser reading R source, as a lexer made by lexer.InitializeRLexer gives it, into the same
+// statements TypeR source gives; what TypeR has no counterpart for is reported along with where it is found
+func InitializeRParser(l *lexer.Lexer) *Parser {
+	p := InitializeParser(l)
+	p.r = true
+
+	delete(p.prefixParserFunction, token.LEFT_BRACKET)
+	delete(p.prefixParserFunction, token.POINT)
+
+	p.registerPrefix(token.IDENTIFIER, p.parseRIdentifier)
+	p.registerInfix(token.SPECIAL, p.parseRSpecialExpression)
+	p.registerInfix(token.LEFT_PARENTHESIS, p.parseRCallExpression)
+	p.registerInfix(token.LEFT_BRACKET, p.parseRIndexExpression)
+
+	return p
+}
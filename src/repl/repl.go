@@ -4,9 +4,13 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"strings"
 
+	"../checker"
 	"../compiler"
+	"../emitter"
 	"../lexer"
+	"../module"
 	"../object"
 	"../parser"
 	"../virtualmachine"
@@ -22,12 +26,21 @@ func printParseErrors(out io.Writer, errors []string) {
 	}
 }
 
+// printWarnings : the warnings of the checker do not stop the program from running
+func printWarnings(out io.Writer, warnings []string) {
+	for _, message := range warnings {
+		io.WriteString(out, "Warning: "+message+"\n")
+	}
+}
+
 // Start :
 func Start(in io.Reader, out io.Writer) {
 	scanner := bufio.NewScanner(in)
 	constants := []object.Object{}
 	globals := make([]object.Object, virtualmachine.GlobalSize)
 	symbolTable := compiler.InitializeSymbolTable()
+	random := object.InitializeRandom()
+	shapes := checker.InitializeChecker()
 
 	for index, value := range object.Builtins {
 		symbolTable.DefineBuiltin(index, value.Name)
@@ -53,6 +66,14 @@ func Start(in io.Reader, out io.Writer) {
 			continue
 		}
 
+		if errors := shapes.Check(program); 0 != len(errors) {
+			printParseErrors(out, errors)
+
+			continue
+		}
+
+		printWarnings(out, shapes.Warnings())
+
 		comp := compiler.InitializeWithState(symbolTable, constants)
 		err := comp.Compile(program)
 
@@ -65,7 +86,7 @@ func Start(in io.Reader, out io.Writer) {
 		code := comp.Bytecode()
 		constants = code.Constants
 
-		machine := virtualmachine.InitializeWithGlobalStore(code, globals)
+		machine := virtualmachine.InitializeWithState(code, globals, random)
 		err = machine.Run()
 
 		if nil != err {
@@ -79,3 +100,78 @@ func Start(in io.Reader, out io.Writer) {
 		io.WriteString(out, "\n")
 	}
 }
+
+// Run : runs the program in the file at path, the modules it imports being found relative to it
+func Run(path string) error {
+	path, err := module.Resolve("", path)
+
+	if nil != err {
+		return err
+	}
+
+	program, err := module.Load(path)
+
+	if nil != err {
+		return err
+	}
+
+	check := checker.InitializeChecker()
+	check.Path = path
+
+	if errors := check.Check(program); 0 != len(errors) {
+		return fmt.Errorf("%s", strings.Join(errors, "\n"))
+	}
+
+	printWarnings(object.Messages, check.Warnings())
+
+	symbolTable := compiler.InitializeSymbolTable()
+	symbolTable.Path = path
+
+	for index, value := range object.Builtins {
+		symbolTable.DefineBuiltin(index, value.Name)
+	}
+
+	comp := compiler.InitializeWithState(symbolTable, []object.Object{})
+
+	if err := comp.Compile(program); nil != err {
+		return err
+	}
+
+	machine := virtualmachine.InitializeVirtualMachine(comp.Bytecode())
+
+	return machine.Run()
+}
+
+// Emit : writes the R source of the program in the file, once checked
+func Emit(path string, out io.Writer) error {
+	path, err := module.Resolve("", path)
+
+	if nil != err {
+		return err
+	}
+
+	program, err := module.Load(path)
+
+	if nil != err {
+		return err
+	}
+
+	check := checker.InitializeChecker()
+	check.Path = path
+
+	if errors := check.Check(program); 0 != len(errors) {
+		return fmt.Errorf("%s", strings.Join(errors, "\n"))
+	}
+
+	printWarnings(object.Messages, check.Warnings())
+
+	source, err := emitter.Emit(program)
+
+	if nil != err {
+		return err
+	}
+
+	_, err = io.WriteString(out, source)
+
+	return err
+}
@@ -0,0 +1,208 @@
+package rpackage
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Documentation : what a help page of the package tells about the functions it documents
+type Documentation struct {
+	Aliases []string
+	// Arguments holds the description of every argument, by name
+	Arguments map[string]string
+	Value     string
+	Usage     string
+}
+
+// command : an Rd markup command, as `\code` in `\code{x}`
+var command = regexp.MustCompile(`\\[a-zA-Z]+`)
+
+// stripRdComments : the Rd source without its comments, which start with `%` unless escaped
+func stripRdComments(source string) string {
+	lines := strings.Split(source, "\n")
+
+	for index, line := range lines {
+		for position := 0; position < len(line); position++ {
+			if '\\' == line[position] {
+				position++
+			} else if '%' == line[position] {
+				lines[index] = line[:position]
+
+				break
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// braced : what is between the brace at start and the one closing it, escaped braces not counting
+func braced(source string, start int) (string, int, bool) {
+	depth := 0
+
+	for index := start; index < len(source); index++ {
+		switch source[index] {
+		case '\\':
+			index++
+		case '{':
+			depth++
+		case '}':
+			depth--
+
+			if 0 == depth {
+				return source[start+1 : index], index + 1, true
+			}
+		}
+	}
+
+	return "", len(source), false
+}
+
+// sections : the contents of every `\name{...}` section of the Rd source
+func sections(source string, name string) []string {
+	found := []string{}
+	pattern := regexp.MustCompile(`\\` + name + `\s*\{`)
+
+	for _, match := range pattern.FindAllStringIndex(source, -1) {
+		if content, _, ok := braced(source, match[1]-1); ok {
+			found = append(found, content)
+		}
+	}
+
+	return found
+}
+
+// text : the Rd markup as plain text, `\dots` standing for `...`
+func text(markup string) string {
+	markup = strings.Replace(markup, `\dots`, "...", -1)
+	markup = strings.Replace(markup, `\ldots`, "...", -1)
+	markup = command.ReplaceAllString(markup, "")
+	markup = strings.NewReplacer(`\{`, "{", `\}`, "}", `\%`, "%", "{", "", "}", "").Replace(markup)
+
+	return strings.Join(strings.Fields(markup), " ")
+}
+
+// items : the arguments described by `\item{x, y}{description}`, by name
+func items(arguments string) map[string]string {
+	described := map[string]string{}
+	start := 0
+
+	for {
+		position := strings.Index(arguments[start:], `\item`)
+
+		if -1 == position {
+			return described
+		}
+
+		start += position + len(`\item`)
+
+		for start < len(arguments) && ' ' == arguments[start] {
+			start++
+		}
+
+		names, next, ok := braced(arguments, start)
+
+		if !ok || next >= len(arguments) || '{' != arguments[next] {
+			continue
+		}
+
+		description, next, ok := braced(arguments, next)
+
+		if !ok {
+			continue
+		}
+
+		for _, name := range strings.Split(text(names), ",") {
+			described[strings.TrimSpace(name)] = text(description)
+		}
+
+		start = next
+	}
+}
+
+// ReadDocumentation : the Rd source of a help page
+func ReadDocumentation(source string) *Documentation {
+	source = stripRdComments(source)
+	documentation := &Documentation{
+		Arguments: map[string]string{},
+	}
+
+	for _, alias := range append(sections(source, "name"), sections(source, "alias")...) {
+		documentation.Aliases = append(documentation.Aliases, text(alias))
+	}
+
+	for _, arguments := range sections(source, "arguments") {
+		for name, description := range items(arguments) {
+			documentation.Arguments[name] = description
+		}
+	}
+
+	if values := sections(source, "value"); 0 != len(values) {
+		documentation.Value = text(values[0])
+	}
+
+	if usages := sections(source, "usage"); 0 != len(usages) {
+		documentation.Usage = strings.Replace(strings.Replace(usages[0], `\dots`, "...", -1), `\ldots`, "...", -1)
+	}
+
+	return documentation
+}
+
+// suggestions : the words telling the type of a value, as they are usually found in the help pages
+var suggestions = []struct {
+	word       string
+	suggestion string
+}{
+	{"logical", "logical"},
+	{"character", "character"},
+	{"string", "character"},
+	{"numeric", "numeric"},
+	{"number", "numeric"},
+	{"integer", "integer"},
+	{"function", "function"},
+	{"list", "list"},
+}
+
+// Suggest : the type the first sentence of a description suggests, the first word telling one being taken; a
+// vector of them if it talks about vectors, and `any` when nothing is suggested
+func Suggest(description string) string {
+	sentence := strings.ToLower(description)
+
+	if end := strings.Index(sentence, ". "); -1 != end {
+		sentence = sentence[:end]
+	}
+
+	suggested, first := "any", len(sentence)
+
+	for _, suggestion := range suggestions {
+		if position := strings.Index(sentence, suggestion.word); -1 != position && position < first {
+			suggested, first = suggestion.suggestion, position
+		}
+	}
+
+	if "any" != suggested && strings.Contains(sentence, "vector") {
+		return suggested + "[]"
+	}
+
+	return suggested
+}
+
+// usage : the parameters of the function as its usage tells them, for those not written in R, as the primitives
+func (d *Documentation) usage(name string) ([]string, []string, bool) {
+	pattern := regexp.MustCompile(`(?m)^\s*` + regexp.QuoteMeta(name) + `\s*\(`)
+	match := pattern.FindStringIndex(d.Usage)
+
+	if nil == match {
+		return nil, nil, false
+	}
+
+	arguments, ok := enclosed(d.Usage, match[1]-1)
+
+	if !ok {
+		return nil, nil, false
+	}
+
+	parameters, defaults := formals(arguments)
+
+	return parameters, defaults, true
+}
@@ -0,0 +1,257 @@
+package rpackage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"../ast"
+	"../lexer"
+	"../parser"
+	"../token"
+)
+
+// Function : a function the package exports, with the R source of the default values of its parameters, "" for
+// those without one, and the types its help page suggests
+type Function struct {
+	Name       string
+	Parameters []string
+	Defaults   []string
+	Types      []string
+	Result     string
+}
+
+// Package : the functions an R package exports, as found in its sources
+type Package struct {
+	Name      string
+	Functions []*Function
+}
+
+// directive : the NAMESPACE directives telling what the package exports
+var directive = regexp.MustCompile(`(export|exportPattern)\s*\(`)
+
+// description : the name of the package, in its DESCRIPTION file
+var description = regexp.MustCompile(`(?m)^Package:\s*(\S+)`)
+
+// integer : R integers are written with a trailing `L`, `10L`
+var integer = regexp.MustCompile(`^-?[0-9]+L$`)
+
+// namespace : the names the NAMESPACE exports, along with the patterns of those it exports by pattern
+func namespace(source string) ([]string, []*regexp.Regexp, error) {
+	source = stripComments(source)
+	names, patterns := []string{}, []*regexp.Regexp{}
+
+	for _, match := range directive.FindAllStringSubmatchIndex(source, -1) {
+		arguments, ok := enclosed(source, match[1]-1)
+
+		if !ok {
+			return nil, nil, fmt.Errorf("unclosed %s in NAMESPACE", source[match[2]:match[3]])
+		}
+
+		for _, argument := range split(arguments, ',') {
+			argument = unquote(strings.TrimSpace(argument))
+
+			if "" == argument {
+				continue
+			}
+
+			if "export" == source[match[2]:match[3]] {
+				names = append(names, argument)
+
+				continue
+			}
+
+			pattern, err := regexp.Compile(strings.Replace(argument, `\\`, `\`, -1))
+
+			if nil != err {
+				return nil, nil, fmt.Errorf("unusable exportPattern %q in NAMESPACE: %s", argument, err)
+			}
+
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return names, patterns, nil
+}
+
+// Read : the package whose sources are in the directory, its `NAMESPACE`, `R/*.R` and `man/*.Rd` files; an exported
+// function defined nowhere, as a primitive, takes the parameters its usage tells, or just `...`
+func Read(directory string) (*Package, error) {
+	exports, err := ioutil.ReadFile(filepath.Join(directory, "NAMESPACE"))
+
+	if nil != err {
+		return nil, fmt.Errorf("could not read the NAMESPACE of the package: %s", err)
+	}
+
+	names, patterns, err := namespace(string(exports))
+
+	if nil != err {
+		return nil, err
+	}
+
+	pkg := &Package{
+		Name: filepath.Base(directory),
+	}
+
+	if content, err := ioutil.ReadFile(filepath.Join(directory, "DESCRIPTION")); nil == err {
+		if match := description.FindStringSubmatch(string(content)); nil != match {
+			pkg.Name = match[1]
+		}
+	}
+
+	defined := map[string]*Function{}
+	sources, _ := filepath.Glob(filepath.Join(directory, "R", "*.[RrSsq]"))
+
+	for _, path := range sources {
+		content, err := ioutil.ReadFile(path)
+
+		if nil != err {
+			return nil, err
+		}
+
+		for _, function := range functions(string(content)) {
+			defined[function.Name] = function
+		}
+	}
+
+	documented := map[string]*Documentation{}
+	pages, _ := filepath.Glob(filepath.Join(directory, "man", "*.Rd"))
+
+	for _, path := range pages {
+		content, err := ioutil.ReadFile(path)
+
+		if nil != err {
+			return nil, err
+		}
+
+		documentation := ReadDocumentation(string(content))
+
+		for _, alias := range documentation.Aliases {
+			documented[alias] = documentation
+		}
+	}
+
+	exported := map[string]bool{}
+
+	for _, name := range names {
+		exported[name] = true
+	}
+
+	for name := range defined {
+		for _, pattern := range patterns {
+			if pattern.MatchString(name) {
+				exported[name] = true
+			}
+		}
+	}
+
+	for name := range exported {
+		pkg.Functions = append(pkg.Functions, signature(name, defined[name], documented[name]))
+	}
+
+	sort.Slice(pkg.Functions, func(i, j int) bool {
+		return pkg.Functions[i].Name < pkg.Functions[j].Name
+	})
+
+	return pkg, nil
+}
+
+// signature : the function along with the types its help page suggests, `any` for those it does not tell about
+func signature(name string, defined *Function, documentation *Documentation) *Function {
+	function := &Function{
+		Name:       name,
+		Parameters: []string{"..."},
+		Defaults:   []string{""},
+		Result:     "any",
+	}
+
+	if nil != defined {
+		function.Parameters, function.Defaults = defined.Parameters, defined.Defaults
+	} else if nil != documentation {
+		if parameters, defaults, ok := documentation.usage(name); ok {
+			function.Parameters, function.Defaults = parameters, defaults
+		}
+	}
+
+	function.Types = make([]string, len(function.Parameters))
+
+	for index, parameter := range function.Parameters {
+		function.Types[index] = "any"
+
+		if nil != documentation && "..." != parameter {
+			function.Types[index] = Suggest(documentation.Arguments[parameter])
+		}
+	}
+
+	if nil != documentation && "" != documentation.Value {
+		function.Result = Suggest(documentation.Value)
+	}
+
+	return function
+}
+
+// name : as TypeR takes the name, backquoted when it is not an identifier
+func name(identifier string) string {
+	l := lexer.InitializeLexer(identifier)
+	tok := l.NextToken()
+
+	if (token.IDENTIFIER == tok.Type && identifier == tok.Literal || token.ELLIPSIS == tok.Type) && token.EOF == l.NextToken().Type {
+		return identifier
+	}
+
+	return "`" + identifier + "`"
+}
+
+// defaultValue : the default value written in R as TypeR takes it, NULL standing for any TypeR can not read; only
+// whether there is a default matters to the checker
+func defaultValue(value string) string {
+	switch {
+	case integer.MatchString(value):
+		value = strings.TrimSuffix(value, "L")
+	case "T" == value:
+		value = "TRUE"
+	case "F" == value:
+		value = "FALSE"
+	}
+
+	p := parser.InitializeParser(lexer.InitializeLexer(value))
+	program := p.ParseProgram()
+
+	if 0 != len(p.Errors()) || 1 != len(program.Statements) {
+		return "NULL"
+	}
+
+	if statement, ok := program.Statements[0].(*ast.ExpressionStatement); !ok || nil == statement.Expression {
+		return "NULL"
+	}
+
+	return value
+}
+
+// Declarations : the `.d.tr` source declaring the functions of the package
+func (p *Package) Declarations() string {
+	var out strings.Builder
+
+	for _, function := range p.Functions {
+		parameters := make([]string, len(function.Parameters))
+
+		for index, parameter := range function.Parameters {
+			parameters[index] = name(parameter)
+
+			if "..." != parameter {
+				parameters[index] += ": " + function.Types[index]
+			}
+
+			if "" != function.Defaults[index] {
+				parameters[index] += " = " + defaultValue(function.Defaults[index])
+			}
+		}
+
+		fmt.Fprintf(&out, "declare function %s(%s): %s\n", name(function.Name), strings.Join(parameters, ", "), function.Result)
+	}
+
+	return out.String()
+}
@@ -0,0 +1,108 @@
+package rpackage
+
+import (
+	"testing"
+
+	"../ast"
+	"../lexer"
+	"../parser"
+)
+
+func TestDeclarations(t *testing.T) {
+	tests := []struct {
+		directory string
+		name      string
+		expected  string
+	}{
+		{
+			"testdata/fakestats",
+			"fakestats",
+			"declare function `%+%`(e1: any, e2: any): any\n" +
+				"declare function mean(x: numeric[], trim: any = 0, na.rm: logical = FALSE, ...): numeric\n" +
+				"declare function sd(x: any, na.rm: any = FALSE, digits: any = 2): any\n" +
+				"declare function sum(..., na.rm: logical = FALSE): any\n" +
+				"declare function trimmed.mean(x: numeric[], trim: any = 0.1, side: character = c(\"both\", \"left\", \"right\"), tolerance: any = NULL): numeric\n",
+		},
+		{
+			"testdata/fakeutils",
+			"fakeutils",
+			"declare function first(xs: any): any\n" +
+				"declare function last(xs: any, n: any = 1): any\n",
+		},
+	}
+
+	for _, tt := range tests {
+		pkg, err := Read(tt.directory)
+
+		if nil != err {
+			t.Fatalf("could not read %s: %s", tt.directory, err)
+		}
+
+		if pkg.Name != tt.name {
+			t.Errorf("wrong package name, expected=%q, got=%q", tt.name, pkg.Name)
+		}
+
+		declarations := pkg.Declarations()
+
+		if declarations != tt.expected {
+			t.Errorf("wrong declarations for %s, expected=%q, got=%q", tt.directory, tt.expected, declarations)
+		}
+
+		p := parser.InitializeParser(lexer.InitializeLexer(declarations))
+		program := p.ParseProgram()
+
+		if 0 != len(p.Errors()) {
+			t.Fatalf("the declarations of %s can not be parsed: %v", tt.directory, p.Errors())
+		}
+
+		for _, statement := range program.Statements {
+			if declaration, ok := statement.(*ast.DeclareStatement); !ok || len(declaration.Parameters) != len(declaration.Types) {
+				t.Errorf("not a declaration: %s", statement)
+			}
+		}
+	}
+
+	if _, err := Read("testdata"); nil == err {
+		t.Fatalf("a directory without NAMESPACE must not be read")
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		description string
+		expected    string
+	}{
+		{"A numeric vector. Other objects are coerced to a list.", "numeric[]"},
+		{"a logical value indicating whether NA values should be stripped.", "logical"},
+		{"a character string naming the method, a function otherwise.", "character"},
+		{"the fraction (0 to 0.5) of observations to be trimmed.", "any"},
+		{"an R object.", "any"},
+		{"", "any"},
+	}
+
+	for _, tt := range tests {
+		if suggested := Suggest(tt.description); suggested != tt.expected {
+			t.Errorf("wrong type for %q, expected=%q, got=%q", tt.description, tt.expected, suggested)
+		}
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	source := "f <- function(x = \"#\", y = list(a = 1, b = c(2, 3))) { # a comment\n  x\n}\n" +
+		"  indented <- function(z) z\n" +
+		"`g h` = function(..., `k` = ')') NULL\n"
+
+	found := functions(source)
+
+	if 2 != len(found) {
+		t.Fatalf("wrong number of functions, got=%d", len(found))
+	}
+
+	if "f" != found[0].Name || 2 != len(found[0].Parameters) || "list(a = 1, b = c(2, 3))" != found[0].Defaults[1] || "\"#\"" != found[0].Defaults[0] {
+		t.Errorf("wrong function, got=%+v", found[0])
+	}
+
+	if "g h" != found[1].Name || "k" != found[1].Parameters[1] || "')'" != found[1].Defaults[1] {
+		t.Errorf("wrong function, got=%+v", found[1])
+	}
+}
@@ -0,0 +1,168 @@
+package rpackage
+
+import (
+	"regexp"
+	"strings"
+)
+
+// definition : a function bound at the top level of an R file, `name <- function(` starting the line
+var definition = regexp.MustCompile("(?m)^(`[^`]+`|\"[^\"]+\"|'[^']+'|[\\pL.][\\pL\\pN._]*)[ \\t]*(<-|=)[ \\t]*function[ \\t]*\\(")
+
+// stripComments : the source without its comments, the `#` in strings and backquoted names being kept
+func stripComments(source string) string {
+	var out strings.Builder
+	var quote rune
+	comment, escaped := false, false
+
+	for _, char := range source {
+		switch {
+		case comment:
+			if '\n' != char {
+				continue
+			}
+
+			comment = false
+		case 0 != quote:
+			if escaped {
+				escaped = false
+			} else if '\\' == char {
+				escaped = true
+			} else if char == quote {
+				quote = 0
+			}
+		case '"' == char || '\'' == char || '`' == char:
+			quote = char
+		case '#' == char:
+			comment = true
+
+			continue
+		}
+
+		out.WriteRune(char)
+	}
+
+	return out.String()
+}
+
+// enclosed : what is between the opening character at start and the one closing it, along with the rest of the
+// source; strings, and whatever opens and closes within them, are skipped
+func enclosed(source string, start int) (string, bool) {
+	depth := 0
+	var quote rune
+	escaped := false
+
+	for index, char := range source[start:] {
+		switch {
+		case 0 != quote:
+			if escaped {
+				escaped = false
+			} else if '\\' == char {
+				escaped = true
+			} else if char == quote {
+				quote = 0
+			}
+		case '"' == char || '\'' == char || '`' == char:
+			quote = char
+		case '(' == char || '[' == char || '{' == char:
+			depth++
+		case ')' == char || ']' == char || '}' == char:
+			depth--
+
+			if 0 == depth {
+				return source[start+1 : start+index], true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// split : the parts of the source separated by separator, leaving out those within brackets or strings
+func split(source string, separator rune) []string {
+	parts := []string{}
+	depth, last := 0, 0
+	var quote rune
+	escaped := false
+
+	for index, char := range source {
+		switch {
+		case 0 != quote:
+			if escaped {
+				escaped = false
+			} else if '\\' == char {
+				escaped = true
+			} else if char == quote {
+				quote = 0
+			}
+		case '"' == char || '\'' == char || '`' == char:
+			quote = char
+		case '(' == char || '[' == char || '{' == char:
+			depth++
+		case ')' == char || ']' == char || '}' == char:
+			depth--
+		case separator == char && 0 == depth:
+			parts = append(parts, source[last:index])
+			last = index + 1
+		}
+	}
+
+	return append(parts, source[last:])
+}
+
+// unquote : a name as R takes it, without the quotes or backquotes around it
+func unquote(name string) string {
+	if 2 <= len(name) && strings.ContainsAny(name[:1], "`\"'") && name[0] == name[len(name)-1] {
+		return name[1 : len(name)-1]
+	}
+
+	return name
+}
+
+// formals : the parameters in `x, na.rm = FALSE, ...`, with the R source of their default values, "" for those
+// without one
+func formals(source string) ([]string, []string) {
+	parameters, defaults := []string{}, []string{}
+
+	if "" == strings.TrimSpace(source) {
+		return parameters, defaults
+	}
+
+	for _, formal := range split(source, ',') {
+		name, value := formal, ""
+
+		for index, char := range formal {
+			if '=' == char {
+				name, value = formal[:index], formal[index+1:]
+
+				break
+			}
+		}
+
+		parameters = append(parameters, unquote(strings.TrimSpace(name)))
+		defaults = append(defaults, strings.Join(strings.Fields(value), " "))
+	}
+
+	return parameters, defaults
+}
+
+// functions : the functions defined at the top level of the R source, in the order they are found
+func functions(source string) []*Function {
+	source = stripComments(source)
+	found := []*Function{}
+
+	for _, match := range definition.FindAllStringSubmatchIndex(source, -1) {
+		arguments, ok := enclosed(source, match[1]-1)
+
+		if !ok {
+			continue
+		}
+
+		function := &Function{
+			Name: unquote(source[match[2]:match[3]]),
+		}
+		function.Parameters, function.Defaults = formals(arguments)
+		found = append(found, function)
+	}
+
+	return found
+}
@@ -3,10 +3,15 @@ package token
 // TokenType : this will work as a PoC only, needs to change it to an int or a byte later on
 type TokenType string
 
-// Token : stores the information token related; later on add a line and column to it to make an easier debug later on
+// Token : stores the information token related, along with where it starts -- lines and columns start at 1
 type Token struct {
 	Type    TokenType
 	Literal string
+	Line    int
+	Column  int
+	// Newline tells, for R source only, that the token starts a line outside parentheses and brackets, ending the
+	// expression before it
+	Newline bool
 }
 
 const (
@@ -20,16 +25,21 @@ const (
 	IDENTIFIER = "IDENTIFIER"
 
 	EXPORT   = "EXPORT"
+	IMPORT   = "IMPORT"
+	DECLARE  = "DECLARE"
 	LET      = "LET"
 	CONST    = "CONST"
 	FUNCTION = "FUNCTION"
 	TRUE     = "TRUE"
 	FALSE    = "FALSE"
+	NA       = "NA"
 	IF       = "IF"
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
+	MATCH    = "MATCH"
 
 	POINT              = "."
+	DOLLAR             = "$"
 	BANG               = "!"
 	EQUAL              = "="
 	PLUS               = "+"
@@ -40,8 +50,14 @@ const (
 	GREATER_THAN       = ">"
 	LESS_THAN_EQUAL    = "<="
 	GREATER_THAN_EQUAl = ">="
+	MATRIX_PRODUCT     = "%*%"
+	SPECIAL            = "SPECIAL"
+	PIPE               = "|>"
+	BAR                = "|"
+	ELLIPSIS           = "..."
 
 	COMMA             = ","
+	COLON             = ":"
 	SEMICOLON         = ";"
 	LEFT_PARENTHESIS  = "("
 	RIGHT_PARENTHESIS = ")"
@@ -51,6 +67,7 @@ const (
 	RIGHT_BRACKET     = "]"
 
 	ASSIGN       = "<-"
+	RIGHT_ASSIGN = "->"
 	DOUBLE_EQUAL = "=="
 	DIFFERENT    = "!="
 )
@@ -58,10 +75,15 @@ const (
 var keywords = map[string]TokenType{
 	"if":       IF,
 	"let":      LET,
+	"export":   EXPORT,
+	"import":   IMPORT,
+	"declare":  DECLARE,
 	"TRUE":     TRUE,
 	"else":     ELSE,
 	"FALSE":    FALSE,
+	"NA":       NA,
 	"return":   RETURN,
+	"match":    MATCH,
 	"<-":       ASSIGN,
 	"function": FUNCTION,
 	"<":        LESS_THAN,
@@ -0,0 +1,60 @@
+package virtualmachine
+
+import (
+	"../object"
+)
+
+// callContext : what the builtins called by the virtual machine receive
+type callContext struct {
+	vm       *VirtualMachine
+	position object.Position
+}
+
+// Call :
+func (c *callContext) Call(function object.Object, parameters ...object.Object) (object.Object, *object.Error) {
+	return c.vm.callFunction(function, nil, parameters)
+}
+
+// CallNamed :
+func (c *callContext) CallNamed(function object.Object, names []string, parameters []object.Object) (object.Object, *object.Error) {
+	return c.vm.callFunction(function, names, parameters)
+}
+
+// Position :
+func (c *callContext) Position() object.Position {
+	return c.position
+}
+
+// Function : the name is looked up just as the compiler would, among the locals and the free variables of the
+// current frame, then among the globals and the builtins
+func (c *callContext) Function(name string) object.Object {
+	frame := c.vm.currentFrame()
+
+	if index, ok := frame.cl.Fn.Locals[name]; ok {
+		return c.vm.stack[frame.basePointer+index]
+	}
+
+	if index, ok := frame.cl.Fn.FreeVariables[name]; ok {
+		return frame.cl.FreeVariables[index]
+	}
+
+	if index, ok := c.vm.names[name]; ok {
+		return c.vm.globals[index]
+	}
+
+	if builtin := object.GetBuiltinByName(name); nil != builtin {
+		return builtin
+	}
+
+	return nil
+}
+
+// Random :
+func (c *callContext) Random() *object.Random {
+	return c.vm.random
+}
+
+// Signal :
+func (c *callContext) Signal(condition *object.Condition) *object.Error {
+	return c.vm.signal(condition)
+}
@@ -5,11 +5,12 @@ import (
 	"../object"
 )
 
-// Frame :
+// Frame : call is where the last call made from the frame is, so builtins can tell where they were called from
 type Frame struct {
 	cl          *object.Closure
 	ip          int
 	basePointer int
+	call        int
 }
 
 // Instructions :
@@ -0,0 +1,165 @@
+package virtualmachine
+
+import (
+	"fmt"
+
+	"../object"
+)
+
+// handler : the handlers established by a tryCatch or a withCallingHandlers, along with where the evaluation
+// unwinds to -- catch and finally being addresses in the instructions of the frame they were established in, 0
+// when there is no finally -- and the frames, stack pointer and run they were established at
+type handler struct {
+	*object.Handler
+	catch   int
+	finally int
+	frames  int
+	sp      int
+	runs    int
+}
+
+// signalled : the error making a run end, so the condition goes on unwinding from the run below it, or ends the
+// program when no handler takes it
+type signalled struct {
+	condition *object.Condition
+}
+
+// Error : the position is shown when the error was raised by a builtin failing
+func (s *signalled) Error() string {
+	if 0 == s.condition.Position.Line {
+		return s.condition.Message
+	}
+
+	return fmt.Sprintf("%s, at %s", s.condition.Message, s.condition.Position)
+}
+
+// handlerClasses : the classes of the handlers OpPushHandler and OpPushCallingHandler find on the stack, in order
+var handlerClasses = []string{"error", "warning", "message", "condition"}
+
+// pushHandler : establishes the handlers on top of the stack, NULL standing for those not given
+func (vm *VirtualMachine) pushHandler(calling bool, catch int, finally int) {
+	functions := map[string]object.Object{}
+
+	for index := len(handlerClasses) - 1; index >= 0; index-- {
+		if function := vm.pop(); NULL != function {
+			functions[handlerClasses[index]] = function
+		}
+	}
+
+	vm.handlers = append(vm.handlers, &handler{
+		Handler: &object.Handler{
+			Functions: functions,
+			Calling:   calling,
+		},
+		catch:   catch,
+		finally: finally,
+		frames:  vm.framesIndex,
+		sp:      vm.sp,
+		runs:    vm.runs,
+	})
+}
+
+// dropHandlers : leaves out the handlers established in frames which are gone
+func (vm *VirtualMachine) dropHandlers() {
+	for 0 < len(vm.handlers) && vm.handlers[len(vm.handlers)-1].frames > vm.framesIndex {
+		vm.handlers = vm.handlers[:len(vm.handlers)-1]
+	}
+}
+
+// catching : the innermost tryCatch taking the condition, -1 when there is none
+func (vm *VirtualMachine) catching(condition *object.Condition) int {
+	for index := len(vm.handlers) - 1; index >= 0; index-- {
+		if record := vm.handlers[index]; !record.Calling && nil != record.For(condition) {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// signal : hands the condition to the handlers, the innermost first; calling handlers are called right away, with
+// the handlers from their own on left out, until a tryCatch takes the condition, which is then given back in an
+// error for raise to unwind to it -- warnings and messages nobody takes are reported and give nil
+func (vm *VirtualMachine) signal(condition *object.Condition) *object.Error {
+	for index := len(vm.handlers) - 1; index >= 0; index-- {
+		record := vm.handlers[index]
+		function := record.For(condition)
+
+		if nil == function {
+			continue
+		}
+
+		if !record.Calling {
+			break
+		}
+
+		disabled := append([]*handler{}, vm.handlers[index:]...)
+		vm.handlers = vm.handlers[:index]
+
+		_, err := vm.callFunction(function, nil, []object.Object{condition})
+
+		vm.handlers = append(vm.handlers, disabled...)
+
+		if nil != err {
+			return err
+		}
+	}
+
+	if "error" != condition.Class && -1 == vm.catching(condition) {
+		condition.Report()
+
+		return nil
+	}
+
+	return &object.Error{
+		Message:   condition.Message,
+		Condition: condition,
+	}
+}
+
+// raise : unwinds to the tryCatch taking the condition, going through the finally of those in between; the
+// condition is pushed for the finally, which signals it again, while the handler of the tryCatch is called with it
+// -- when the tryCatch was established in a run below, or there is none, the run ends with the condition
+func (vm *VirtualMachine) raise(condition *object.Condition) error {
+	target := vm.catching(condition)
+
+	for 0 < len(vm.handlers) {
+		top := len(vm.handlers) - 1
+		record := vm.handlers[top]
+
+		if record.runs < vm.runs {
+			break
+		}
+
+		vm.handlers = vm.handlers[:top]
+
+		if top != target && 0 == record.finally {
+			continue
+		}
+
+		vm.framesIndex = record.frames
+		vm.sp = record.sp
+
+		if top != target {
+			vm.currentFrame().ip = record.finally - 1
+
+			return vm.push(condition)
+		}
+
+		vm.currentFrame().ip = record.catch - 1
+
+		if err := vm.push(record.For(condition)); nil != err {
+			return err
+		}
+
+		if err := vm.push(condition); nil != err {
+			return err
+		}
+
+		return vm.exectueCall(1, nil)
+	}
+
+	return &signalled{
+		condition: condition,
+	}
+}
@@ -38,9 +38,19 @@ type VirtualMachine struct {
 	sp    int
 
 	globals []object.Object
+	// the slots of the globals by their names
+	names map[string]int
 
 	frames      []*Frame
 	framesIndex int
+
+	// each machine draws its own random numbers, so concurrent ones do not interfere
+	random *object.Random
+
+	// the handlers established by tryCatch and withCallingHandlers, the innermost last, and how many runs are
+	// nested, as builtins calling back functions run the machine again
+	handlers []*handler
+	runs     int
 }
 
 // nativeBoolToBooleanObject :
@@ -116,13 +126,52 @@ func (vm *VirtualMachine) pop() object.Object {
 	return obj
 }
 
-// executeIntegerBinaryOperation :
-func (vm *VirtualMachine) executeIntegerBinaryOperation(op code.Opcode, left, right object.Object) error {
+// integerBinaryOperation :
+func integerBinaryOperation(op code.Opcode, left, right object.Object) (object.Object, error) {
 	var result int64
 
 	leftValue := left.(*object.Integer).Value
 	rightValue := right.(*object.Integer).Value
 
+	switch op {
+	case code.OpAdd:
+		result = leftValue + rightValue
+	case code.OpSubtract:
+		result = leftValue - rightValue
+	case code.OpMultiply:
+		result = leftValue * rightValue
+	case code.OpDivide:
+		return object.DivideIntegers(leftValue, rightValue), nil
+	default:
+		return nil, fmt.Errorf("unknown integer operator: %d", op)
+	}
+
+	return &object.Integer{
+		Value: result,
+	}, nil
+}
+
+// toDouble :
+func toDouble(obj object.Object) float64 {
+	if integer, ok := obj.(*object.Integer); ok {
+		return float64(integer.Value)
+	}
+
+	return obj.(*object.Double).Value
+}
+
+// isNumeric :
+func isNumeric(obj object.Object) bool {
+	return object.INTEGER_OBJECT == obj.Type() || object.DOUBLE_OBJECT == obj.Type()
+}
+
+// doubleBinaryOperation : whenever a double is involved, the integer is promoted to double
+func doubleBinaryOperation(op code.Opcode, left, right object.Object) (object.Object, error) {
+	var result float64
+
+	leftValue := toDouble(left)
+	rightValue := toDouble(right)
+
 	switch op {
 	case code.OpAdd:
 		result = leftValue + rightValue
@@ -133,111 +182,277 @@ func (vm *VirtualMachine) executeIntegerBinaryOperation(op code.Opcode, left, ri
 	case code.OpDivide:
 		result = leftValue / rightValue
 	default:
-		return fmt.Errorf("unknown integer operator: %d", op)
+		return nil, fmt.Errorf("unknown double operator: %d", op)
 	}
 
-	return vm.push(&object.Integer{
+	return &object.Double{
 		Value: result,
-	})
+	}, nil
 }
 
-// executeStringBinaryOperation :
-func (vm *VirtualMachine) executeStringBinaryOperation(op code.Opcode, left, right object.Object) error {
+// stringBinaryOperation :
+func stringBinaryOperation(op code.Opcode, left, right object.Object) (object.Object, error) {
 	if code.OpAdd != op {
-		return fmt.Errorf("unknown string operator: %d", op)
+		return nil, fmt.Errorf("unknown string operator: %d", op)
 	}
 
 	leftValue := left.(*object.String).Value
 	rightValue := right.(*object.String).Value
 
-	return vm.push(&object.String{
+	return &object.String{
 		Value: leftValue + rightValue,
-	})
+	}, nil
 }
 
-// executeBinaryOperation :
-func (vm *VirtualMachine) executeBinaryOperation(op code.Opcode) error {
-	right := vm.pop()
-	left := vm.pop()
+// vectorElement : scalars are recycled against every element of the other side
+func vectorElement(obj object.Object, index int) object.Object {
+	if array, ok := obj.(*object.Array); ok {
+		return array.Elements[index]
+	}
+
+	return obj
+}
+
+// vectorize : operations over arrays are applied element-wise, just like in R
+func vectorize(op code.Opcode, left, right object.Object, operation func(code.Opcode, object.Object, object.Object) (object.Object, error)) (object.Object, error) {
+	length := -1
+
+	for _, side := range []object.Object{left, right} {
+		array, ok := side.(*object.Array)
+
+		if !ok {
+			continue
+		}
+
+		if -1 != length && len(array.Elements) != length {
+			return nil, fmt.Errorf("vector lengths differ: %d and %d", length, len(array.Elements))
+		}
 
+		length = len(array.Elements)
+	}
+
+	elements := make([]object.Object, length)
+
+	for index := range elements {
+		element, err := operation(op, vectorElement(left, index), vectorElement(right, index))
+
+		if nil != err {
+			return nil, err
+		}
+
+		elements[index] = element
+	}
+
+	return &object.Array{
+		Elements: elements,
+	}, nil
+}
+
+// operators : matrices share their arithmetic with the evaluator, which knows the operations by their symbol
+var operators = map[code.Opcode]string{
+	code.OpAdd:            "+",
+	code.OpSubtract:       "-",
+	code.OpMultiply:       "*",
+	code.OpDivide:         "/",
+	code.OpMatrixMultiply: "%*%",
+}
+
+// matrixOperation :
+func matrixOperation(op code.Opcode, left, right object.Object) (object.Object, error) {
+	result, err := object.MatrixOperation(operators[op], left, right)
+
+	if nil != err {
+		return nil, fmt.Errorf("%s", err.Message)
+	}
+
+	return result, nil
+}
+
+// binaryOperation :
+func binaryOperation(op code.Opcode, left, right object.Object) (object.Object, error) {
 	leftType := left.Type()
 	rightType := right.Type()
 
 	switch {
+	case code.OpMatrixMultiply == op || object.MATRIX_OBJECT == leftType || object.MATRIX_OBJECT == rightType:
+		return matrixOperation(op, left, right)
 	case object.INTEGER_OBJECT == leftType && object.INTEGER_OBJECT == rightType:
-		return vm.executeIntegerBinaryOperation(op, left, right)
+		return integerBinaryOperation(op, left, right)
+	case isNumeric(left) && isNumeric(right):
+		return doubleBinaryOperation(op, left, right)
+	case object.ARRAY_OBJECT == leftType || object.ARRAY_OBJECT == rightType:
+		return vectorize(op, left, right, binaryOperation)
+	case object.NA == left || object.NA == right:
+		return object.NA, nil
 	case object.STRING_OBJECT == leftType && object.STRING_OBJECT == rightType:
-		return vm.executeStringBinaryOperation(op, left, right)
+		return stringBinaryOperation(op, left, right)
 	default:
-		return fmt.Errorf("unsupported types for binary operation: %s %s", leftType, rightType)
+		return nil, fmt.Errorf("unsupported types for binary operation: %s %s", leftType, rightType)
 	}
 }
 
-// executeIntegerComparisson :
-func (vm *VirtualMachine) executeIntegerComparisson(op code.Opcode, left, right object.Object) error {
-	leftValue := left.(*object.Integer).Value
-	rightValue := right.(*object.Integer).Value
+// executeBinaryOperation :
+func (vm *VirtualMachine) executeBinaryOperation(op code.Opcode) error {
+	right := vm.pop()
+	left := vm.pop()
+
+	result, err := binaryOperation(op, left, right)
+
+	if nil != err {
+		return err
+	}
+
+	return vm.push(result)
+}
+
+// numericComparisson :
+func numericComparisson(op code.Opcode, left, right object.Object) (object.Object, error) {
+	leftValue := toDouble(left)
+	rightValue := toDouble(right)
+
+	if object.INTEGER_OBJECT == left.Type() && object.INTEGER_OBJECT == right.Type() {
+		integerLeft := left.(*object.Integer).Value
+		integerRight := right.(*object.Integer).Value
+
+		switch op {
+		case code.OpEqual:
+			return nativeBoolToBooleanObject(integerRight == integerLeft), nil
+		case code.OpNotEqual:
+			return nativeBoolToBooleanObject(integerRight != integerLeft), nil
+		case code.OpGreaterThan:
+			return nativeBoolToBooleanObject(integerLeft > integerRight), nil
+		}
+	}
 
 	switch op {
 	case code.OpEqual:
-		return vm.push(nativeBoolToBooleanObject(rightValue == leftValue))
+		return nativeBoolToBooleanObject(rightValue == leftValue), nil
 	case code.OpNotEqual:
-		return vm.push(nativeBoolToBooleanObject(rightValue != leftValue))
+		return nativeBoolToBooleanObject(rightValue != leftValue), nil
 	case code.OpGreaterThan:
-		return vm.push(nativeBoolToBooleanObject(leftValue > rightValue))
+		return nativeBoolToBooleanObject(leftValue > rightValue), nil
 	default:
-		return fmt.Errorf("unknown operator: %d", op)
+		return nil, fmt.Errorf("unknown operator: %d", op)
 	}
 }
 
-// executeComparison :
-func (vm *VirtualMachine) executeComparison(op code.Opcode) error {
-	right := vm.pop()
-	left := vm.pop()
-
-	if object.INTEGER_OBJECT == left.Type() && object.INTEGER_OBJECT == right.Type() {
-		return vm.executeIntegerComparisson(op, left, right)
+// comparison :
+func comparison(op code.Opcode, left, right object.Object) (object.Object, error) {
+	switch {
+	case isNumeric(left) && isNumeric(right):
+		return numericComparisson(op, left, right)
+	case object.ARRAY_OBJECT == left.Type() || object.ARRAY_OBJECT == right.Type():
+		return vectorize(op, left, right, comparison)
+	case object.NA == left || object.NA == right:
+		return object.NA, nil
+	case object.STRING_OBJECT == left.Type() && object.STRING_OBJECT == right.Type() && code.OpGreaterThan != op:
+		equal := left.(*object.String).Value == right.(*object.String).Value
+
+		return nativeBoolToBooleanObject(equal == (code.OpEqual == op)), nil
 	}
 
 	switch op {
 	case code.OpEqual:
-		return vm.push(nativeBoolToBooleanObject(right == left))
+		return nativeBoolToBooleanObject(right == left), nil
 	case code.OpNotEqual:
-		return vm.push(nativeBoolToBooleanObject(right != left))
+		return nativeBoolToBooleanObject(right != left), nil
 	default:
-		return fmt.Errorf("unknown operator: %d %s %s", op, left.Type(), right.Type())
+		return nil, fmt.Errorf("unknown operator: %d %s %s", op, left.Type(), right.Type())
 	}
 }
 
-// executeBangOperator :
-func (vm *VirtualMachine) executeBangOperator() error {
-	operand := vm.pop()
+// executeComparison :
+func (vm *VirtualMachine) executeComparison(op code.Opcode) error {
+	right := vm.pop()
+	left := vm.pop()
+
+	result, err := comparison(op, left, right)
+
+	if nil != err {
+		return err
+	}
+
+	return vm.push(result)
+}
+
+// bangOperator :
+func bangOperator(operand object.Object) object.Object {
+	if array, ok := operand.(*object.Array); ok {
+		elements := make([]object.Object, len(array.Elements))
+
+		for index, element := range array.Elements {
+			elements[index] = bangOperator(element)
+		}
+
+		return &object.Array{
+			Elements: elements,
+		}
+	}
 
 	switch operand {
+	case object.NA:
+		return object.NA
 	case TRUE:
-		return vm.push(FALSE)
+		return FALSE
 	case FALSE:
-		return vm.push(TRUE)
+		return TRUE
 	case NULL:
-		return vm.push(TRUE)
+		return TRUE
+	default:
+		return FALSE
+	}
+}
+
+// executeBangOperator :
+func (vm *VirtualMachine) executeBangOperator() error {
+	return vm.push(bangOperator(vm.pop()))
+}
+
+// minusOperator :
+func minusOperator(operand object.Object) (object.Object, error) {
+	switch operand := operand.(type) {
+	case *object.Integer:
+		return &object.Integer{
+			Value: -operand.Value,
+		}, nil
+	case *object.Double:
+		return &object.Double{
+			Value: -operand.Value,
+		}, nil
+	case *object.Array:
+		elements := make([]object.Object, len(operand.Elements))
+
+		for index, element := range operand.Elements {
+			negated, err := minusOperator(element)
+
+			if nil != err {
+				return nil, err
+			}
+
+			elements[index] = negated
+		}
+
+		return &object.Array{
+			Elements: elements,
+		}, nil
+	case *object.Matrix:
+		return operand.Negate(), nil
+	case *object.NotAvailable:
+		return object.NA, nil
 	default:
-		return vm.push(FALSE)
+		return nil, fmt.Errorf("unsupported type for negation: %s", operand.Type())
 	}
 }
 
 // executeMinusOperator :
 func (vm *VirtualMachine) executeMinusOperator() error {
-	operand := vm.pop()
+	result, err := minusOperator(vm.pop())
 
-	if object.INTEGER_OBJECT != operand.Type() {
-		return fmt.Errorf("unsupported type for negation: %s", operand.Type())
+	if nil != err {
+		return err
 	}
 
-	value := operand.(*object.Integer).Value
-
-	return vm.push(&object.Integer{
-		Value: -value,
-	})
+	return vm.push(result)
 }
 
 // buildArray :
@@ -266,36 +481,154 @@ func (vm *VirtualMachine) executeArrayIndex(array, index object.Object) error {
 	return vm.push(arrayObject.Elements[position])
 }
 
+// executeHashIndex :
+func (vm *VirtualMachine) executeHashIndex(hash, index object.Object) error {
+	key, ok := index.(object.Hashable)
+
+	if !ok {
+		return fmt.Errorf("unusable as hash key: %s", index.Type())
+	}
+
+	value, ok := hash.(*object.Hash).Get(key)
+
+	if !ok {
+		return vm.push(NULL)
+	}
+
+	return vm.push(value)
+}
+
 // executeIndexExpression :
 func (vm *VirtualMachine) executeIndexExpression(left, index object.Object) error {
 	switch {
 	case left.Type() == object.ARRAY_OBJECT && index.Type() == object.INTEGER_OBJECT:
 		return vm.executeArrayIndex(left, index)
+	case left.Type() == object.HASH_OBJECT:
+		return vm.executeHashIndex(left, index)
+	case left.Type() == object.DATA_FRAME_OBJECT:
+		return vm.pushIndexResult(left.(*object.DataFrame).Index(index))
+	case left.Type() == object.MATRIX_OBJECT:
+		return vm.pushIndexResult(left.(*object.Matrix).Index(index))
+	case left.Type() == object.LIST_OBJECT:
+		return vm.pushIndexResult(left.(*object.List).Index(index))
 	default:
 		return fmt.Errorf("index operator not supported: %s", left.Type())
 	}
 }
 
-// callClosure :
-func (vm *VirtualMachine) callClosure(cl *object.Closure, numberOfParameters int) error {
-	if numberOfParameters != cl.Fn.NumberOfParameters {
-		return fmt.Errorf("wrong number of parameters: want=%d, got=%d", cl.Fn.NumberOfParameters, numberOfParameters)
+// pushIndexResult : errors become VM errors and missing values become NULL
+func (vm *VirtualMachine) pushIndexResult(result object.Object) error {
+	if err, ok := result.(*object.Error); ok {
+		return fmt.Errorf("%s", err.Message)
 	}
 
-	frame := InitializeFrame(cl, vm.sp-numberOfParameters)
-	vm.pushFrame(frame)
+	if nil == result {
+		return vm.push(NULL)
+	}
+
+	return vm.push(result)
+}
 
-	vm.sp = frame.basePointer + cl.Fn.NumberOfLocals
+// executeMatrixIndexExpression :
+func (vm *VirtualMachine) executeMatrixIndexExpression(left, rows, columns object.Object) error {
+	switch left := left.(type) {
+	case *object.DataFrame:
+		return vm.pushIndexResult(left.Slice(rows, columns))
+	case *object.Matrix:
+		return vm.pushIndexResult(left.Slice(rows, columns))
+	default:
+		return fmt.Errorf("matrix index operator not supported: %s", left.Type())
+	}
+}
+
+// callClosure : the arguments are bound the way R does, by name and then by position, the parameters left out
+// being nil so their default values are worked out on entry, and those left over packed into `...`
+func (vm *VirtualMachine) callClosure(cl *object.Closure, numberOfParameters int, names []string) error {
+	base := vm.sp - numberOfParameters
+
+	if nil == names && !cl.Fn.Variadic && numberOfParameters == cl.Fn.NumberOfParameters {
+		vm.pushFrame(InitializeFrame(cl, base))
+		vm.sp = base + cl.Fn.NumberOfLocals
+
+		return nil
+	}
+
+	arguments := make([]object.Object, numberOfParameters)
+	copy(arguments, vm.stack[base:vm.sp])
+
+	bound, dots, err := object.MatchArguments(cl.Fn.Parameters, names, arguments)
+
+	if nil != err {
+		return fmt.Errorf("%s", err.Message)
+	}
+
+	if base+cl.Fn.NumberOfLocals >= StackSize {
+		return fmt.Errorf("stack overflow")
+	}
+
+	for parameter, index := range bound {
+		if "..." == cl.Fn.Parameters[parameter] {
+			vm.stack[base+parameter] = object.PackDots(dots, names, arguments)
+
+			continue
+		}
+
+		if -1 != index {
+			vm.stack[base+parameter] = arguments[index]
+
+			continue
+		}
+
+		if !cl.Fn.Defaults[parameter] {
+			return fmt.Errorf("%s", object.MissingArgument(cl.Fn.Parameters[parameter]).Message)
+		}
+
+		vm.stack[base+parameter] = nil
+	}
+
+	vm.pushFrame(InitializeFrame(cl, base))
+	vm.sp = base + cl.Fn.NumberOfLocals
 
 	return nil
 }
 
 // callBuiltin :
-func (vm *VirtualMachine) callBuiltin(builtin *object.Builtin, numberOfParameters int) error {
-	parameters := vm.stack[vm.sp-numberOfParameters : vm.sp]
-	result := builtin.Fn(parameters...)
+func (vm *VirtualMachine) callBuiltin(builtin *object.Builtin, numberOfParameters int, names []string) error {
+	frame := vm.currentFrame()
+
+	context := &callContext{
+		vm:       vm,
+		position: frame.cl.Fn.Positions[frame.call],
+	}
+
+	var result object.Object
+	parameters, err := builtin.Arguments(names, vm.stack[vm.sp-numberOfParameters:vm.sp])
+
+	if nil != err {
+		result = err
+	} else {
+		result = builtin.Fn(context, parameters...)
+	}
+
+	if err, ok := result.(*object.Error); ok && 0 == err.Position.Line {
+		err.Position = context.position
+	}
+
 	vm.sp = vm.sp - numberOfParameters - 1
 
+	// errors are signalled, making the evaluation unwind to the handler taking them, or ending the run
+	if err, ok := result.(*object.Error); ok && nil == err.Condition {
+		result = vm.signal(object.ConditionOf(err))
+	}
+
+	if err, ok := result.(*object.Error); ok {
+		if nil == err.Condition {
+			return fmt.Errorf("%s", err.Message)
+		}
+
+		return vm.raise(err.Condition)
+	}
+
 	if nil != result {
 		vm.push(result)
 	} else {
@@ -305,17 +638,101 @@ func (vm *VirtualMachine) callBuiltin(builtin *object.Builtin, numberOfParameter
 	return nil
 }
 
-// exectueCall :
-func (vm *VirtualMachine) exectueCall(numberOfParameters int) error {
+// callFunction : lets builtins call back functions, running the VM until the call returns
+func (vm *VirtualMachine) callFunction(function object.Object, names []string, parameters []object.Object) (object.Object, *object.Error) {
+	depth := vm.framesIndex
+	base := vm.sp
+
+	fail := func(err error) (object.Object, *object.Error) {
+		vm.framesIndex = depth
+		vm.sp = base
+		vm.dropHandlers()
+
+		failure := &object.Error{
+			Message: err.Error(),
+		}
+
+		if signalled, ok := err.(*signalled); ok {
+			failure.Condition = signalled.condition
+		}
+
+		return nil, failure
+	}
+
+	if err := vm.push(function); nil != err {
+		return fail(err)
+	}
+
+	for _, parameter := range parameters {
+		if err := vm.push(parameter); nil != err {
+			return fail(err)
+		}
+	}
+
+	if err := vm.exectueCall(len(parameters), names); nil != err {
+		return fail(err)
+	}
+
+	if err := vm.run(depth); nil != err {
+		return fail(err)
+	}
+
+	result := vm.pop()
+
+	if err, ok := result.(*object.Error); ok {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// spreadArguments : the names given to the arguments on top of the stack, those of `...` being replaced by the
+// arguments it holds
+func (vm *VirtualMachine) spreadArguments(numberOfParameters int, given *object.Array) (int, []string, error) {
+	base := vm.sp - numberOfParameters
+	names := []string{}
+	arguments := []object.Object{}
+	spread := false
+
+	for index, name := range given.Elements {
+		name := name.(*object.String).Value
+
+		if "..." == name {
+			names, arguments = vm.stack[base+index].(*object.List).Spread(names, arguments)
+			spread = true
+
+			continue
+		}
+
+		names = append(names, name)
+		arguments = append(arguments, vm.stack[base+index])
+	}
+
+	if !spread {
+		return numberOfParameters, names, nil
+	}
+
+	if base+len(arguments) >= StackSize {
+		return 0, nil, fmt.Errorf("stack overflow")
+	}
+
+	copy(vm.stack[base:], arguments)
+	vm.sp = base + len(arguments)
+
+	return len(arguments), names, nil
+}
+
+// exectueCall : names are those given to the arguments, nil when they are all given by position
+func (vm *VirtualMachine) exectueCall(numberOfParameters int, names []string) error {
 	callee := vm.stack[vm.sp-1-numberOfParameters]
 
 	// fmt.Println(callee)
 
 	switch calleeType := callee.(type) {
 	case *object.Closure:
-		return vm.callClosure(calleeType, numberOfParameters)
+		return vm.callClosure(calleeType, numberOfParameters, names)
 	case *object.Builtin:
-		return vm.callBuiltin(calleeType, numberOfParameters)
+		return vm.callBuiltin(calleeType, numberOfParameters, names)
 	default:
 		return fmt.Errorf("calling a non-function and non-built-in")
 	}
@@ -348,11 +765,58 @@ func (vm *VirtualMachine) pushClosure(constIndex int, numberOfFreeVariables int)
 
 // Run :
 func (vm *VirtualMachine) Run() error {
+	return vm.run(0)
+}
+
+// run : executes until the frames go back to the given depth, so calls made by builtins return to them; when
+// handlers are established, errors are signalled as error conditions, the run going on from the tryCatch taking them
+func (vm *VirtualMachine) run(depth int) error {
+	vm.runs++
+	defer func() {
+		vm.runs--
+	}()
+
+	for {
+		err := vm.guarded(depth)
+
+		if _, ok := err.(*signalled); ok || nil == err || 0 == len(vm.handlers) {
+			return err
+		}
+
+		condition := &object.Condition{
+			Class:   "error",
+			Message: err.Error(),
+		}
+
+		if failure := vm.signal(condition); nil != failure && nil != failure.Condition {
+			condition = failure.Condition
+		}
+
+		if err := vm.raise(condition); nil != err {
+			return err
+		}
+	}
+}
+
+// guarded : executes the instructions, a fault of the machine itself, such as an index out of range, being an error
+// rather than the end of the program, so tryCatch can take it just as any other
+func (vm *VirtualMachine) guarded(depth int) (err error) {
+	defer func() {
+		if fault := recover(); nil != fault {
+			err = fmt.Errorf("%v", fault)
+		}
+	}()
+
+	return vm.execute(depth)
+}
+
+// execute :
+func (vm *VirtualMachine) execute(depth int) error {
 	var ip int
 	var instructions code.Instructions
 	var op code.Opcode
 
-	for vm.currentFrame().ip < len(vm.currentFrame().Instructions())-1 {
+	for vm.framesIndex > depth && vm.currentFrame().ip < len(vm.currentFrame().Instructions())-1 {
 		vm.currentFrame().ip++
 
 		ip = vm.currentFrame().ip
@@ -370,7 +834,7 @@ func (vm *VirtualMachine) Run() error {
 				return err
 			}
 
-		case code.OpAdd, code.OpSubtract, code.OpMultiply, code.OpDivide:
+		case code.OpAdd, code.OpSubtract, code.OpMultiply, code.OpDivide, code.OpMatrixMultiply:
 			err := vm.executeBinaryOperation(op)
 
 			if nil != err {
@@ -475,22 +939,79 @@ func (vm *VirtualMachine) Run() error {
 				return err
 			}
 
+		case code.OpMatrixIndex:
+			columns := vm.pop()
+			rows := vm.pop()
+			left := vm.pop()
+
+			err := vm.executeMatrixIndexExpression(left, rows, columns)
+
+			if nil != err {
+				return err
+			}
+
 		case code.OpCall:
 			numberOfParameters := code.ReadUint8(instructions[ip+1:])
 
+			vm.currentFrame().call = ip
 			vm.currentFrame().ip++
 
-			err := vm.exectueCall(int(numberOfParameters))
+			err := vm.exectueCall(int(numberOfParameters), nil)
+
+			if nil != err {
+				return err
+			}
+
+		case code.OpCallNamed:
+			numberOfParameters := code.ReadUint8(instructions[ip+1:])
+			namesIndex := code.ReadUint16(instructions[ip+2:])
+
+			vm.currentFrame().call = ip
+			vm.currentFrame().ip += 3
+
+			count, names, err := vm.spreadArguments(int(numberOfParameters), vm.constants[namesIndex].(*object.Array))
 
 			if nil != err {
 				return err
 			}
 
+			err = vm.exectueCall(count, names)
+
+			if nil != err {
+				return err
+			}
+
+		case code.OpGetDot:
+			position := int(code.ReadUint16(instructions[ip+1:]))
+			vm.currentFrame().ip += 2
+
+			dot, failure := vm.pop().(*object.List).Dot(position)
+
+			if nil != failure {
+				return fmt.Errorf("%s", failure.Message)
+			}
+
+			err := vm.push(dot)
+
+			if nil != err {
+				return err
+			}
+
+		case code.OpJumpBound:
+			localIndex := code.ReadUint8(instructions[ip+1:])
+			position := int(code.ReadUint16(instructions[ip+2:]))
+			vm.currentFrame().ip += 3
+
+			if nil != vm.stack[vm.currentFrame().basePointer+int(localIndex)] {
+				vm.currentFrame().ip = position - 1
+			}
+
 		case code.OpReturnValue:
 			returnValue := vm.pop()
 
 			frame := vm.popFrame()
 			vm.sp = frame.basePointer - 1
+			vm.dropHandlers()
 
 			err := vm.push(returnValue)
 
@@ -501,6 +1022,7 @@ func (vm *VirtualMachine) Run() error {
 		case code.OpReturn:
 			frame := vm.popFrame()
 			vm.sp = frame.basePointer - 1
+			vm.dropHandlers()
 
 			err := vm.push(NULL)
 
@@ -521,8 +1043,14 @@ func (vm *VirtualMachine) Run() error {
 			vm.currentFrame().ip++
 
 			frame := vm.currentFrame()
+			value := vm.stack[frame.basePointer+int(localIndex)]
+
+			// a parameter is only left unbound while the default values are worked out
+			if nil == value {
+				return fmt.Errorf("%s", object.RecursiveDefault().Message)
+			}
 
-			err := vm.push(vm.stack[frame.basePointer+int(localIndex)])
+			err := vm.push(value)
 
 			if nil != err {
 				return err
@@ -573,6 +1101,59 @@ func (vm *VirtualMachine) Run() error {
 				return err
 			}
 
+		case code.OpPushHandler:
+			catch := int(code.ReadUint16(instructions[ip+1:]))
+			finally := int(code.ReadUint16(instructions[ip+3:]))
+			vm.currentFrame().ip += 4
+
+			vm.pushHandler(false, catch, finally)
+
+		case code.OpPushCallingHandler:
+			vm.pushHandler(true, 0, 0)
+
+		case code.OpPopHandler:
+			vm.handlers = vm.handlers[:len(vm.handlers)-1]
+
+		case code.OpSignal:
+			err := vm.raise(vm.pop().(*object.Condition))
+
+			if nil != err {
+				return err
+			}
+
+		case code.OpTestTag:
+			constIndex := code.ReadUint16(instructions[ip+1:])
+			vm.currentFrame().ip += 2
+
+			tag := vm.constants[constIndex].(*object.String).Value
+			_, matched, _ := object.Destructure(vm.stack[vm.sp-1], tag, -1)
+
+			err := vm.push(nativeBoolToBooleanObject(matched))
+
+			if nil != err {
+				return err
+			}
+
+		case code.OpDestructure:
+			count := int(code.ReadUint8(instructions[ip+1:]))
+			vm.currentFrame().ip++
+
+			variant := vm.pop().(*object.Variant)
+			values, _, failure := object.Destructure(variant, variant.Tag, count)
+
+			if nil != failure {
+				return fmt.Errorf("%s", failure.Message)
+			}
+
+			for _, value := range values {
+				if err := vm.push(value); nil != err {
+					return err
+				}
+			}
+
+		case code.OpNoMatch:
+			return fmt.Errorf("no pattern matches %s", vm.pop().Inspect())
+
 		}
 	}
 
@@ -583,6 +1164,7 @@ func (vm *VirtualMachine) Run() error {
 func InitializeVirtualMachine(bytecode *compiler.Bytecode) *VirtualMachine {
 	mainFictional := &object.CompiledFunction{
 		Instructions: bytecode.Instructions,
+		Positions:    bytecode.Positions,
 	}
 	mainClosure := &object.Closure{
 		Fn: mainFictional,
@@ -599,9 +1181,12 @@ func InitializeVirtualMachine(bytecode *compiler.Bytecode) *VirtualMachine {
 		sp:    0,
 
 		globals: make([]object.Object, GlobalSize),
+		names:   bytecode.Globals,
 
 		frames:      frames,
 		framesIndex: 1,
+
+		random: object.InitializeRandom(),
 	}
 }
 
@@ -612,3 +1197,11 @@ func InitializeWithGlobalStore(bytecode *compiler.Bytecode, s []object.Object) *
 
 	return vm
 }
+
+// InitializeWithState : keeps both the globals and the random number generator of previous runs, as the REPL does
+func InitializeWithState(bytecode *compiler.Bytecode, s []object.Object, random *object.Random) *VirtualMachine {
+	vm := InitializeWithGlobalStore(bytecode, s)
+	vm.random = random
+
+	return vm
+}
@@ -1,7 +1,14 @@
 package virtualmachine
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"../ast"
@@ -82,6 +89,15 @@ func testExpectedObject(t *testing.T, expected interface{}, actual object.Object
 			t.Errorf("testIntegerObject failed: %s", err)
 		}
 
+	case float64:
+		result, ok := actual.(*object.Double)
+
+		if !ok {
+			t.Errorf("object is not Double, got=%T (%+v)", actual, actual)
+		} else if result.Value != expected {
+			t.Errorf("object has wrong value, got=%f, want=%f", result.Value, expected)
+		}
+
 	case bool:
 		err := testBooleanObject(bool(expected), actual)
 
@@ -94,6 +110,11 @@ func testExpectedObject(t *testing.T, expected interface{}, actual object.Object
 			t.Errorf("object is not NULL: %T (%+v)", actual, actual)
 		}
 
+	case *object.NotAvailable:
+		if actual != object.NA {
+			t.Errorf("object is not NA: %T (%+v)", actual, actual)
+		}
+
 	case string:
 		err := testStringObject(expected, actual)
 
@@ -124,6 +145,53 @@ func testExpectedObject(t *testing.T, expected interface{}, actual object.Object
 			}
 		}
 
+	case []bool:
+		array, ok := actual.(*object.Array)
+
+		if !ok || len(array.Elements) != len(expected) {
+			t.Errorf("object not Array of %d elements: %T (%+v)", len(expected), actual, actual)
+
+			return
+		}
+
+		for index, expectedElement := range expected {
+			err := testBooleanObject(expectedElement, array.Elements[index])
+
+			if nil != err {
+				t.Errorf("testBooleanObject failed: %s", err)
+			}
+		}
+
+	case []string:
+		array, ok := actual.(*object.Array)
+
+		if !ok || len(array.Elements) != len(expected) {
+			t.Errorf("object not Array of %d elements: %T (%+v)", len(expected), actual, actual)
+
+			return
+		}
+
+		for index, expectedElement := range expected {
+			err := testStringObject(expectedElement, array.Elements[index])
+
+			if nil != err {
+				t.Errorf("testStringObject failed: %s", err)
+			}
+		}
+
+	case []float64:
+		array, ok := actual.(*object.Array)
+
+		if !ok || len(array.Elements) != len(expected) {
+			t.Errorf("object not Array of %d elements: %T (%+v)", len(expected), actual, actual)
+
+			return
+		}
+
+		for index, expectedElement := range expected {
+			testExpectedObject(t, expectedElement, array.Elements[index])
+		}
+
 	case *object.Error:
 		errorObject, ok := actual.(*object.Error)
 
@@ -137,6 +205,15 @@ func testExpectedObject(t *testing.T, expected interface{}, actual object.Object
 			t.Errorf("wrong error message, expected=%q, got=%q", expected.Message, errorObject.Message)
 		}
 
+		if 0 != expected.Position.Line && errorObject.Position != expected.Position {
+			t.Errorf("wrong error position, expected=%s, got=%s", expected.Position, errorObject.Position)
+		}
+
+	case *object.Variant:
+		if variant, ok := actual.(*object.Variant); !ok || variant.Inspect() != expected.Inspect() {
+			t.Errorf("wrong variant, expected=%s, got=%T (%+v)", expected.Inspect(), actual, actual)
+		}
+
 	default:
 		t.Errorf("object not defined: %T (%+v)", actual, actual)
 	}
@@ -174,6 +251,18 @@ func runVirtualMachineTests(t *testing.T, tests []virtualMachineTestCase) {
 		virtualMachine := InitializeVirtualMachine(comp.Bytecode())
 		err = virtualMachine.Run()
 
+		// the errors no handler takes end the run
+		if failure, ok := err.(*signalled); ok {
+			if _, expected := tt.expected.(*object.Error); expected {
+				testExpectedObject(t, tt.expected, &object.Error{
+					Message:  failure.condition.Message,
+					Position: failure.condition.Position,
+				})
+
+				continue
+			}
+		}
+
 		if nil != err {
 			t.Fatalf("Virtual Machine error: %s", err)
 		}
@@ -395,6 +484,18 @@ func TestGlobalLetStatements(t *testing.T) {
 			"let one <-  1; let two <- one + one; one + two",
 			3,
 		},
+		{
+			"let total <- 1; let total <- total + 2; total",
+			3,
+		},
+		{
+			"let one = 1; 2 -> two; one + two",
+			3,
+		},
+		{
+			"let f <- function(x) { let x <- x * 2; let x <- x + 1; x }; f(3)",
+			7,
+		},
 	}
 
 	runVirtualMachineTests(t, tests)
@@ -724,15 +825,15 @@ func TestCallingFunctionsWithWrongParameters(t *testing.T) {
 	tests := []virtualMachineTestCase{
 		{
 			input:    `function() { 1 }(1)`,
-			expected: `wrong number of parameters: want=0, got=1`,
+			expected: `unused argument (1)`,
 		},
 		{
 			input:    `function(a) { a }()`,
-			expected: `wrong number of parameters: want=1, got=0`,
+			expected: `argument "a" is missing, with no default`,
 		},
 		{
 			input:    `function(a, b) { a + b }(1)`,
-			expected: `wrong number of parameters: want=2, got=1`,
+			expected: `argument "b" is missing, with no default`,
 		},
 	}
 
@@ -1064,3 +1165,2176 @@ func TestRecursiveFibonacci(t *testing.T) {
 
 	runVirtualMachineTests(t, tests)
 }
+
+// TestHashes :
+func TestHashes(t *testing.T) {
+	tests := []virtualMachineTestCase{
+		{
+			`hash("one", 1, "two", 2)["two"]`,
+			2,
+		},
+		{
+			`hash(1, 10, TRUE, 20)[TRUE]`,
+			20,
+		},
+		{
+			`hash("one", 1)["three"]`,
+			NULL,
+		},
+		{
+			`let h <- hash("one", 1); h["o" + "ne"]`,
+			1,
+		},
+		{
+			`keys(hash(3, "c", 1, "a", 2, "b"))`,
+			[]int{
+				3,
+				1,
+				2,
+			},
+		},
+		{
+			`values(hash("c", 3, "a", 1, "c", 4))`,
+			[]int{
+				4,
+				1,
+			},
+		},
+		{
+			`has_key(hash("one", 1), "one")`,
+			true,
+		},
+		{
+			`has_key(hash("one", 1), "two")`,
+			false,
+		},
+		{
+			`let h <- hash("one", 1); let other <- assoc(h, "two", 2); len(keys(h)) + other["two"]`,
+			3,
+		},
+		{
+			`hash("one")`,
+			&object.Error{
+				Message: "wrong number of parameters, got=1, want an even number",
+			},
+		},
+		{
+			`hash([1], 1)`,
+			&object.Error{
+				Message: "unusable as hash key: ARRAY",
+			},
+		},
+		{
+			`keys([1])`,
+			&object.Error{
+				Message: "parameter to `keys` must be HASH, got ARRAY",
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+// TestDoublesAndVectors :
+func TestDoublesAndVectors(t *testing.T) {
+	tests := []virtualMachineTestCase{
+		{
+			"1.5 + 1",
+			2.5,
+		},
+		{
+			"3 / 2.0",
+			1.5,
+		},
+		{
+			"-0.5 * 4",
+			-2.0,
+		},
+		{
+			"2.5 > 2",
+			true,
+		},
+		{
+			"1 < 0.5",
+			false,
+		},
+		{
+			"[1, 2, 3] * 2",
+			[]int{
+				2,
+				4,
+				6,
+			},
+		},
+		{
+			"[1, 2] + [10, 20]",
+			[]int{
+				11,
+				22,
+			},
+		},
+		{
+			"[4, 6] / [2, 3]",
+			[]int{
+				2,
+				2,
+			},
+		},
+		{
+			"7 / 0",
+			object.NA,
+		},
+		{
+			"([4, 6] / [2, 0])[1]",
+			object.NA,
+		},
+		{
+			`(data.frame("a", [4, 6])$a / 0)[0]`,
+			object.NA,
+		},
+		{
+			"(-9223372036854775807 - 1) / -1",
+			object.NA,
+		},
+		{
+			"-[1, 2]",
+			[]int{
+				-1,
+				-2,
+			},
+		},
+		{
+			"[1, 5, 3] > 2",
+			[]bool{
+				false,
+				true,
+				true,
+			},
+		},
+		{
+			`!(["a", "b"] == "a")`,
+			[]bool{
+				false,
+				true,
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+// TestDataFrames :
+func TestDataFrames(t *testing.T) {
+	frame := `let frame <- data.frame("name", ["ana", "bob", "carl"], "age", [31, 27, 45], "score", [1, 2.5, 3]);`
+
+	tests := []virtualMachineTestCase{
+		{
+			frame + "nrow(frame)",
+			3,
+		},
+		{
+			frame + "ncol(frame)",
+			3,
+		},
+		{
+			frame + `names(frame)[2]`,
+			"score",
+		},
+		{
+			frame + "frame$age",
+			[]int{
+				31,
+				27,
+				45,
+			},
+		},
+		{
+			frame + `frame["score"][0]`,
+			1.0,
+		},
+		{
+			frame + "frame[1, 0]",
+			"bob",
+		},
+		{
+			frame + `nrow(frame[[0, 2], ])`,
+			2,
+		},
+		{
+			frame + `frame[frame$age > 30, "name"][1]`,
+			"carl",
+		},
+		{
+			frame + `filter(frame, frame$age < 40)$name[1]`,
+			"bob",
+		},
+		{
+			frame + `ncol(select(frame, "age", "name"))`,
+			2,
+		},
+		{
+			frame + `mutate(frame, "double", frame$age * 2)$double`,
+			[]int{
+				62,
+				54,
+				90,
+			},
+		},
+		{
+			frame + `arrange(frame, "age")$name[0]`,
+			"bob",
+		},
+		{
+			frame + `arrange(frame, "age", TRUE)$name[0]`,
+			"carl",
+		},
+		{
+			frame + `summarise(frame, "rows", nrow(frame))$rows[0]`,
+			3,
+		},
+		{
+			frame + "frame$unknown",
+			NULL,
+		},
+		{
+			`nrow(data.frame("x", "a", "y", 1))`,
+			1,
+		},
+		{
+			`data.frame("x", [1, 2], "y", [1])`,
+			&object.Error{
+				Message: "column 'y' has 1 rows, want=2",
+			},
+		},
+		{
+			`data.frame("x", [1, "a"])`,
+			&object.Error{
+				Message: "column 'x' mixes INTEGER and STRING",
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+// TestDataFrameInspect :
+func TestDataFrameInspect(t *testing.T) {
+	program := parse(`data.frame("name", ["ana", "bob"], "age", [31, 7])`)
+	comp := compiler.InitializeCompiler()
+	err := comp.Compile(program)
+
+	if nil != err {
+		t.Fatalf("compiler error: %s", err)
+	}
+
+	virtualMachine := InitializeVirtualMachine(comp.Bytecode())
+	err = virtualMachine.Run()
+
+	if nil != err {
+		t.Fatalf("Virtual Machine error: %s", err)
+	}
+
+	expected := "  name age\n0  ana  31\n1  bob   7"
+
+	if inspected := virtualMachine.LastPoppedStackElement().Inspect(); expected != inspected {
+		t.Errorf("wrong inspect, expected=%q, got=%q", expected, inspected)
+	}
+}
+
+// TestCSV :
+func TestCSV(t *testing.T) {
+	directory, err := ioutil.TempDir("", "typer")
+
+	if nil != err {
+		t.Fatalf("could not create temporary directory: %s", err)
+	}
+
+	defer os.RemoveAll(directory)
+
+	people := filepath.Join(directory, "people.csv")
+	content := "name,age,height,member,notes\n" +
+		"ana,31,1.62,TRUE,\"likes \"\"quotes\"\"\"\n" +
+		"bob,NA,1.8,FALSE,\"two\nlines\"\n" +
+		"carl,45,,T,\n"
+
+	err = ioutil.WriteFile(people, []byte(content), 0644)
+
+	if nil != err {
+		t.Fatalf("could not write fixture: %s", err)
+	}
+
+	codes := filepath.Join(directory, "codes.csv")
+	err = ioutil.WriteFile(codes, []byte("code,label\n\"007\",\"NA\"\n\"010\",NA\n"), 0644)
+
+	if nil != err {
+		t.Fatalf("could not write fixture: %s", err)
+	}
+
+	read := fmt.Sprintf("let people <- read.csv(%q);", people)
+	copied := filepath.Join(directory, "copy.csv")
+
+	tests := []virtualMachineTestCase{
+		{
+			read + "nrow(people)",
+			3,
+		},
+		{
+			read + "people$age[0]",
+			31,
+		},
+		{
+			read + "people$age[1]",
+			object.NA,
+		},
+		{
+			read + "people$height[0]",
+			1.62,
+		},
+		{
+			read + "people$height[2]",
+			object.NA,
+		},
+		{
+			read + "people$member[2]",
+			true,
+		},
+		{
+			read + "people$notes[0]",
+			`likes "quotes"`,
+		},
+		{
+			read + "people$notes[1]",
+			"two\nlines",
+		},
+		{
+			read + "people$notes[2]",
+			"",
+		},
+		{
+			read + fmt.Sprintf("write.csv(people, %q); read.csv(%q)$notes[1]", copied, copied),
+			"two\nlines",
+		},
+		{
+			read + fmt.Sprintf("write.csv(people, %q); read.csv(%q)$height[1]", copied, copied),
+			1.8,
+		},
+		{
+			read + fmt.Sprintf("write.csv(people, %q); read.csv(%q)$notes[0]", copied, copied),
+			`likes "quotes"`,
+		},
+		{
+			fmt.Sprintf("read.csv(%q)$code[1]", codes),
+			"010",
+		},
+		{
+			fmt.Sprintf("read.csv(%q)$label[0]", codes),
+			"NA",
+		},
+		{
+			fmt.Sprintf("read.csv(%q)$label[1]", codes),
+			object.NA,
+		},
+		{
+			fmt.Sprintf("write.csv(data.frame(\"code\", [\"007\", \"1\"]), %q); read.csv(%q)$code[0]", copied, copied),
+			"007",
+		},
+		{
+			fmt.Sprintf("read.csv(%q, FALSE)$V1[0]", people),
+			"name",
+		},
+		{
+			fmt.Sprintf("read.csv(%q, TRUE, \";\")", filepath.Join(directory, "missing.csv")),
+			&object.Error{
+				Message: fmt.Sprintf("cannot open file '%s': open %s: no such file or directory", filepath.Join(directory, "missing.csv"), filepath.Join(directory, "missing.csv")),
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+
+	// strings are quoted, as R's write.csv writes them
+	written, err := ioutil.ReadFile(copied)
+
+	if nil != err || "\"code\"\n\"007\"\n\"1\"\n" != string(written) {
+		t.Errorf("wrong CSV written, got=%q (%v)", written, err)
+	}
+
+	outside, err := ioutil.TempDir("", "typer")
+
+	if nil != err {
+		t.Fatalf("could not create temporary directory: %s", err)
+	}
+
+	defer os.RemoveAll(outside)
+
+	err = ioutil.WriteFile(filepath.Join(outside, "secret.csv"), []byte(content), 0644)
+
+	if nil != err {
+		t.Fatalf("could not write fixture: %s", err)
+	}
+
+	linked := filepath.Join(directory, "linked")
+	linkedFile := filepath.Join(directory, "secret.csv")
+	dangling := filepath.Join(directory, "dangling.csv")
+
+	for link, target := range map[string]string{
+		linked:     outside,
+		linkedFile: filepath.Join(outside, "secret.csv"),
+		dangling:   filepath.Join(outside, "written.csv"),
+	} {
+		if err := os.Symlink(target, link); nil != err {
+			t.Fatalf("could not create symbolic link: %s", err)
+		}
+	}
+
+	object.Files = object.SandboxedFiles{
+		Root:     directory,
+		ReadOnly: true,
+	}
+
+	defer func() {
+		object.Files = object.AllowAllFiles{}
+	}()
+
+	tests = []virtualMachineTestCase{
+		{
+			read + "nrow(people)",
+			3,
+		},
+		{
+			read + fmt.Sprintf("write.csv(people, %q)", copied),
+			&object.Error{
+				Message: fmt.Sprintf("access to '%s' denied: sandbox is read only", copied),
+			},
+		},
+		{
+			fmt.Sprintf("read.csv(%q)", filepath.Join(directory, "..", "outside.csv")),
+			&object.Error{
+				Message: fmt.Sprintf("access to '%s' denied: outside of '%s'", filepath.Join(directory, "..", "outside.csv"), directory),
+			},
+		},
+		{
+			fmt.Sprintf("read.csv(%q)", linkedFile),
+			&object.Error{
+				Message: fmt.Sprintf("access to '%s' denied: outside of '%s'", linkedFile, directory),
+			},
+		},
+		{
+			fmt.Sprintf("read.csv(%q)", filepath.Join(linked, "secret.csv")),
+			&object.Error{
+				Message: fmt.Sprintf("access to '%s' denied: outside of '%s'", filepath.Join(linked, "secret.csv"), directory),
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+
+	object.Files = object.SandboxedFiles{
+		Root: directory,
+	}
+
+	tests = []virtualMachineTestCase{
+		{
+			read + fmt.Sprintf("write.csv(people, %q); nrow(read.csv(%q))", copied, copied),
+			3,
+		},
+		{
+			read + fmt.Sprintf("write.csv(people, %q)", dangling),
+			&object.Error{
+				Message: fmt.Sprintf("access to '%s' denied: outside of '%s'", dangling, directory),
+			},
+		},
+		{
+			read + fmt.Sprintf("write.csv(people, %q)", filepath.Join(linked, "new.csv")),
+			&object.Error{
+				Message: fmt.Sprintf("access to '%s' denied: outside of '%s'", filepath.Join(linked, "new.csv"), directory),
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+func TestJSON(t *testing.T) {
+	directory, err := ioutil.TempDir("", "typer")
+
+	if nil != err {
+		t.Fatalf("could not create temporary directory: %s", err)
+	}
+
+	defer os.RemoveAll(directory)
+
+	orders := filepath.Join(directory, "orders.json")
+	content := `{
+		"service": "orders",
+		"count": 3,
+		"ratio": 0.5,
+		"ok": true,
+		"missing": null,
+		"ids": [1, 2, null],
+		"scores": [1, 2.5],
+		"mixed": [1, "a", null],
+		"rows": [{"id": 1, "item": "pen"}, {"id": 2, "item": "ink", "price": 1.25}]
+	}`
+
+	err = ioutil.WriteFile(orders, []byte(content), 0644)
+
+	if nil != err {
+		t.Fatalf("could not write fixture: %s", err)
+	}
+
+	read := fmt.Sprintf("let orders <- fromJSON(%q);", orders)
+
+	tests := []virtualMachineTestCase{
+		{
+			read + `orders["service"]`,
+			"orders",
+		},
+		{
+			read + `orders["count"]`,
+			3,
+		},
+		{
+			read + `orders["ratio"]`,
+			0.5,
+		},
+		{
+			read + `orders["ok"]`,
+			true,
+		},
+		{
+			read + `orders["missing"]`,
+			NULL,
+		},
+		{
+			read + `orders["ids"][2]`,
+			object.NA,
+		},
+		{
+			read + `orders["scores"][0]`,
+			1.0,
+		},
+		{
+			read + `orders["mixed"][2]`,
+			NULL,
+		},
+		{
+			read + `nrow(orders["rows"])`,
+			2,
+		},
+		{
+			read + `orders["rows"]$price[0]`,
+			object.NA,
+		},
+		{
+			read + `orders["rows"]$item[1]`,
+			"ink",
+		},
+		{
+			read + `toJSON(orders["ids"])`,
+			"[1,2,null]",
+		},
+		{
+			`toJSON(hash("a", [1, 2]), TRUE)`,
+			"{\n  \"a\": [\n    1,\n    2\n  ]\n}",
+		},
+		{
+			`let value <- hash("name", "ana", "tags", ["a", "b"], "score", 2.0); toJSON(fromJSON(toJSON(value))) == toJSON(value)`,
+			true,
+		},
+		{
+			`let frame <- data.frame("x", [1, NA], "y", ["a", "b"]); toJSON(fromJSON(toJSON(frame))) == toJSON(frame)`,
+			true,
+		},
+		{
+			`let list <- [hash("a", 1), [1, 2], NA]; toJSON(fromJSON(toJSON(list))) == toJSON(list)`,
+			true,
+		},
+		{
+			`fromJSON(toJSON([1.5, NA]))[1]`,
+			object.NA,
+		},
+		{
+			`toJSON(function(x) { x; })`,
+			&object.Error{
+				Message: "cannot convert CLOSURE_OBJECT to JSON",
+			},
+		},
+		{
+			fmt.Sprintf("fromJSON(%q)", filepath.Join(directory, "missing.json")),
+			&object.Error{
+				Message: "invalid JSON: invalid character '/' looking for beginning of value",
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+func TestHigherOrderBuiltins(t *testing.T) {
+	tests := []virtualMachineTestCase{
+		{
+			`lapply([1, 2, 3], function(x) { x * 2; })`,
+			[]int{2, 4, 6},
+		},
+		{
+			`lapply([1, 2, 3], function(x, y) { x * y; }, 2)`,
+			[]int{2, 4, 6},
+		},
+		{
+			`sapply([1, 2], function(x, by = 1, plus = 0) { x * by + plus; }, plus = 10)`,
+			[]int{11, 12},
+		},
+		{
+			`sapply(["a", "b"], paste, "z", sep = "-")`,
+			[]string{"a-z", "b-z"},
+		},
+		{
+			`vapply([1, 2], function(x, y) { x + y; }, 0, 3)`,
+			[]int{4, 5},
+		},
+		{
+			`let offset <- 10; sapply([1, 2], function(x) { [x + offset]; })`,
+			[]int{11, 12},
+		},
+		{
+			`lapply(hash("a", 1, "b", 2), function(x) { x + 1; })["b"]`,
+			3,
+		},
+		{
+			`sapply(data.frame("x", [1, 2], "y", [3, 4]), function(column) { Reduce(function(a, b) { a + b; }, column); })["y"]`,
+			7,
+		},
+		{
+			`vapply([1, 2], function(x) { x; }, 0.0)[1]`,
+			2.0,
+		},
+		{
+			`vapply([1, 2], function(x) { "a"; }, 0)`,
+			&object.Error{
+				Message: "values must be INTEGER, but FUN(X[[0]]) result is STRING",
+			},
+		},
+		{
+			`Map(function(x, y) { x + y; }, [1, 2, 3], 10)`,
+			[]int{11, 12, 13},
+		},
+		{
+			`Filter(function(x) { x > 1; }, [3, 1, NA, 2])`,
+			[]int{3, 2},
+		},
+		{
+			`Reduce(function(a, b) { a + b; }, [1, 2, 3])`,
+			6,
+		},
+		{
+			`Reduce(function(a, b) { a * b; }, [2, 3], 10)`,
+			60,
+		},
+		{
+			`Reduce(function(a, b) { a + b; }, [1, 2, 3], 0, TRUE)`,
+			[]int{0, 1, 3, 6},
+		},
+		{
+			`Reduce(function(a, b) { a + b; }, [])`,
+			NULL,
+		},
+		{
+			`Position(function(x) { x > 1; }, [1, 2, 3])`,
+			1,
+		},
+		{
+			`Position(function(x) { x > 1; }, [1, 2, 3], TRUE)`,
+			2,
+		},
+		{
+			`Position(function(x) { x > 5; }, [1, 2, 3])`,
+			object.NA,
+		},
+		{
+			`Find(function(x) { x > 1; }, [1, 2, 3])`,
+			2,
+		},
+		{
+			`Find(function(x) { x > 5; }, [1, 2, 3])`,
+			NULL,
+		},
+		{
+			`lapply([1, 2], len)`,
+			&object.Error{
+				Message: "parameters to `len` not supported, got=INTEGER",
+			},
+		},
+		{
+			`lapply([1, 2], function(x, y) { x; })`,
+			&object.Error{
+				Message: `argument "y" is missing, with no default`,
+			},
+		},
+		{
+			`Filter(function(x) { x; }, [1])`,
+			&object.Error{
+				Message: "predicate given to `Filter` must return BOOLEAN, got INTEGER",
+			},
+		},
+		{
+			`lapply([1], 2)`,
+			&object.Error{
+				Message: "function given to `lapply` must be a function, got INTEGER",
+			},
+		},
+		{
+			`let count <- function(n) { if (n > 0) { count(n - 1) + 1; } else { 0; } }; sapply([3, 200], count)`,
+			[]int{3, 200},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+func TestBuiltinCallPositions(t *testing.T) {
+	tests := []virtualMachineTestCase{
+		{
+			"len(1)",
+			&object.Error{
+				Message: "parameters to `len` not supported, got=INTEGER",
+				Position: object.Position{
+					Line:   1,
+					Column: 4,
+				},
+			},
+		},
+		{
+			"let size <- function(x) {\n  len(x);\n};\nlapply([1], size)",
+			&object.Error{
+				Message: "parameters to `len` not supported, got=INTEGER",
+				Position: object.Position{
+					Line:   2,
+					Column: 6,
+				},
+			},
+		},
+		{
+			"sapply([1],\n  head)",
+			&object.Error{
+				Message: "parameter to `head` must be ARRAY, got INTEGER",
+				Position: object.Position{
+					Line:   1,
+					Column: 7,
+				},
+			},
+		},
+		{
+			"Map(function(x) { x; },\n  [1], [2])",
+			&object.Error{
+				Message: "unused argument (2)",
+				Position: object.Position{
+					Line:   1,
+					Column: 4,
+				},
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+func TestStringBuiltins(t *testing.T) {
+	tests := []virtualMachineTestCase{
+		{
+			`paste("a", 1, TRUE, 2.5)`,
+			"a 1 TRUE 2.5",
+		},
+		{
+			`paste0("x", [1, 2], NA)`,
+			[]string{"x1NA", "x2NA"},
+		},
+		{
+			`paste0("x", [])`,
+			"x",
+		},
+		{
+			`paste("a", ["x", "y"], sep = "-")`,
+			[]string{"a-x", "a-y"},
+		},
+		{
+			`paste(["a", "b"], 1, sep = "", collapse = ", ")`,
+			"a1, b1",
+		},
+		{
+			`paste0("a", ["x", "y"], collapse = "")`,
+			"axay",
+		},
+		{
+			`sprintf("%5.2f|%-4d|%s|%03d%%", 3.14159, 7, "ok", 5)`,
+			" 3.14|7   |ok|005%",
+		},
+		{
+			`sprintf("%d items", [1, 2])`,
+			[]string{"1 items", "2 items"},
+		},
+		{
+			`sprintf("%4d", NA)`,
+			"  NA",
+		},
+		{
+			`sprintf("%d", 1.5)`,
+			&object.Error{
+				Message: "invalid format '%d'; use format %f, %e or %g for numeric objects",
+			},
+		},
+		{
+			`sprintf("%d %d", 1)`,
+			&object.Error{
+				Message: "too few arguments",
+			},
+		},
+		{
+			`sprintf("%y", 1)`,
+			&object.Error{
+				Message: "unrecognised format specification '%y'",
+			},
+		},
+		{
+			`format([1, 10, 100])`,
+			[]string{"  1", " 10", "100"},
+		},
+		{
+			`format([1.5, 10.0, NA])`,
+			[]string{" 1.5", "10.0", "  NA"},
+		},
+		{
+			`format(["a", "abc"])`,
+			[]string{"a  ", "abc"},
+		},
+		{
+			`nchar("日本語")`,
+			3,
+		},
+		{
+			`len("日本語")`,
+			3,
+		},
+		{
+			`nchar(["ab", NA, "c"])[1]`,
+			object.NA,
+		},
+		{
+			`substr("héllo", 1, 3)`,
+			"éll",
+		},
+		{
+			`substr(["hello", "hi"], 0, 2)`,
+			[]string{"hel", "hi"},
+		},
+		{
+			`substr("hello", 3, 1)`,
+			"",
+		},
+		{
+			`strsplit("a,b,,c", ",")`,
+			[]string{"a", "b", "", "c"},
+		},
+		{
+			`strsplit("abc", "")`,
+			[]string{"a", "b", "c"},
+		},
+		{
+			`strsplit(["a b", "c"], " ")[0]`,
+			[]string{"a", "b"},
+		},
+		{
+			`toupper(["straße", "ok"])`,
+			[]string{"STRAßE", "OK"},
+		},
+		{
+			`tolower("ÀB")`,
+			"àb",
+		},
+		{
+			"trimws(\"  a b \n\")",
+			"a b",
+		},
+		{
+			`trimws("  a  ", "left")`,
+			"a  ",
+		},
+		{
+			`trimws("a", "middle")`,
+			&object.Error{
+				Message: `which to ` + "`trimws`" + ` must be "both", "left" or "right", got middle`,
+			},
+		},
+		{
+			`startsWith(["apple", "banana"], "a")`,
+			[]bool{true, false},
+		},
+		{
+			`endsWith("file.csv", [".csv", ".json"])`,
+			[]bool{true, false},
+		},
+		{
+			`toupper(hash("a", 1))`,
+			&object.Error{
+				Message: "parameters to `toupper` must be atomic vectors, got HASH",
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+func TestRegularExpressions(t *testing.T) {
+	logs := `let logs <- ["INFO start", "ERROR disk full", "info done", NA];`
+
+	tests := []virtualMachineTestCase{
+		{
+			logs + `grepl("^ERROR", logs)`,
+			[]bool{false, true, false, false},
+		},
+		{
+			logs + `grepl("^info", logs, TRUE)`,
+			[]bool{true, false, true, false},
+		},
+		{
+			`grepl("a.c", ["abc", "a.c"], FALSE, FALSE, TRUE)`,
+			[]bool{false, true},
+		},
+		{
+			logs + `grep("f", logs)`,
+			[]int{1, 2},
+		},
+		{
+			logs + `grep("^[A-Z]+ ", logs, FALSE, FALSE, TRUE)`,
+			[]string{"INFO start", "ERROR disk full"},
+		},
+		{
+			`sub("(\\w+)@(\\w+)", "\\2 at \\1", "mail: ana@host and bob@box")`,
+			"mail: host at ana and bob@box",
+		},
+		{
+			`gsub("[0-9]+", "#", ["a1b22", "none"])`,
+			[]string{"a#b#", "none"},
+		},
+		{
+			`gsub(".", "$", "a.b", FALSE, FALSE, TRUE)`,
+			"a$b",
+		},
+		{
+			`sub("x", "y", NA)`,
+			object.NA,
+		},
+		{
+			`regexpr("[0-9]+", ["ab12c", "none", "日本3"])$start`,
+			[]int{2, -1, 2},
+		},
+		{
+			`regexpr("[0-9]+", ["ab12c", "none"])$length`,
+			[]int{2, -1},
+		},
+		{
+			`let x <- ["ab12c", "none", "日本34"]; regmatches(x, regexpr("[0-9]+", x))`,
+			[]string{"12", "34"},
+		},
+		{
+			`strsplit("a1b22c", "[0-9]+")`,
+			[]string{"a", "b", "c"},
+		},
+		{
+			`strsplit("a.b", ".", TRUE)`,
+			[]string{"a", "b"},
+		},
+		{
+			`grepl("(", "a")`,
+			&object.Error{
+				Message: "invalid regular expression '(', reason 'missing closing )'",
+			},
+		},
+		{
+			`grepl("a(?=b)", "ab", FALSE, TRUE)`,
+			&object.Error{
+				Message: "invalid regular expression 'a(?=b)', reason 'invalid or unsupported Perl syntax'",
+			},
+		},
+		{
+			`grepl("a", "a", "yes")`,
+			&object.Error{
+				Message: "ignore.case to `grepl` must be BOOLEAN, got STRING",
+			},
+		},
+		{
+			`regmatches("a", 1)`,
+			&object.Error{
+				Message: "m to `regmatches` must be the result of `regexpr`, got INTEGER",
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+func TestMathBuiltins(t *testing.T) {
+	tests := []virtualMachineTestCase{
+		{
+			`abs([-3, 2])`,
+			[]int{3, 2},
+		},
+		{
+			`abs(-2.5)`,
+			2.5,
+		},
+		{
+			`sqrt([16, 2])`,
+			[]float64{4, math.Sqrt(2)},
+		},
+		{
+			`exp(0)`,
+			1.0,
+		},
+		{
+			`log(1000, 10)`,
+			3.0,
+		},
+		{
+			`log(8, 2)`,
+			3.0,
+		},
+		{
+			`log([1, NA])[1]`,
+			object.NA,
+		},
+		{
+			`floor([-1.5, 1.5])`,
+			[]float64{-2, 1},
+		},
+		{
+			`ceiling(1.2)`,
+			2.0,
+		},
+		{
+			`round([0.5, 1.5, 2.5, -1.5])`,
+			[]float64{0, 2, 2, -2},
+		},
+		{
+			`round([0.15, 2.675, 1.005], 2)`,
+			[]float64{0.15, 2.67, 1},
+		},
+		{
+			`round(0.15, 1)`,
+			0.1,
+		},
+		{
+			`round(123.456, -1)`,
+			120.0,
+		},
+		{
+			`round(7, 2)`,
+			7,
+		},
+		{
+			`sum([1, 2, 3])`,
+			6,
+		},
+		{
+			`sum([0.1, 0.2, 0.3])`,
+			0.6,
+		},
+		{
+			`sum([TRUE, FALSE, TRUE])`,
+			2,
+		},
+		{
+			`sum([1, NA, 3])`,
+			object.NA,
+		},
+		{
+			`sum([1, NA, 3], na.rm = TRUE)`,
+			4,
+		},
+		{
+			`sum([1, NA, 3], TRUE)`,
+			object.NA,
+		},
+		{
+			`sum(1, 2, 3)`,
+			6,
+		},
+		{
+			`max(1, [5, 2])`,
+			5,
+		},
+		{
+			`sum(9007199254740993)`,
+			9007199254740993,
+		},
+		{
+			`range(9007199254740993, [9007199254740995, 9007199254740994])`,
+			[]int{9007199254740993, 9007199254740995},
+		},
+		{
+			`cumsum([9223372036854775806, 1])`,
+			[]int{9223372036854775806, 9223372036854775807},
+		},
+		{
+			`min([4, NA], 2.5, na.rm = TRUE)`,
+			2.5,
+		},
+		{
+			`range([3, 1], 7)`,
+			[]int{1, 7},
+		},
+		{
+			`prod(2, [3, 4])`,
+			24.0,
+		},
+		{
+			`prod([1, 2, 3, 4])`,
+			24.0,
+		},
+		{
+			`mean([0.1, 0.2, 0.3])`,
+			0.2,
+		},
+		{
+			`mean([1, 2, 3, 4])`,
+			2.5,
+		},
+		{
+			`mean([1, NA], TRUE)`,
+			1.0,
+		},
+		{
+			`median([3, 1, 2])`,
+			2,
+		},
+		{
+			`median([3, 1, 4, 2])`,
+			2.5,
+		},
+		{
+			`var([1, 2, 3, 4])`,
+			5.0 / 3,
+		},
+		{
+			`sd([2, 4, 4, 4, 5, 5, 7, 9])`,
+			math.Sqrt(32.0 / 7),
+		},
+		{
+			`var([1])`,
+			object.NA,
+		},
+		{
+			`quantile([1, 2, 3, 4, 5, 6, 7, 8, 9, 10])`,
+			[]float64{1, 3.25, 5.5, 7.75, 10},
+		},
+		{
+			`quantile([1, 3, 2, NA], [0.1, 0.5], TRUE)`,
+			[]float64{1.2, 2},
+		},
+		{
+			`quantile([1, NA])`,
+			&object.Error{
+				Message: "missing values and NaN's not allowed in `quantile` if na.rm is FALSE",
+			},
+		},
+		{
+			`cumsum([1, 2, 3])`,
+			[]int{1, 3, 6},
+		},
+		{
+			`cumsum([0.1, 0.2, 0.3])[2]`,
+			0.6,
+		},
+		{
+			`cumsum([1, NA, 3])[2]`,
+			object.NA,
+		},
+		{
+			`min([3, 1, 2])`,
+			1,
+		},
+		{
+			`max([1.5, 2])`,
+			2.0,
+		},
+		{
+			`range([3, 1, 2])`,
+			[]int{1, 3},
+		},
+		{
+			`cor([1, 2, 3, 4, 5], [2, 4, 6, 8, 10])`,
+			1.0,
+		},
+		{
+			`cor([1, 2, 3, 4], [4, 3, 2, 1])`,
+			-1.0,
+		},
+		{
+			`cor([1, 2, 3], [1, NA, 3])`,
+			object.NA,
+		},
+		{
+			`cor([1, 2, 3], [1, NA, 3], "complete.obs")`,
+			1.0,
+		},
+		{
+			`sum(["a"])`,
+			&object.Error{
+				Message: "parameters to `sum` must be numeric, got STRING inside an ARRAY",
+			},
+		},
+		{
+			`mean([1, 2], "yes")`,
+			&object.Error{
+				Message: "na.rm to `mean` must be BOOLEAN, got STRING",
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+func TestRandomNumbers(t *testing.T) {
+	tests := []virtualMachineTestCase{
+		{
+			`set.seed(42); runif(3)`,
+			[]float64{0.9148060434963554, 0.9370754132978618, 0.2861395347863436},
+		},
+		{
+			`set.seed(42); rnorm(3)`,
+			[]float64{1.3709584471466685, -0.5646981713960887, 0.3631284113373392},
+		},
+		{
+			`set.seed(123); runif(2, 10, 20)`,
+			[]float64{12.875775201246142, 17.883051354438066},
+		},
+		{
+			`set.seed(42); sample(10)`,
+			[]int{1, 5, 10, 8, 2, 4, 6, 9, 7, 3},
+		},
+		{
+			`set.seed(123); sample(["a", "b", "c", "d"], 2)`,
+			[]string{"c", "d"},
+		},
+		{
+			`set.seed(42); rbinom(5, 10, 0.5)`,
+			[]int{7, 7, 4, 7, 6},
+		},
+		{
+			`set.seed(42); let first <- runif(2); set.seed(42); runif(2)[1] == first[1]`,
+			true,
+		},
+		{
+			`runif(2, 5, 5)`,
+			[]float64{5, 5},
+		},
+		{
+			`sample([1, 2], 3)`,
+			&object.Error{
+				Message: "cannot take a sample larger than the population when replace is FALSE",
+			},
+		},
+		{
+			`rbinom(1, 10, 2)`,
+			&object.Error{
+				Message: "prob to `rbinom` must be between 0 and 1, got 2",
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+func TestRandomNumbersPerMachine(t *testing.T) {
+	run := func(input string) *VirtualMachine {
+		comp := compiler.InitializeCompiler()
+
+		if err := comp.Compile(parse(input)); nil != err {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		machine := InitializeVirtualMachine(comp.Bytecode())
+
+		if err := machine.Run(); nil != err {
+			t.Fatalf("vm error: %s", err)
+		}
+
+		return machine
+	}
+
+	first := run(`set.seed(42)`)
+	run(`set.seed(7); runif(10)`)
+
+	if value := first.random.Uniform(); 0.9148060434963554 != value {
+		t.Fatalf("the generator was shared between machines, got=%v", value)
+	}
+}
+
+func TestMatrices(t *testing.T) {
+	tests := []virtualMachineTestCase{
+		{
+			`dim(matrix([1, 2, 3, 4, 5, 6], 2))`,
+			[]int{2, 3},
+		},
+		{
+			`matrix([1, 2, 3, 4, 5, 6], 2, 3, TRUE)[1, ]`,
+			[]float64{4, 5, 6},
+		},
+		{
+			`let m <- matrix([1, 2, 3, 4, 5, 6], 2, 3); (m %*% t(m))[0, 1]`,
+			44.0,
+		},
+		{
+			`let m <- matrix([1, 2, 3, 4, 5, 6], 2, 3); (m + [10, 20])[1, ]`,
+			[]float64{22, 24, 26},
+		},
+		{
+			`let m <- matrix([1, 2, 3, 4, 5, 6], 2, 3); (2 * m - m)[, 2]`,
+			[]float64{5, 6},
+		},
+		{
+			`([1, 2] %*% [3, 4])[0, 0]`,
+			11.0,
+		},
+		{
+			`solve(matrix([2, 1, 1, 3], 2, 2), [3, 5])`,
+			[]float64{0.8, 1.4},
+		},
+		{
+			`diag(solve(matrix([4, 0, 0, 2], 2, 2)))`,
+			[]float64{0.25, 0.5},
+		},
+		{
+			`dim(diag(3))`,
+			[]int{3, 3},
+		},
+		{
+			`apply(matrix([1, 2, 3, 4, 5, 6], 2, 3), 1, sum)`,
+			[]float64{9, 12},
+		},
+		{
+			`apply(matrix([1, 2, 3, 4, 5, 6], 2, 3), 2, function(x) { x * 2; })[, 2]`,
+			[]float64{10, 12},
+		},
+		{
+			`sum(sqrt(matrix([4, 9], 1, 2)))`,
+			5.0,
+		},
+		{
+			`matrix([1, NA], 1, 2)[1]`,
+			object.NA,
+		},
+		{
+			`solve(matrix([1, 2, 2, 4], 2, 2))`,
+			&object.Error{
+				Message: "system is exactly singular: U[1,1] = 0",
+			},
+		},
+		{
+			`solve(matrix([1, 2, 3, 4, 5, 6], 2, 3))`,
+			&object.Error{
+				Message: "a to `solve` must be square, got 2x3",
+			},
+		},
+		{
+			`matrix([1, 2, 3, 4, 5], 2, 3)`,
+			&object.Error{
+				Message: "data length 5 differs from the size of the matrix, 2x3",
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+func TestMatrixErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`let m <- matrix([1, 2, 3, 4, 5, 6], 2, 3); m %*% m`,
+			"non-conformable arguments: 2x3 %*% 2x3",
+		},
+		{
+			`let m <- matrix([1, 2, 3, 4, 5, 6], 2, 3); m + t(m)`,
+			"non-conformable arrays: 2x3 + 3x2",
+		},
+		{
+			`matrix([1, 2, 3, 4, 5, 6], 2, 3) * [1, 2, 3, 4]`,
+			"vector of length 4 does not fit a 2x3 matrix",
+		},
+		{
+			`matrix([1, 2, 3, 4], 2, 2)[2, 0]`,
+			"row index out of range: 2",
+		},
+	}
+
+	for _, tt := range tests {
+		comp := compiler.InitializeCompiler()
+
+		if err := comp.Compile(parse(tt.input)); nil != err {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		err := InitializeVirtualMachine(comp.Bytecode()).Run()
+
+		if nil == err || err.Error() != tt.expected {
+			t.Errorf("wrong Virtual Machine error: want=%q, got=%v", tt.expected, err)
+		}
+	}
+}
+
+func TestPipesAndCustomOperators(t *testing.T) {
+	tests := []virtualMachineTestCase{
+		{
+			"let `%+%` <- function(a, b) { paste(a, b); }; \"a\" %+% \"b\" %+% \"c\"",
+			"a b c",
+		},
+		{
+			"let `%||%` <- function(a, b) { if (len(a) == 0) { b } else { a }; }; [] %||% [1]",
+			[]int{1},
+		},
+		{
+			`[1, 2, 3] |> cumsum() |> paste("so far")`,
+			[]string{"1 so far", "3 so far", "6 so far"},
+		},
+		{
+			`let add <- function(x, y) { x + y; }; 1 |> add(2) %>% add(3)`,
+			6,
+		},
+		{
+			`[4, 9] %>% sqrt %>% sum()`,
+			5.0,
+		},
+		{
+			"let `my total` <- 3; `my total` * 2",
+			6,
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+func TestDefaultsAndNamedArguments(t *testing.T) {
+	tests := []virtualMachineTestCase{
+		{
+			`let f <- function(x, n = 10) { x + n; }; f(1)`,
+			11,
+		},
+		{
+			`let f <- function(x, n = 10) { x + n; }; f(1, 2)`,
+			3,
+		},
+		{
+			`let f <- function(x, n = 10) { x + n; }; f(n = 1, 5)`,
+			6,
+		},
+		{
+			`let f <- function(value, number = 2) { value * number; }; f(num = 3, val = 4)`,
+			12,
+		},
+		{
+			`let f <- function(x, n = len(x)) { n; }; f([1, 2, 3])`,
+			3,
+		},
+		{
+			`let g <- function(a = b, b = 1) { a + 1; }; g()`,
+			2,
+		},
+		{
+			`let g <- function(a = b, b = 1) { a; }; g(b = 5)`,
+			5,
+		},
+		{
+			`let g <- function(k) { function(x, n = k * 2) { x + n; }; }; g(3)(1)`,
+			7,
+		},
+		{
+			`let f <- function(x, n = 2) { x * n; }; lapply([1, 2], f)`,
+			[]int{2, 4},
+		},
+		{
+			`round(digits = 1, x = 3.14159)`,
+			3.1,
+		},
+		{
+			`mean([1, NA, 3], na.rm = TRUE)`,
+			2.0,
+		},
+		{
+			`dim(matrix([1, 2, 3, 4, 5, 6], ncol = 2))`,
+			[]int{3, 2},
+		},
+		{
+			`grepl("A", ["a", "b"], fixed = FALSE, ignore.case = TRUE)`,
+			[]bool{true, false},
+		},
+		{
+			"let f <- function(x) { x; };\nround(3.2, precision = 1)",
+			&object.Error{
+				Message: "unused argument (precision = 1)",
+				Position: object.Position{
+					Line:   2,
+					Column: 6,
+				},
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+func TestNamedArgumentErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`function(x, n = 1) { x; }()`,
+			`argument "x" is missing, with no default`,
+		},
+		{
+			`function(x) { x; }(y = 1)`,
+			"unused argument (y = 1)",
+		},
+		{
+			`function(x, n = 1) { x; }(1, 2, 3)`,
+			"unused argument (3)",
+		},
+		{
+			`function(value, verbose = FALSE) { value; }(v = 1)`,
+			"argument v = 1 matches multiple formal arguments",
+		},
+		{
+			`function(x) { x; }(x = 1, x = 2)`,
+			`formal argument "x" matched by multiple actual arguments`,
+		},
+		{
+			`let g <- function(a = b, b = a) { a; }; g()`,
+			"promise already under evaluation: recursive default argument reference or earlier problems?",
+		},
+	}
+
+	for _, tt := range tests {
+		comp := compiler.InitializeCompiler()
+
+		if err := comp.Compile(parse(tt.input)); nil != err {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		err := InitializeVirtualMachine(comp.Bytecode()).Run()
+
+		if nil == err || err.Error() != tt.expected {
+			t.Errorf("wrong Virtual Machine error: want=%q, got=%v", tt.expected, err)
+		}
+	}
+}
+
+func TestVariadicFunctions(t *testing.T) {
+	tests := []virtualMachineTestCase{
+		{
+			`let f <- function(...) { len(list(...)); }; f(1, 2, 3)`,
+			3,
+		},
+		{
+			`let f <- function(x, ...) { ..2; }; f(1, 2, 3)`,
+			3,
+		},
+		{
+			`let f <- function(...) { ..1; }; f(a = 5)`,
+			5,
+		},
+		{
+			`let m <- function(x, ...) { mean(x, ...); }; m([1, NA, 3], na.rm = TRUE)`,
+			2.0,
+		},
+		{
+			`let f <- function(..., number = 1) { number; }; f(1, 2, number = 5)`,
+			5,
+		},
+		{
+			`let f <- function(..., number = 1) { len(list(...)); }; f(1, num = 2)`,
+			2,
+		},
+		{
+			`let inner <- function(a, b) { a - b; }; let outer <- function(...) { inner(...); }; outer(b = 1, a = 3)`,
+			2,
+		},
+		{
+			`let g <- function(...) { lapply(list(...), function(x) { x * 2; }); }; g(1, 2)`,
+			[]int{2, 4},
+		},
+		{
+			`list(a = 1, 2)["a"]`,
+			1,
+		},
+		{
+			`list(1, "b")[1]`,
+			"b",
+		},
+		{
+			`do.call(paste, list("a", "b"))`,
+			"a b",
+		},
+		{
+			`do.call(function(x, y) { x - y; }, list(y = 1, x = 10))`,
+			9,
+		},
+		{
+			`let minus <- function(x, y) { x - y; }; do.call("minus", list(y = 1, x = 10))`,
+			9,
+		},
+		{
+			`let f <- function() { let twice <- function(x) { x * 2; }; do.call(what = "twice", list(4)); }; f()`,
+			8,
+		},
+		{
+			`let name <- "paste0"; do.call(name, list("a", "b"))`,
+			"ab",
+		},
+		{
+			`let minus <- function(x, y) { x - y; }; let name <- "minus"; do.call(name, list(y = 1, x = 10))`,
+			9,
+		},
+		{
+			`let f <- function(name) { let twice <- function(x) { x * 2; }; do.call(name, list(4)); }; f("twice")`,
+			8,
+		},
+		{
+			`do.call("nowhere", list(1))`,
+			&object.Error{
+				Message: "could not find function \"nowhere\"",
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+func TestVariadicErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`let f <- function(...) { ..3; }; f(1)`,
+			"the ... list contains fewer than 3 elements",
+		},
+		{
+			`let f <- function(x) { ..1; }; f(1)`,
+			"..1 used in an incorrect context, no ... to look in",
+		},
+		{
+			`let f <- function(x) { list(...); }; f(1)`,
+			"'...' used in an incorrect context",
+		},
+	}
+
+	for _, tt := range tests {
+		comp := compiler.InitializeCompiler()
+		err := comp.Compile(parse(tt.input))
+
+		if nil == err {
+			err = InitializeVirtualMachine(comp.Bytecode()).Run()
+		}
+
+		if nil == err || err.Error() != tt.expected {
+			t.Errorf("wrong error: want=%q, got=%v", tt.expected, err)
+		}
+	}
+}
+
+// writeModules : the files, by name, in a temporary directory that is to be removed once the test is done
+func writeModules(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	directory, err := ioutil.TempDir("", "typer")
+
+	if nil != err {
+		t.Fatalf("could not create temporary directory: %s", err)
+	}
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(directory, name), []byte(content), 0644); nil != err {
+			os.RemoveAll(directory)
+			t.Fatalf("could not write fixture: %s", err)
+		}
+	}
+
+	return directory
+}
+
+// TestModules :
+func TestModules(t *testing.T) {
+	directory := writeModules(t, map[string]string{
+		"utils.tr": `export let double <- function(x) { x * 2 }; export scale <- 10; let hidden <- 1;
+			export let unseen <- function() { hidden }`,
+		"shapes.tr": `import { double } from "./utils.tr"; export let area <- function(side) { double(side) * side }`,
+		"random.tr": `export let draw <- runif(1)`,
+		"legacy.R":  "# written in R\nsquare <- function(x) x * x\ntotal = function(xs, by = 2) {\n  s <- 0\n  s <- s + xs[1] * by\n  s\n}\nc(10, 20) -> v\n",
+	})
+
+	defer os.RemoveAll(directory)
+
+	from := func(name string) string {
+		return fmt.Sprintf("%q", filepath.Join(directory, name))
+	}
+
+	tests := []virtualMachineTestCase{
+		{
+			"import { double, scale } from " + from("utils.tr") + "; double(scale)",
+			20,
+		},
+		{
+			"import { area } from " + from("shapes.tr") + "; area(3)",
+			18,
+		},
+		{
+			"let hidden <- 5; import { unseen } from " + from("utils.tr") + "; unseen() + hidden",
+			6,
+		},
+		{
+			"import { draw } from " + from("random.tr") + "; let first <- draw; import { draw } from " + from("random.tr") + "; first[0] == draw[0]",
+			true,
+		},
+		{
+			"import { area } from " + from("shapes.tr") + "; import { double } from " + from("utils.tr") + "; double(area(1))",
+			4,
+		},
+		{
+			"import { square, total, v } from " + from("legacy.R") + "; square(v[1]) + total([5])",
+			410,
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+// TestModuleErrors :
+func TestModuleErrors(t *testing.T) {
+	directory := writeModules(t, map[string]string{
+		"utils.tr":   `export let double <- function(x) { x * 2 }; let hidden <- 1`,
+		"a.tr":       `import { b } from "./b.tr"; export let a <- 1`,
+		"b.tr":       `import { a } from "./a.tr"; export let b <- 2`,
+		"broken.tr":  `let <- 1`,
+		"nested.tr":  `let f <- function() { export let x <- 1 }`,
+		"missing.tr": `import { x } from "./nowhere.tr"`,
+		"loops.R":    "total <- 0\nfor (x in xs) total <- total + x",
+	})
+
+	defer os.RemoveAll(directory)
+
+	from := func(name string) string {
+		return fmt.Sprintf("%q", filepath.Join(directory, name))
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"import { hidden } from " + from("utils.tr"),
+			"hidden is not exported by " + filepath.Join(directory, "utils.tr"),
+		},
+		{
+			"import { a } from " + from("a.tr"),
+			"import cycle: a.tr -> b.tr -> a.tr, in b.tr, in a.tr",
+		},
+		{
+			"import { x } from " + from("broken.tr"),
+			"broken.tr: Expected next token to be IDENTIFIER, got '<-' instead; no prefix parse function for '<-' was found",
+		},
+		{
+			"import { x } from " + from("nested.tr"),
+			"export is only allowed at the top level, in nested.tr",
+		},
+		{
+			"let f <- function() { import { double } from " + from("utils.tr") + " }",
+			"import is only allowed at the top level",
+		},
+		{
+			"import { total } from " + from("loops.R"),
+			"loops.R: `for` loops are not supported at line 2, column 1",
+		},
+	}
+
+	for _, tt := range tests {
+		comp := compiler.InitializeCompiler()
+		err := comp.Compile(parse(tt.input))
+
+		if nil == err || err.Error() != tt.expected {
+			t.Errorf("wrong error: want=%q, got=%v", tt.expected, err)
+		}
+	}
+
+	comp := compiler.InitializeCompiler()
+
+	if err := comp.Compile(parse("import { x } from " + from("missing.tr"))); nil == err || !strings.HasPrefix(err.Error(), "could not load module: ") {
+		t.Errorf("expected the missing module to be reported, got=%v", err)
+	}
+}
+
+// TestDeclarations :
+func TestDeclarations(t *testing.T) {
+	directory := writeModules(t, map[string]string{
+		"base.d.tr":   "declare function nchar(x: character[]): integer[]\ndeclare function Sys.time(): double",
+		"broken.d.tr": "declare function nchar(x: character[]): integer[]; let x <- 1",
+	})
+
+	defer os.RemoveAll(directory)
+
+	from := func(name string) string {
+		return fmt.Sprintf("%q", filepath.Join(directory, name))
+	}
+
+	tests := []virtualMachineTestCase{
+		{
+			`declare function mean(x: numeric[], na.rm: logical = FALSE): double; mean([1, 2, 6])`,
+			3.0,
+		},
+		{
+			`declare function pivot_longer(data: any, ...): any; pivot_longer([1], cols = 2)`,
+			&object.Error{
+				Message: "could not find function \"pivot_longer\"",
+			},
+		},
+		{
+			`declare function pivot_longer(data: any, ...): any; let f <- function() { 1 }; f()`,
+			1,
+		},
+		{
+			"import { nchar } from " + from("base.d.tr") + "; nchar(\"abc\")",
+			3,
+		},
+		{
+			"import { Sys.time } from " + from("base.d.tr") + "; Sys.time()",
+			&object.Error{
+				Message: "could not find function \"Sys.time\"",
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+
+	errors := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"import { nchar } from " + from("broken.d.tr"),
+			"broken.d.tr: only declarations can be in a declaration file, got let x <- 1",
+		},
+		{
+			"let f <- function() { declare function g(): any }",
+			"declare is only allowed at the top level",
+		},
+	}
+
+	for _, tt := range errors {
+		comp := compiler.InitializeCompiler()
+		err := comp.Compile(parse(tt.input))
+
+		if nil == err || err.Error() != tt.expected {
+			t.Errorf("wrong error: want=%q, got=%v", tt.expected, err)
+		}
+	}
+}
+
+func TestConditions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+		printed  string
+	}{
+		{
+			`tryCatch(stop("boom"), error = function(e) { conditionMessage(e) })`,
+			"boom",
+			"",
+		},
+		{
+			`tryCatch(sapply([1, 0], function(x) { conditionMessage() }), error = function(e) { conditionMessage(e) })`,
+			"runtime error: index out of range [0] with length 0",
+			"",
+		},
+		{
+			`let f <- function() { warning("careful"); 1 }; tryCatch(f(), warning = function(w) { 2 })`,
+			2,
+			"",
+		},
+		{
+			`let f <- function() { warning("careful"); 1 }; f()`,
+			1,
+			"Warning message:\ncareful\n",
+		},
+		{
+			`let f <- function() { message("hello"); 1 }; withCallingHandlers(f(), message = function(m) { warning("seen ", conditionMessage(m)) })`,
+			1,
+			"Warning message:\nseen hello\nhello\n",
+		},
+		{
+			`let f <- function(x) { x + "a" }; tryCatch(f(1), error = function(e) { conditionMessage(e) })`,
+			"unsupported types for binary operation: INTEGER STRING",
+			"",
+		},
+		{
+			`tryCatch(stop("x"), error = function(e) { 1 }, finally = message("done"))`,
+			1,
+			"done\n",
+		},
+		{
+			`tryCatch(tryCatch(stop("inner"), warning = function(w) { 1 }, finally = message("inner done")), error = function(e) { 2 })`,
+			2,
+			"inner done\n",
+		},
+		{
+			`tryCatch(withCallingHandlers(stop("e"), error = function(e) { message("logged") }), error = function(e) { 3 })`,
+			3,
+			"logged\n",
+		},
+		{
+			`tryCatch(tryCatch(stop("deep"), error = function(e) { stop(e) }), condition = function(e) { conditionMessage(e) })`,
+			"deep",
+			"",
+		},
+		{
+			`tryCatch(sapply([1, 2], function(x) { stop("at ", x) }), error = function(e) { conditionMessage(e) })`,
+			"at 1",
+			"",
+		},
+		{
+			`let f <- function() { tryCatch(1, finally = message("left")) }; f() + tryCatch(2)`,
+			3,
+			"left\n",
+		},
+		{
+			`let tryCatch <- function(x) { x * 2 }; tryCatch(2)`,
+			4,
+			"",
+		},
+		{
+			`cumsum([9223372036854775806, 1, 1])[2]`,
+			object.NA,
+			"Warning message:\ninteger overflow in 'cumsum'; use 'cumsum(as.numeric(.))'\n",
+		},
+		{
+			`tryCatch(sum(9223372036854775806, 2), warning = function(w) { conditionMessage(w) })`,
+			"integer overflow - use sum(as.numeric(.))",
+			"",
+		},
+	}
+
+	defer func(messages io.Writer) {
+		object.Messages = messages
+	}(object.Messages)
+
+	for _, tt := range tests {
+		var printed bytes.Buffer
+		object.Messages = &printed
+
+		comp := compiler.InitializeCompiler()
+
+		if err := comp.Compile(parse(tt.input)); nil != err {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		virtualMachine := InitializeVirtualMachine(comp.Bytecode())
+
+		if err := virtualMachine.Run(); nil != err {
+			t.Fatalf("Virtual Machine error for %q: %s", tt.input, err)
+		}
+
+		testExpectedObject(t, tt.expected, virtualMachine.LastPoppedStackElement())
+
+		if printed.String() != tt.printed {
+			t.Errorf("wrong messages for %q, expected=%q, got=%q", tt.input, tt.printed, printed.String())
+		}
+	}
+
+	errors := []struct {
+		input    string
+		expected string
+		printed  string
+	}{
+		{
+			`stop("a", "b", 1)`,
+			"ab1",
+			"",
+		},
+		{
+			`let f <- function() { tryCatch(stop("x"), finally = message("done")) }; f()`,
+			"x",
+			"done\n",
+		},
+		{
+			`withCallingHandlers(1 + "a", error = function(e) { message("seen ", conditionMessage(e)) })`,
+			"unsupported types for binary operation: INTEGER STRING",
+			"seen unsupported types for binary operation: INTEGER STRING\n",
+		},
+		{
+			`sqrt("a"); message("not reached")`,
+			"parameters to `sqrt` must be numeric, got STRING, at line 1, column 5",
+			"",
+		},
+		{
+			`let f <- function(x) { let y <- sqrt(x); y + 1 }; f("a"); message("not reached")`,
+			"parameters to `sqrt` must be numeric, got STRING, at line 1, column 37",
+			"",
+		},
+		{
+			`type Shape = Circle(r: double); match (Circle(1.0, 2.0)) { _ -> 9 }`,
+			"wrong number of parameters to `Circle`, got=2, want=1, at line 1, column 46",
+			"",
+		},
+	}
+
+	for _, tt := range errors {
+		var printed bytes.Buffer
+		object.Messages = &printed
+
+		comp := compiler.InitializeCompiler()
+
+		if err := comp.Compile(parse(tt.input)); nil != err {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		err := InitializeVirtualMachine(comp.Bytecode()).Run()
+
+		if nil == err || err.Error() != tt.expected {
+			t.Errorf("wrong Virtual Machine error for %q: want=%q, got=%v", tt.input, tt.expected, err)
+		}
+
+		if printed.String() != tt.printed {
+			t.Errorf("wrong messages for %q, expected=%q, got=%q", tt.input, tt.printed, printed.String())
+		}
+	}
+
+	comp := compiler.InitializeCompiler()
+	err := comp.Compile(parse(`tryCatch(1, other = 2)`))
+	expected := "tryCatch takes handlers for conditions of class error, warning, message or condition, got other"
+
+	if nil == err || err.Error() != expected {
+		t.Errorf("wrong compiler error: want=%q, got=%v", expected, err)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	half := "let half <- function(x) { if (x > 0) { Some(x / 2) } else { None() } }; "
+
+	tests := []virtualMachineTestCase{
+		{
+			half + "map(half(8), function(x) { x + 1 })",
+			&object.Variant{Tag: "Some", Values: []object.Object{&object.Integer{Value: 5}}},
+		},
+		{
+			half + "and_then(half(8), half)",
+			&object.Variant{Tag: "Some", Values: []object.Object{&object.Integer{Value: 2}}},
+		},
+		{
+			half + "map(half(-1), half)",
+			&object.Variant{Tag: "None"},
+		},
+		{
+			`unwrap_or(and_then(Ok(1), function(x) { Err("too small") }), 10)`,
+			10,
+		},
+		{
+			`unwrap_or(o = Ok(3), default = 10)`,
+			3,
+		},
+		{
+			`map(Err("boom"), function(x) { x })`,
+			&object.Variant{Tag: "Err", Values: []object.Object{&object.String{Value: "boom"}}},
+		},
+		{
+			`unwrap_or(1, 0)`,
+			&object.Error{
+				Message: "parameter to `unwrap_or` must be OPTION or RESULT, got INTEGER",
+			},
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+}
+
+func TestAlgebraicTypes(t *testing.T) {
+	shape := "type Shape = Circle(r: double) | Rect(w: double, h: double) | Empty; " +
+		"let area <- function(s) { match (s) { Circle(r) -> 3 * r * r, Rect(w, h) -> { w * h }, Empty -> 0 } }; "
+
+	tests := []virtualMachineTestCase{
+		{
+			shape + "area(Circle(2))",
+			12,
+		},
+		{
+			shape + "area(Rect(h = 3, w = 2))",
+			6,
+		},
+		{
+			shape + "area(Empty())",
+			0,
+		},
+		{
+			shape + "Rect(1, 2)",
+			&object.Variant{Tag: "Rect", Values: []object.Object{&object.Integer{Value: 1}, &object.Integer{Value: 2}}},
+		},
+		{
+			`match (Some(2)) { None -> 0, Some(_) -> 7 }`,
+			7,
+		},
+		{
+			`match (Err("boom")) { Ok(x) -> x, _ -> 1 }`,
+			1,
+		},
+		{
+			`let f <- function(o) { match (o) { Some(x) -> { let y <- x + 1; y }, None -> {} } }; f(None())`,
+			NULL,
+		},
+		{
+			`type Numbers = integer[]; let first <- function<T>(xs: T[]): T { xs[0] }; first([3, 4])`,
+			3,
+		},
+		{
+			`type Tree<T: numeric> = Leaf(value: T) | Node(left: Tree[T], right: Tree[T]); match (Node(Leaf(1), Leaf(2))) { Node(l, _) -> match (l) { Leaf(v) -> v, _ -> 0 }, _ -> 0 }`,
+			1,
+		},
+	}
+
+	runVirtualMachineTests(t, tests)
+
+	failures := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`match (None()) { Some(x) -> x }`,
+			"no pattern matches None()",
+		},
+		{
+			`match (Some(1)) { Some(x, y) -> x }`,
+			"pattern Some takes 1 fields, got=2",
+		},
+	}
+
+	for _, tt := range failures {
+		comp := compiler.InitializeCompiler()
+
+		if err := comp.Compile(parse(tt.input)); nil != err {
+			t.Fatalf("compiler error: %s", err)
+		}
+
+		err := InitializeVirtualMachine(comp.Bytecode()).Run()
+
+		if nil == err || err.Error() != tt.expected {
+			t.Errorf("wrong Virtual Machine error for %q: want=%q, got=%v", tt.input, tt.expected, err)
+		}
+	}
+
+	comp := compiler.InitializeCompiler()
+	err := comp.Compile(parse(shape + `r <- 1; match (Circle(2.0)) { Circle(r) -> r }`))
+	expected := "overwrite previously defined value 'r' is not allowed"
+
+	if nil == err || err.Error() != expected {
+		t.Errorf("wrong compiler error: want=%q, got=%v", expected, err)
+	}
+}